@@ -1,68 +1,414 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/admin"
+	"github.com/BenasB/bx2cloud/internal/api/apply"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/config"
 	"github.com/BenasB/bx2cloud/internal/api/container"
 	"github.com/BenasB/bx2cloud/internal/api/container/images"
 	"github.com/BenasB/bx2cloud/internal/api/container/logs"
+	"github.com/BenasB/bx2cloud/internal/api/gateway"
+	"github.com/BenasB/bx2cloud/internal/api/gc"
+	"github.com/BenasB/bx2cloud/internal/api/health"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
 	"github.com/BenasB/bx2cloud/internal/api/introspection"
+	"github.com/BenasB/bx2cloud/internal/api/logging"
+	"github.com/BenasB/bx2cloud/internal/api/metrics"
 	"github.com/BenasB/bx2cloud/internal/api/network"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/rbac"
+	"github.com/BenasB/bx2cloud/internal/api/reconciler"
+	"github.com/BenasB/bx2cloud/internal/api/shutdown"
 	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
 	"github.com/BenasB/bx2cloud/internal/api/subnetwork/ipam"
+	"github.com/BenasB/bx2cloud/internal/api/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	address := ":8080" // TODO: Make this configurable
-	lis, err := net.Listen("tcp", address)
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		fatal("Failed to load the configuration", err)
+	}
+	if err := logging.Setup(os.Stderr, cfg.Log); err != nil {
+		fatal("Failed to set up logging", err)
+	}
+
+	// Cancelled on the first signal, after which the signals are no longer caught and a second one kills the process right away
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listeners := make([]net.Listener, 0, len(cfg.Listen))
+	for _, address := range cfg.Listen {
+		lis, err := listen(address)
+		if err != nil {
+			fatal("Failed to listen", err, "address", address)
+		}
+		listeners = append(listeners, lis)
+	}
+	var gatewayListener net.Listener
+	if cfg.GatewayListen != "" {
+		gatewayListener, err = listen(cfg.GatewayListen)
+		if err != nil {
+			fatal("Failed to listen", err, "address", cfg.GatewayListen)
+		}
+	}
+	if cfg.Tracing.Enabled() {
+		shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+		if err != nil {
+			fatal("Failed to set up tracing", err)
+		}
+		defer shutdownTracing(context.Background())
+	}
+
+	drainer := shutdown.NewDrainer()
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(logging.UnaryInterceptor(), metrics.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(logging.StreamInterceptor(), metrics.StreamInterceptor(), drainer.StreamInterceptor()),
+		// Starts a span for every call, or continues the client's trace, that the services add their spans to
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	}
+	var tlsConfig *tls.Config
+	if cfg.TLS.Enabled() {
+		tlsConfig, err = auth.ServerTLSConfig(cfg.TLS)
+		if err != nil {
+			fatal("Failed to set up TLS", err)
+		}
+	} else {
+		slog.Warn("TLS is disabled, traffic to TCP addresses is not encrypted")
+	}
+	if cfg.TokenFile != "" {
+		tokens, err := auth.LoadTokens(cfg.TokenFile)
+		if err != nil {
+			fatal("Failed to load the tokens", err)
+		}
+		authenticator := auth.NewTokenAuthenticator(tokens)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(authenticator.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(authenticator.StreamInterceptor()),
+		)
+	} else {
+		slog.Warn("Token authentication is disabled, anyone who can reach the API can use it")
+	}
+
+	projectRepository, err := project.NewFileRepository(cfg.Paths.State)
+	if err != nil {
+		fatal("Failed to create the project repository", err)
+	}
+	// Runs after authentication, since tokens can be bound to a project
+	projectResolver := project.NewResolver(projectRepository)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(projectResolver.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(projectResolver.StreamInterceptor()),
+	)
+	var authorizer interfaces.Authorizer
+	if cfg.PolicyFile != "" {
+		policy, err := rbac.LoadPolicy(cfg.PolicyFile)
+		if err != nil {
+			fatal("Failed to load the access policy", err)
+		}
+		// Runs after project resolution, since roles can be limited to projects
+		policyAuthorizer := rbac.NewAuthorizer(policy)
+		authorizer = policyAuthorizer
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(policyAuthorizer.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(policyAuthorizer.StreamInterceptor()),
+		)
+	} else {
+		slog.Warn("No access policy is loaded, access control is off and every caller can call every method")
+		authorizer = rbac.NewAllowAllAuthorizer()
+	}
+
+	// Every service is registered on each of these servers
+	grpcServers := make([]*grpc.Server, 0, 2)
+	var gatewayServer interface {
+		Serve(lis net.Listener) error
+		GracefulStop()
+		Stop()
+	}
+	if gatewayListener != nil {
+		// The gateway's own gRPC server is only reachable in memory, so it gets the same interceptors without the transport credentials
+		server, err := gateway.NewServer(tlsConfig, opts...)
+		if err != nil {
+			fatal("Failed to create the HTTP gateway", err)
+		}
+		gatewayServer = server
+		grpcServers = append(grpcServers, server.Backend())
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
 	}
-	var opts []grpc.ServerOption
 	grpcServer := grpc.NewServer(opts...)
+	grpcServers = append(grpcServers, grpcServer)
 
 	ipamRepository := ipam.NewMemoryRepository()
 
-	networkRepository := network.NewMemoryRepository(make([]*interfaces.NetworkModel, 0))
-	networkConfigurator, err := network.NewNamespaceConfigurator()
+	networkRepository, err := network.NewFileRepository(cfg.Paths.State)
+	if err != nil {
+		fatal("Failed to create the network repository", err)
+	}
+	networkConfigurator, err := network.NewNamespaceConfigurator(cfg.Host)
 	if err != nil {
-		log.Fatalf("Failed to create the network configurator: %v", err)
+		fatal("Failed to create the network configurator", err)
 	}
 
-	subnetworkRepository := subnetwork.NewMemoryRepository(make([]*interfaces.SubnetworkModel, 0))
-	subnetworkConfigurator := subnetwork.NewBridgeConfigurator(networkConfigurator.GetNetworkNamespaceName, ipamRepository)
+	subnetworkRepository, err := subnetwork.NewFileRepository(cfg.Paths.State)
+	if err != nil {
+		fatal("Failed to create the subnetwork repository", err)
+	}
+	subnetworkConfigurator := subnetwork.NewBridgeConfigurator(networkConfigurator.GetNetworkNamespaceName, ipamRepository, cfg.Host.InterfacePrefix)
 
-	containerRepository, err := container.NewLibcontainerRepository()
+	containerRepository, err := container.NewLibcontainerRepository(cfg.Paths.Containers, cfg.Host.NamespacePrefix)
 	if err != nil {
-		log.Fatalf("Failed to create the container repository: %v", err)
+		fatal("Failed to create the container repository", err)
+	}
+
+	ipamIssues, err := ipam.Restore(context.Background(), ipamRepository, containerRepository, subnetworkRepository)
+	if err != nil {
+		fatal("Failed to restore IP allocations of existing containers", err)
 	}
 
 	containerConfigurator := container.NewNamespaceConfigurator(
 		networkConfigurator.GetNetworkNamespaceName,
 		subnetworkConfigurator.GetBridgeName,
 		ipamRepository,
+		cfg.Host.InterfacePrefix,
 	)
-	imagePuller, err := images.NewFlatPuller()
+
+	imagePuller, err := images.NewFlatPuller(cfg.Paths.Images)
 	if err != nil {
-		log.Fatalf("Failed to create the image puller: %v", err)
+		fatal("Failed to create the image puller", err)
 	}
 
-	containerLogger, err := logs.NewFsLogger()
+	containerLogger, err := logs.NewFsLogger(cfg.Paths.Logs)
 	if err != nil {
-		log.Fatalf("Failed to create the container logger: %v", err)
+		fatal("Failed to create the container logger", err)
+	}
+
+	garbageCollector := gc.NewCollector(
+		networkRepository,
+		subnetworkRepository,
+		containerRepository,
+		networkConfigurator,
+		subnetworkConfigurator,
+		containerConfigurator,
+		imagePuller,
+		containerLogger,
+		cfg.GcGracePeriod,
+	)
+	if _, err := garbageCollector.Collect(ctx, cfg.StartupGcDryRun); err != nil {
+		slog.Error("Startup garbage collection failed", "error", err)
 	}
 
-	pb.RegisterNetworkServiceServer(grpcServer, network.NewService(networkRepository, subnetworkRepository, networkConfigurator))
-	pb.RegisterSubnetworkServiceServer(grpcServer, subnetwork.NewService(subnetworkRepository, networkRepository, subnetworkConfigurator, ipamRepository))
-	pb.RegisterContainerServiceServer(grpcServer, container.NewService(containerRepository, subnetworkRepository, containerConfigurator, imagePuller, ipamRepository, containerLogger))
-	pb.RegisterIntrospectionServiceServer(grpcServer, introspection.NewService())
+	hostReconciler := reconciler.New(
+		networkRepository,
+		subnetworkRepository,
+		containerRepository,
+		networkConfigurator,
+		subnetworkConfigurator,
+		containerConfigurator,
+	)
+	if _, err := hostReconciler.Reconcile(ctx); err != nil {
+		slog.Error("Startup reconciliation failed", "error", err)
+	}
+	if cfg.ReconcileInterval > 0 {
+		go hostReconciler.Run(ctx, cfg.ReconcileInterval)
+	}
 
-	log.Printf("Starting server on %s", address)
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	var metricsServer *http.Server
+	if cfg.MetricsListen != "" {
+		metrics.Registry.MustRegister(metrics.NewResourceCollector(networkRepository, subnetworkRepository, containerRepository, ipamRepository))
+		metricsServer = serveMetrics(cfg.MetricsListen)
 	}
+
+	quotaChecker := quota.NewChecker(cfg.Quotas, networkRepository, subnetworkRepository, containerRepository)
+	idempotencyTracker := idempotency.NewTracker(cfg.IdempotencyRetention)
+
+	networkService := network.NewService(networkRepository, subnetworkRepository, networkConfigurator, quotaChecker, idempotencyTracker)
+	subnetworkService := subnetwork.NewService(subnetworkRepository, networkRepository, subnetworkConfigurator, ipamRepository, quotaChecker, idempotencyTracker)
+	containerService := container.NewService(containerRepository, subnetworkRepository, containerConfigurator, imagePuller, ipamRepository, containerLogger, quotaChecker, idempotencyTracker)
+	go containerService.WatchStatuses(ctx, cfg.ContainerStatusInterval)
+
+	projectService := project.NewService(projectRepository, networkRepository)
+	adminService := admin.NewService(garbageCollector)
+	introspectionService := introspection.NewService(ipamIssues, cfg, projectRepository, networkRepository, subnetworkRepository, containerRepository)
+	quotaService := quota.NewService(quotaChecker)
+	applyService := apply.NewService(
+		networkService,
+		subnetworkService,
+		containerService,
+		networkRepository,
+		subnetworkRepository,
+		containerRepository,
+		authorizer,
+	)
+
+	// The services that the gateway exposes share their state with the ones served over gRPC
+	for _, server := range grpcServers {
+		pb.RegisterProjectServiceServer(server, projectService)
+		pb.RegisterNetworkServiceServer(server, networkService)
+		pb.RegisterSubnetworkServiceServer(server, subnetworkService)
+		pb.RegisterContainerServiceServer(server, containerService)
+		pb.RegisterApplyServiceServer(server, applyService)
+		pb.RegisterQuotaServiceServer(server, quotaService)
+		pb.RegisterIntrospectionServiceServer(server, introspectionService)
+		pb.RegisterAdminServiceServer(server, adminService)
+	}
+
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	healthMonitor := health.NewMonitor(healthServer, slices.Collect(maps.Keys(grpcServer.GetServiceInfo())),
+		health.Check{
+			Name: "state directory",
+			Services: []string{
+				pb.ProjectService_ServiceDesc.ServiceName,
+				pb.NetworkService_ServiceDesc.ServiceName,
+				pb.SubnetworkService_ServiceDesc.ServiceName,
+				pb.ApplyService_ServiceDesc.ServiceName,
+			},
+			Probe: health.DirectoryWritable(cfg.Paths.State),
+		},
+		health.Check{
+			Name:     "iptables",
+			Services: []string{pb.NetworkService_ServiceDesc.ServiceName},
+			Probe:    health.IptablesUsable(),
+		},
+		health.Check{
+			Name:     "container state",
+			Services: []string{pb.ContainerService_ServiceDesc.ServiceName},
+			Probe:    health.ContainersLoadable(containerRepository),
+		},
+		health.Check{
+			Name:     "images directory",
+			Services: []string{pb.ContainerService_ServiceDesc.ServiceName},
+			Probe:    health.DirectoryWritable(cfg.Paths.Images),
+		},
+		health.Check{
+			Name:     "logs directory",
+			Services: []string{pb.ContainerService_ServiceDesc.ServiceName},
+			Probe:    health.DirectoryWritable(cfg.Paths.Logs),
+		},
+	)
+	// The first statuses are known before the server starts serving
+	healthMonitor.Update(ctx)
+	go healthMonitor.Run(ctx, cfg.HealthCheckInterval)
+
+	errs := make(chan error, len(listeners)+1)
+	for _, lis := range listeners {
+		slog.Info("Starting server", "address", lis.Addr().String())
+		go func() {
+			errs <- grpcServer.Serve(lis)
+		}()
+	}
+	if gatewayListener != nil {
+		slog.Info("Serving the HTTP gateway", "address", gatewayListener.Addr().String())
+		go func() {
+			errs <- gatewayServer.Serve(gatewayListener)
+		}()
+	}
+
+	select {
+	case err := <-errs:
+		fatal("Failed to serve", err)
+	case <-ctx.Done():
+		stop()
+	}
+
+	// Containers are separate processes, they keep running and are picked up again on the next start
+	slog.Info("Shutting down, waiting for calls to finish", "timeout", cfg.ShutdownTimeout)
+	// Load balancers stop sending new calls once every service is reported as not serving
+	healthServer.Shutdown()
+	drainer.Drain()
+	stopped := make(chan struct{})
+	go func() {
+		if gatewayServer != nil {
+			gatewayServer.GracefulStop()
+		}
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(cfg.ShutdownTimeout):
+		slog.Warn("Calls did not finish in time, cancelling them")
+		if gatewayServer != nil {
+			gatewayServer.Stop()
+		}
+		grpcServer.Stop()
+	}
+
+	if metricsServer != nil {
+		_ = metricsServer.Close()
+	}
+
+	slog.Info("Stopped")
+}
+
+// Logs the error and exits, deferred functions do not run
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append([]any{"error", err}, args...)...)
+	os.Exit(1)
+}
+
+func serveMetrics(address string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	server := &http.Server{Addr: address, Handler: mux}
+
+	slog.Info("Serving metrics", "address", address)
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to serve metrics", err)
+		}
+	}()
+
+	return server
+}
+
+// "unix:<path>" addresses are Unix sockets, a socket left behind by a previous run is replaced
+func listen(address string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(address, "unix:")
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+
+	path = strings.TrimPrefix(path, "//")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove the existing socket: %w", err)
+	}
+
+	return net.Listen("unix", path)
 }
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+)
+
+type garbageCollector interface {
+	Collect(ctx context.Context, dryRun bool) ([]*pb.Orphan, error)
+}
+
+type service struct {
+	pb.UnimplementedAdminServiceServer
+	garbageCollector garbageCollector
+}
+
+func NewService(garbageCollector garbageCollector) *service {
+	return &service{
+		garbageCollector: garbageCollector,
+	}
+}
+
+func (s *service) CollectGarbage(ctx context.Context, req *pb.GarbageCollectionRequest) (*pb.GarbageCollectionResponse, error) {
+	orphans, err := s.garbageCollector.Collect(ctx, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GarbageCollectionResponse{
+		Orphans: orphans,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// An error with a gRPC status code. It can be wrapped with more context, gRPC still finds the code and uses the full message.
+type Error struct {
+	code       codes.Code
+	message    string
+	violations []*errdetails.BadRequest_FieldViolation
+	quota      *errdetails.QuotaFailure_Violation
+	details    []protoadapt.MessageV1
+	cause      error
+}
+
+var _ interface{ GRPCStatus() *status.Status } = &Error{}
+
+// Used for sentinel errors, which are then compared with errors.Is
+func New(code codes.Code, message string) *Error {
+	return &Error{
+		code:    code,
+		message: message,
+	}
+}
+
+func NotFound(format string, args ...any) error {
+	return newf(codes.NotFound, format, args...)
+}
+
+func AlreadyExists(format string, args ...any) error {
+	return newf(codes.AlreadyExists, format, args...)
+}
+
+func FailedPrecondition(format string, args ...any) error {
+	return newf(codes.FailedPrecondition, format, args...)
+}
+
+func ResourceExhausted(format string, args ...any) error {
+	return newf(codes.ResourceExhausted, format, args...)
+}
+
+// Marks err as caused by an invalid request field, the field is reported as a field violation
+func InvalidArgument(field string, err error) error {
+	return &Error{
+		code:    codes.InvalidArgument,
+		message: err.Error(),
+		violations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: err.Error()},
+		},
+		cause: err,
+	}
+}
+
+// Marks a creation as rejected by a quota, the subject and the exceeded limit are reported as a quota violation
+func QuotaExceeded(subject string, format string, args ...any) error {
+	cause := fmt.Errorf(format, args...)
+	return &Error{
+		code:    codes.ResourceExhausted,
+		message: cause.Error(),
+		quota: &errdetails.QuotaFailure_Violation{
+			Subject:     subject,
+			Description: cause.Error(),
+		},
+		cause: cause,
+	}
+}
+
+// Attaches more details to err, which keeps its code, message and the details it already has
+func WithDetails(err error, details ...protoadapt.MessageV1) error {
+	withDetails := &Error{
+		code:    status.Code(err),
+		message: err.Error(),
+		details: details,
+		cause:   err,
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		withDetails.violations = apiErr.violations
+		withDetails.quota = apiErr.quota
+		withDetails.details = append(slices.Clone(apiErr.details), details...)
+	}
+
+	return withDetails
+}
+
+func newf(code codes.Code, format string, args ...any) error {
+	cause := fmt.Errorf(format, args...)
+	return &Error{
+		code:    code,
+		message: cause.Error(),
+		cause:   cause,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Code() codes.Code {
+	return e.code
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	s := status.New(e.code, e.message)
+
+	var details []protoadapt.MessageV1
+	if len(e.violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: e.violations})
+	}
+	if e.quota != nil {
+		details = append(details, &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{e.quota}})
+	}
+	details = append(details, e.details...)
+	if len(details) == 0 {
+		return s
+	}
+
+	withDetails, err := s.WithDetails(details...)
+	if err != nil {
+		return s
+	}
+
+	return withDetails
+}
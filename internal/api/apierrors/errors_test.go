@@ -0,0 +1,102 @@
+package apierrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errSentinel = apierrors.New(codes.Aborted, "sentinel")
+
+func TestApiErrors_Wrapped(t *testing.T) {
+	err := fmt.Errorf("failed to update: %w", apierrors.NotFound("could not find network with id %d", 3))
+
+	s, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("Expected a status, got %v", err)
+	}
+	if s.Code() != codes.NotFound {
+		t.Errorf("Expected %v, got %v", codes.NotFound, s.Code())
+	}
+	if s.Message() != "failed to update: could not find network with id 3" {
+		t.Errorf("Expected the full message, got %q", s.Message())
+	}
+
+	wrapped := fmt.Errorf("%w: expected 1", errSentinel)
+	if !errors.Is(wrapped, errSentinel) || status.Code(wrapped) != codes.Aborted {
+		t.Errorf("Expected a wrapped sentinel to keep its code, got %v", status.Code(wrapped))
+	}
+}
+
+func TestApiErrors_InvalidArgument(t *testing.T) {
+	err := apierrors.InvalidArgument("labels", fmt.Errorf("%w: key must not be empty", errSentinel))
+
+	if !errors.Is(err, errSentinel) {
+		t.Error("Expected the cause to be kept")
+	}
+
+	s := status.Convert(err)
+	if s.Code() != codes.InvalidArgument {
+		t.Errorf("Expected %v, got %v", codes.InvalidArgument, s.Code())
+	}
+
+	if len(s.Details()) != 1 {
+		t.Fatalf("Expected a single detail, got %v", s.Details())
+	}
+	badRequest, ok := s.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "labels" {
+		t.Errorf("Expected a field violation of 'labels', got %v", s.Details()[0])
+	}
+}
+
+func TestApiErrors_QuotaExceeded(t *testing.T) {
+	err := fmt.Errorf("failed to create: %w", apierrors.QuotaExceeded("project:default/containers", "at most %d containers", 5))
+
+	s := status.Convert(err)
+	if s.Code() != codes.ResourceExhausted {
+		t.Errorf("Expected %v, got %v", codes.ResourceExhausted, s.Code())
+	}
+
+	for _, detail := range s.Details() {
+		if failure, ok := detail.(*errdetails.QuotaFailure); ok {
+			if len(failure.Violations) != 1 || failure.Violations[0].Subject != "project:default/containers" {
+				t.Errorf("Expected a single violation of the containers quota, got %v", failure.Violations)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected quota failure details, got %v", s.Details())
+}
+
+func TestApiErrors_WithDetails(t *testing.T) {
+	err := apierrors.WithDetails(
+		fmt.Errorf("failed to create: %w", apierrors.QuotaExceeded("identity:/networks", "at most %d networks", 1)),
+		&errdetails.ErrorInfo{Reason: "PARTIALLY_APPLIED"},
+	)
+
+	s := status.Convert(err)
+	if s.Code() != codes.ResourceExhausted {
+		t.Errorf("Expected the code of the wrapped error, got %v", s.Code())
+	}
+	if s.Message() != "failed to create: at most 1 networks" {
+		t.Errorf("Expected the message of the wrapped error, got %q", s.Message())
+	}
+
+	var quota, info bool
+	for _, detail := range s.Details() {
+		switch detail.(type) {
+		case *errdetails.QuotaFailure:
+			quota = true
+		case *errdetails.ErrorInfo:
+			info = true
+		}
+	}
+	if !quota || !info {
+		t.Errorf("Expected the quota failure and the attached details, got %v", s.Details())
+	}
+}
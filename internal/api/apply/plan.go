@@ -0,0 +1,425 @@
+package apply
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ErrInvalidManifest = apierrors.New(codes.InvalidArgument, "invalid manifest")
+	// The plan would delete a resource that something outside of the manifest still depends on
+	ErrDependencyConflict = apierrors.New(codes.FailedPrecondition, "dependency conflict")
+)
+
+// Label that ties resources to the manifest they were applied from, so that they can be pruned
+const MANIFEST_LABEL = "bx2cloud.io/manifest"
+
+const (
+	KIND_NETWORK    = "network"
+	KIND_SUBNETWORK = "subnetwork"
+	KIND_CONTAINER  = "container"
+)
+
+// Resources of the caller's project
+type state struct {
+	networks    []*interfaces.NetworkModel
+	subnetworks []*interfaces.SubnetworkModel
+	containers  []*interfaces.ContainerModelData
+}
+
+// Resources of the request, with the manifest's label added
+type manifest struct {
+	networks    []*pb.ManifestNetwork
+	subnetworks []*pb.ManifestSubnetwork
+	containers  []*pb.ManifestContainer
+}
+
+type step struct {
+	change *pb.Change
+	// Of the existing resource, set unless it is created
+	currentVersion uint64
+	// Desired resource, the one matching the change's kind is set unless it is deleted
+	network    *pb.ManifestNetwork
+	subnetwork *pb.ManifestSubnetwork
+	container  *pb.ManifestContainer
+}
+
+type plan struct {
+	// Dependents before what they depend on
+	deletions   []*step
+	networks    []*step
+	subnetworks []*step
+	containers  []*step
+}
+
+func (p *plan) steps() []*step {
+	return slices.Concat(p.deletions, p.networks, p.subnetworks, p.containers)
+}
+
+func (p *plan) changes() []*pb.Change {
+	changes := make([]*pb.Change, 0)
+	for _, s := range p.steps() {
+		changes = append(changes, s.change)
+	}
+	return changes
+}
+
+// Full names of the methods that applying the plan calls
+func (p *plan) methods() []string {
+	methods := make([]string, 0)
+	for _, s := range p.steps() {
+		for _, method := range stepMethods(s.change) {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+func stepMethods(change *pb.Change) []string {
+	methods := map[string]map[pb.ChangeAction]string{
+		KIND_NETWORK: {
+			pb.ChangeAction_CREATE: pb.NetworkService_Create_FullMethodName,
+			pb.ChangeAction_UPDATE: pb.NetworkService_Update_FullMethodName,
+			pb.ChangeAction_DELETE: pb.NetworkService_Delete_FullMethodName,
+		},
+		KIND_SUBNETWORK: {
+			pb.ChangeAction_CREATE: pb.SubnetworkService_Create_FullMethodName,
+			pb.ChangeAction_UPDATE: pb.SubnetworkService_Update_FullMethodName,
+			pb.ChangeAction_DELETE: pb.SubnetworkService_Delete_FullMethodName,
+		},
+		KIND_CONTAINER: {
+			pb.ChangeAction_CREATE: pb.ContainerService_Create_FullMethodName,
+			pb.ChangeAction_DELETE: pb.ContainerService_Delete_FullMethodName,
+		},
+	}[change.Kind]
+
+	switch change.Action {
+	case pb.ChangeAction_CREATE, pb.ChangeAction_UPDATE, pb.ChangeAction_DELETE:
+		return []string{methods[change.Action]}
+	case pb.ChangeAction_REPLACE:
+		return []string{methods[pb.ChangeAction_DELETE], methods[pb.ChangeAction_CREATE]}
+	default:
+		return nil
+	}
+}
+
+// Validates the resources of the request and adds the manifest's label to them
+func parseManifest(req *pb.ApplyRequest) (*manifest, error) {
+	if req.Prune && req.Manifest == "" {
+		return nil, fmt.Errorf("%w: pruning requires a manifest name", ErrInvalidManifest)
+	}
+	if req.Manifest != "" {
+		if err := labels.Validate(map[string]string{MANIFEST_LABEL: req.Manifest}); err != nil {
+			return nil, fmt.Errorf("%w: the manifest name can not be a label value: %w", ErrInvalidManifest, err)
+		}
+	}
+
+	m := &manifest{}
+	names := map[string][]string{}
+	for i, resource := range req.Resources {
+		var kind, name string
+		var resourceLabels *map[string]string
+		var err error
+		switch r := resource.Resource.(type) {
+		case *pb.ManifestResource_Network:
+			kind, name, resourceLabels = KIND_NETWORK, r.Network.Name, &r.Network.Labels
+			m.networks = append(m.networks, r.Network)
+		case *pb.ManifestResource_Subnetwork:
+			kind, name, resourceLabels = KIND_SUBNETWORK, r.Subnetwork.Name, &r.Subnetwork.Labels
+			if r.Subnetwork.Network == "" {
+				err = fmt.Errorf("missing the network")
+			}
+			m.subnetworks = append(m.subnetworks, r.Subnetwork)
+		case *pb.ManifestResource_Container:
+			kind, name, resourceLabels = KIND_CONTAINER, r.Container.Name, &r.Container.Labels
+			if r.Container.Subnetwork == "" {
+				err = fmt.Errorf("missing the subnetwork")
+			} else if r.Container.Image == "" {
+				err = fmt.Errorf("missing the image")
+			}
+			m.containers = append(m.containers, r.Container)
+		default:
+			return nil, fmt.Errorf("%w: resource %d has no kind", ErrInvalidManifest, i+1)
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("%w: %s %d has no name", ErrInvalidManifest, kind, i+1)
+		}
+		if slices.Contains(names[kind], name) {
+			return nil, fmt.Errorf("%w: %s %q is defined more than once", ErrInvalidManifest, kind, name)
+		}
+		names[kind] = append(names[kind], name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %q is %w", ErrInvalidManifest, kind, name, err)
+		}
+		if err := labels.Validate(*resourceLabels); err != nil {
+			return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidManifest, kind, name, err)
+		}
+
+		if req.Manifest == "" {
+			continue
+		}
+		if value, ok := (*resourceLabels)[MANIFEST_LABEL]; ok && value != req.Manifest {
+			return nil, fmt.Errorf("%w: %s %q has a %s label of another manifest", ErrInvalidManifest, kind, name, MANIFEST_LABEL)
+		}
+		withManifest := maps.Clone(*resourceLabels)
+		if withManifest == nil {
+			withManifest = make(map[string]string)
+		}
+		withManifest[MANIFEST_LABEL] = req.Manifest
+		*resourceLabels = withManifest
+	}
+
+	return m, nil
+}
+
+// Compares the manifest with the current resources, prune also deletes the resources with the manifest's label that are not in it
+func newPlan(m *manifest, current *state, manifestName string, prune bool) (*plan, error) {
+	p := &plan{}
+	pruned := func(resourceLabels map[string]string, inManifest bool) bool {
+		return prune && !inManifest && resourceLabels[MANIFEST_LABEL] == manifestName
+	}
+
+	networks := make(map[string]*interfaces.NetworkModel)
+	networkNames := make(map[uint32]string)
+	for _, n := range current.networks {
+		networks[n.Name] = n
+		networkNames[n.Id] = n.Name
+	}
+	subnetworks := make(map[string]*interfaces.SubnetworkModel)
+	subnetworkNames := make(map[uint32]string)
+	for _, sn := range current.subnetworks {
+		subnetworks[sn.Name] = sn
+		subnetworkNames[sn.Id] = sn.Name
+	}
+
+	desiredNetworks := make(map[string]bool)
+	for _, n := range m.networks {
+		desiredNetworks[n.Name] = true
+		s := &step{change: &pb.Change{Kind: KIND_NETWORK, Name: n.Name}, network: n}
+		existing, ok := networks[n.Name]
+		if ok {
+			s.change.Id = existing.Id
+			s.currentVersion = existing.ResourceVersion
+		}
+		var replace bool
+		s.change.Fields, replace = diff(ok, []field{
+			{"internet_access", formatBool(existing.GetInternetAccess()), formatBool(n.InternetAccess), false},
+			{"labels", formatLabels(existing.GetLabels()), formatLabels(n.Labels), false},
+		})
+		s.change.Action = action(ok, len(s.change.Fields) > 0, replace)
+		p.networks = append(p.networks, s)
+	}
+
+	desiredSubnetworks := make(map[string]bool)
+	replacedSubnetworks := make(map[string]bool)
+	for _, sn := range m.subnetworks {
+		desiredSubnetworks[sn.Name] = true
+		if err := checkReference(KIND_SUBNETWORK, sn.Name, KIND_NETWORK, sn.Network, desiredNetworks[sn.Network], networks[sn.Network] != nil && !pruned(networks[sn.Network].Labels, false)); err != nil {
+			return nil, err
+		}
+
+		s := &step{change: &pb.Change{Kind: KIND_SUBNETWORK, Name: sn.Name}, subnetwork: sn}
+		existing, ok := subnetworks[sn.Name]
+		if ok {
+			s.change.Id = existing.Id
+			s.currentVersion = existing.ResourceVersion
+		}
+		var currentNetwork string
+		if ok {
+			currentNetwork = networkNames[existing.NetworkId]
+		}
+		var replace bool
+		s.change.Fields, replace = diff(ok, []field{
+			{"network", currentNetwork, sn.Network, true},
+			{"cidr", formatCidr(existing.GetAddress(), existing.GetPrefixLength(), ok), formatCidr(sn.Address, sn.PrefixLength, true), true},
+			{"labels", formatLabels(existing.GetLabels()), formatLabels(sn.Labels), false},
+		})
+		s.change.Action = action(ok, len(s.change.Fields) > 0, replace)
+		if s.change.Action == pb.ChangeAction_REPLACE {
+			replacedSubnetworks[sn.Name] = true
+		}
+		p.subnetworks = append(p.subnetworks, s)
+	}
+
+	desiredContainers := make(map[string]bool)
+	for _, c := range m.containers {
+		desiredContainers[c.Name] = true
+	}
+
+	// Existing containers are only matched by name, so their subnetworks are looked up for the diff
+	containers := make(map[string]*interfaces.ContainerModelData)
+	for _, c := range current.containers {
+		containers[c.Name] = c
+		subnetworkName := subnetworkNames[c.SubnetworkId]
+		switch {
+		case pruned(c.Labels, desiredContainers[c.Name]):
+			p.deletions = append(p.deletions, deletion(KIND_CONTAINER, c.Name, c.Id, c.ResourceVersion, "no longer in the manifest"))
+		case replacedSubnetworks[subnetworkName] && !desiredContainers[c.Name]:
+			return nil, fmt.Errorf("%w: subnetwork %q is replaced, but container %q that is not in the manifest is in it", ErrDependencyConflict, subnetworkName, c.Name)
+		}
+	}
+
+	for _, c := range m.containers {
+		if err := checkReference(KIND_CONTAINER, c.Name, KIND_SUBNETWORK, c.Subnetwork, desiredSubnetworks[c.Subnetwork], subnetworks[c.Subnetwork] != nil && !pruned(subnetworks[c.Subnetwork].Labels, false)); err != nil {
+			return nil, err
+		}
+
+		s := &step{change: &pb.Change{Kind: KIND_CONTAINER, Name: c.Name}, container: c}
+		existing, ok := containers[c.Name]
+		current := &interfaces.ContainerModelData{EntrypointCustomization: &interfaces.ContainerProcessCustomization{}}
+		var currentSubnetwork string
+		if ok {
+			s.change.Id = existing.Id
+			s.currentVersion = existing.ResourceVersion
+			current = existing
+			currentSubnetwork = subnetworkNames[existing.SubnetworkId]
+		}
+		// Containers can not be updated, so every change replaces them
+		fields, replace := diff(ok, []field{
+			{"subnetwork", currentSubnetwork, c.Subnetwork, true},
+			{"image", current.Image, c.Image, true},
+			{"entrypoint", formatList(current.EntrypointCustomization.Entrypoint), formatList(c.Entrypoint), true},
+			{"cmd", formatList(current.EntrypointCustomization.Cmd), formatList(c.Cmd), true},
+			{"env", formatList(current.EntrypointCustomization.Env), formatList(c.Env), true},
+			{"labels", formatLabels(current.Labels), formatLabels(c.Labels), true},
+		})
+		s.change.Fields = fields
+		s.change.Action = action(ok, len(fields) > 0, replace)
+		if ok && !replace && replacedSubnetworks[currentSubnetwork] {
+			s.change.Action = pb.ChangeAction_REPLACE
+			s.change.Reason = "its subnetwork is replaced"
+		}
+		p.containers = append(p.containers, s)
+	}
+
+	for _, sn := range current.subnetworks {
+		if !pruned(sn.Labels, desiredSubnetworks[sn.Name]) {
+			continue
+		}
+		for _, c := range current.containers {
+			// Containers of the manifest that are still in it are replaced, since they can not refer to a pruned subnetwork
+			if c.SubnetworkId == sn.Id && !desiredContainers[c.Name] && !pruned(c.Labels, false) {
+				return nil, fmt.Errorf("%w: subnetwork %q is pruned, but container %q is still in it", ErrDependencyConflict, sn.Name, c.Name)
+			}
+		}
+		p.deletions = append(p.deletions, deletion(KIND_SUBNETWORK, sn.Name, sn.Id, sn.ResourceVersion, "no longer in the manifest"))
+	}
+
+	for _, n := range current.networks {
+		if !pruned(n.Labels, desiredNetworks[n.Name]) {
+			continue
+		}
+		for _, sn := range current.subnetworks {
+			if sn.NetworkId == n.Id && !desiredSubnetworks[sn.Name] && !pruned(sn.Labels, false) {
+				return nil, fmt.Errorf("%w: network %q is pruned, but subnetwork %q is still in it", ErrDependencyConflict, n.Name, sn.Name)
+			}
+		}
+		p.deletions = append(p.deletions, deletion(KIND_NETWORK, n.Name, n.Id, n.ResourceVersion, "no longer in the manifest"))
+	}
+
+	return p, nil
+}
+
+func checkReference(kind, name, referencedKind, referenced string, inManifest bool, exists bool) error {
+	if inManifest || exists {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s %q refers to %s %q that is neither in the manifest nor kept", ErrInvalidManifest, kind, name, referencedKind, referenced)
+}
+
+func deletion(kind, name string, id uint32, version uint64, reason string) *step {
+	return &step{
+		change: &pb.Change{
+			Action: pb.ChangeAction_DELETE,
+			Kind:   kind,
+			Name:   name,
+			Id:     id,
+			Reason: reason,
+		},
+		currentVersion: version,
+	}
+}
+
+type field struct {
+	name    string
+	current string
+	desired string
+	// Whether changing the field replaces the resource
+	replaces bool
+}
+
+// Returns the changed fields and whether any of them replaces the resource, created resources list every set field
+func diff(exists bool, fields []field) ([]*pb.FieldChange, bool) {
+	changes := make([]*pb.FieldChange, 0)
+	replace := false
+	for _, f := range fields {
+		if exists && f.current == f.desired || !exists && f.desired == "" {
+			continue
+		}
+		change := &pb.FieldChange{Field: f.name, Desired: f.desired}
+		if exists {
+			change.Current = f.current
+			replace = replace || f.replaces
+		}
+		changes = append(changes, change)
+	}
+	return changes, replace
+}
+
+func action(exists bool, changed bool, replace bool) pb.ChangeAction {
+	switch {
+	case !exists:
+		return pb.ChangeAction_CREATE
+	case replace:
+		return pb.ChangeAction_REPLACE
+	case changed:
+		return pb.ChangeAction_UPDATE
+	default:
+		return pb.ChangeAction_UNCHANGED
+	}
+}
+
+func formatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func formatLabels(l map[string]string) string {
+	pairs := make([]string, 0, len(l))
+	for _, key := range slices.Sorted(maps.Keys(l)) {
+		pairs = append(pairs, key+"="+l[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+	return "[" + strings.Join(quoted, " ") + "]"
+}
+
+func formatCidr(address uint32, prefixLength uint32, set bool) string {
+	if !set {
+		return ""
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d/%d", byte(address>>24), byte(address>>16), byte(address>>8), byte(address), prefixLength)
+}
@@ -0,0 +1,322 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type networkService interface {
+	Create(context.Context, *pb.NetworkCreationRequest) (*pb.Network, error)
+	Update(context.Context, *pb.NetworkUpdateRequest) (*pb.Network, error)
+	Delete(context.Context, *pb.NetworkIdentificationRequest) (*emptypb.Empty, error)
+}
+
+type subnetworkService interface {
+	Create(context.Context, *pb.SubnetworkCreationRequest) (*pb.Subnetwork, error)
+	Update(context.Context, *pb.SubnetworkUpdateRequest) (*pb.Subnetwork, error)
+	Delete(context.Context, *pb.SubnetworkIdentificationRequest) (*emptypb.Empty, error)
+}
+
+type containerService interface {
+	Create(context.Context, *pb.ContainerCreationRequest) (*pb.Container, error)
+	Delete(context.Context, *pb.ContainerIdentificationRequest) (*emptypb.Empty, error)
+}
+
+// Applies manifests through the other services, so that their validation, quotas and events apply as well
+type service struct {
+	pb.UnimplementedApplyServiceServer
+	networks             networkService
+	subnetworks          subnetworkService
+	containers           containerService
+	networkRepository    interfaces.NetworkRepository
+	subnetworkRepository interfaces.SubnetworkRepository
+	containerRepository  interfaces.ContainerRepository
+	// The calls to the other services do not go through the interceptors, so the caller has to be allowed to make them here
+	authorizer interfaces.Authorizer
+	// Serializes applies in the same project, so that each one plans against the resources the previous one left behind
+	locks *shared.KeyedMutex
+}
+
+func NewService(
+	networks networkService,
+	subnetworks subnetworkService,
+	containers containerService,
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+	authorizer interfaces.Authorizer,
+) *service {
+	return &service{
+		networks:             networks,
+		subnetworks:          subnetworks,
+		containers:           containers,
+		networkRepository:    networkRepository,
+		subnetworkRepository: subnetworkRepository,
+		containerRepository:  containerRepository,
+		authorizer:           authorizer,
+		locks:                shared.NewKeyedMutex(),
+	}
+}
+
+func (s *service) Apply(ctx context.Context, req *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseManifest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.Lock(projectId)()
+
+	current, err := s.load(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newPlan(m, current, req.Manifest, req.Prune)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dry runs are checked as well, so that they show whether the plan could be applied
+	for _, method := range p.methods() {
+		if err := s.authorizer.Authorize(ctx, method); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.DryRun {
+		return &pb.ApplyResponse{Changes: p.changes()}, nil
+	}
+
+	if applied, err := s.execute(ctx, p, current); err != nil {
+		// Applying is not atomic, so the caller learns which changes stay applied from the error details
+		return nil, apierrors.WithDetails(err, &pb.ApplyResponse{Changes: applied})
+	}
+
+	return &pb.ApplyResponse{Changes: p.changes(), Applied: true}, nil
+}
+
+// Returns the resources of the project ordered by id
+func (s *service) load(ctx context.Context, projectId uint32) (*state, error) {
+	networks, err := shared.CollectAll(s.networkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	subnetworks, err := shared.CollectAll(s.subnetworkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := shared.CollectAll(s.containerRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	current := &state{}
+	for _, n := range networks {
+		if n.ProjectId == projectId {
+			current.networks = append(current.networks, n)
+		}
+	}
+	for _, sn := range subnetworks {
+		if sn.ProjectId == projectId {
+			current.subnetworks = append(current.subnetworks, sn)
+		}
+	}
+	for _, c := range containers {
+		if data := c.GetData(); data.ProjectId == projectId {
+			current.containers = append(current.containers, data)
+		}
+	}
+
+	slices.SortFunc(current.networks, func(a, b *interfaces.NetworkModel) int { return int(a.Id) - int(b.Id) })
+	slices.SortFunc(current.subnetworks, func(a, b *interfaces.SubnetworkModel) int { return int(a.Id) - int(b.Id) })
+	slices.SortFunc(current.containers, func(a, b *interfaces.ContainerModelData) int { return int(a.Id) - int(b.Id) })
+
+	return current, nil
+}
+
+// Deletes dependents before what they depend on and creates them after it.
+// Returns the changes that were made in the order they were made, also when a later change fails,
+// where a replaced resource that was deleted but not created again is a deletion.
+func (s *service) execute(ctx context.Context, p *plan, current *state) ([]*pb.Change, error) {
+	applied := make([]*pb.Change, 0)
+	// Index of the deletion of each replaced resource, which becomes the replacement once it is created again
+	replaced := make(map[*step]int)
+	done := func(st *step) {
+		if i, ok := replaced[st]; ok {
+			applied[i] = st.change
+			return
+		}
+		applied = append(applied, st.change)
+	}
+
+	for _, kind := range []string{KIND_CONTAINER, KIND_SUBNETWORK, KIND_NETWORK} {
+		for _, st := range p.steps() {
+			action := st.change.Action
+			if st.change.Kind != kind || action != pb.ChangeAction_DELETE && action != pb.ChangeAction_REPLACE {
+				continue
+			}
+			if err := s.delete(ctx, st); err != nil {
+				return applied, fmt.Errorf("failed to delete %s %q: %w", kind, st.change.Name, err)
+			}
+			if action == pb.ChangeAction_DELETE {
+				done(st)
+				continue
+			}
+			deletion := proto.CloneOf(st.change)
+			deletion.Action = pb.ChangeAction_DELETE
+			deletion.Reason = "deleted to be replaced, applying failed before it was created again"
+			replaced[st] = len(applied)
+			applied = append(applied, deletion)
+		}
+	}
+
+	// References to resources that are not in the manifest resolve to the existing ones
+	networkIds := make(map[string]uint32)
+	for _, n := range current.networks {
+		networkIds[n.Name] = n.Id
+	}
+	subnetworkIds := make(map[string]uint32)
+	for _, sn := range current.subnetworks {
+		subnetworkIds[sn.Name] = sn.Id
+	}
+
+	for _, st := range p.networks {
+		n := st.network
+		var network *pb.Network
+		var err error
+		switch st.change.Action {
+		case pb.ChangeAction_CREATE:
+			network, err = s.networks.Create(ctx, &pb.NetworkCreationRequest{
+				InternetAccess: n.InternetAccess,
+				Labels:         n.Labels,
+				Name:           n.Name,
+			})
+		case pb.ChangeAction_UPDATE:
+			network, err = s.networks.Update(ctx, &pb.NetworkUpdateRequest{
+				Identification: &pb.NetworkIdentificationRequest{
+					Identifier:      &pb.NetworkIdentificationRequest_Id{Id: st.change.Id},
+					ResourceVersion: &st.currentVersion,
+				},
+				Update: &pb.NetworkCreationRequest{
+					InternetAccess: n.InternetAccess,
+					Labels:         n.Labels,
+					Name:           n.Name,
+				},
+			})
+		default:
+			continue
+		}
+		if err != nil {
+			return applied, fmt.Errorf("failed to %s network %q: %w", verb(st), n.Name, err)
+		}
+		st.change.Id = network.Id
+		networkIds[n.Name] = network.Id
+		done(st)
+	}
+
+	for _, st := range p.subnetworks {
+		sn := st.subnetwork
+		creation := &pb.SubnetworkCreationRequest{
+			NetworkId:    networkIds[sn.Network],
+			Address:      sn.Address,
+			PrefixLength: sn.PrefixLength,
+			Labels:       sn.Labels,
+			Name:         sn.Name,
+		}
+		var subnetwork *pb.Subnetwork
+		var err error
+		switch st.change.Action {
+		case pb.ChangeAction_CREATE, pb.ChangeAction_REPLACE:
+			subnetwork, err = s.subnetworks.Create(ctx, creation)
+		case pb.ChangeAction_UPDATE:
+			subnetwork, err = s.subnetworks.Update(ctx, &pb.SubnetworkUpdateRequest{
+				Identification: &pb.SubnetworkIdentificationRequest{
+					Identifier:      &pb.SubnetworkIdentificationRequest_Id{Id: st.change.Id},
+					ResourceVersion: &st.currentVersion,
+				},
+				Update: creation,
+			})
+		default:
+			continue
+		}
+		if err != nil {
+			return applied, fmt.Errorf("failed to %s subnetwork %q: %w", verb(st), sn.Name, err)
+		}
+		st.change.Id = subnetwork.Id
+		subnetworkIds[sn.Name] = subnetwork.Id
+		done(st)
+	}
+
+	for _, st := range p.containers {
+		c := st.container
+		if st.change.Action != pb.ChangeAction_CREATE && st.change.Action != pb.ChangeAction_REPLACE {
+			continue
+		}
+		container, err := s.containers.Create(ctx, &pb.ContainerCreationRequest{
+			SubnetworkId: subnetworkIds[c.Subnetwork],
+			Image:        c.Image,
+			Entrypoint:   c.Entrypoint,
+			Cmd:          c.Cmd,
+			Env:          c.Env,
+			Labels:       c.Labels,
+			Name:         c.Name,
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to %s container %q: %w", verb(st), c.Name, err)
+		}
+		st.change.Id = container.Id
+		done(st)
+	}
+
+	return applied, nil
+}
+
+// Deletes the existing resource of a deletion or replacement
+func (s *service) delete(ctx context.Context, st *step) error {
+	var err error
+	switch st.change.Kind {
+	case KIND_NETWORK:
+		_, err = s.networks.Delete(ctx, &pb.NetworkIdentificationRequest{
+			Identifier:      &pb.NetworkIdentificationRequest_Id{Id: st.change.Id},
+			ResourceVersion: &st.currentVersion,
+		})
+	case KIND_SUBNETWORK:
+		_, err = s.subnetworks.Delete(ctx, &pb.SubnetworkIdentificationRequest{
+			Identifier:      &pb.SubnetworkIdentificationRequest_Id{Id: st.change.Id},
+			ResourceVersion: &st.currentVersion,
+		})
+	case KIND_CONTAINER:
+		_, err = s.containers.Delete(ctx, &pb.ContainerIdentificationRequest{
+			Identifier:      &pb.ContainerIdentificationRequest_Id{Id: st.change.Id},
+			ResourceVersion: &st.currentVersion,
+		})
+	}
+	return err
+}
+
+func verb(st *step) string {
+	switch st.change.Action {
+	case pb.ChangeAction_UPDATE:
+		return "update"
+	case pb.ChangeAction_REPLACE:
+		return "recreate"
+	default:
+		return "create"
+	}
+}
@@ -0,0 +1,356 @@
+package apply_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/apply"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/rbac"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork/ipam"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeContainer struct {
+	interfaces.ContainerModel
+	data *interfaces.ContainerModelData
+}
+
+func (c *fakeContainer) GetData() *interfaces.ContainerModelData {
+	return c.data
+}
+
+// Stores containers without running them
+type fakeContainers struct {
+	containers []*interfaces.ContainerModelData
+	nextId     uint32
+	// Returned by Create when set
+	createErr error
+}
+
+// Reads the containers of the fake service
+type fakeContainerRepository struct {
+	interfaces.ContainerRepository
+	service *fakeContainers
+}
+
+func (f *fakeContainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	results := make(chan interfaces.ContainerModel, len(f.service.containers))
+	errChan := make(chan error)
+	for _, c := range f.service.containers {
+		results <- &fakeContainer{data: c}
+	}
+	close(results)
+	close(errChan)
+	return results, errChan
+}
+
+func (f *fakeContainers) Create(ctx context.Context, req *pb.ContainerCreationRequest) (*pb.Container, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.nextId++
+	f.containers = append(f.containers, &interfaces.ContainerModelData{
+		Id:           f.nextId,
+		SubnetworkId: req.SubnetworkId,
+		Image:        req.Image,
+		EntrypointCustomization: &interfaces.ContainerProcessCustomization{
+			Entrypoint: req.Entrypoint,
+			Cmd:        req.Cmd,
+			Env:        req.Env,
+		},
+		ResourceVersion: 1,
+		Labels:          req.Labels,
+		Name:            req.Name,
+		ProjectId:       projectId,
+	})
+	return &pb.Container{Id: f.nextId}, nil
+}
+
+func (f *fakeContainers) Delete(ctx context.Context, req *pb.ContainerIdentificationRequest) (*emptypb.Empty, error) {
+	for i, c := range f.containers {
+		if c.Id == req.GetId() {
+			f.containers = append(f.containers[:i], f.containers[i+1:]...)
+			return &emptypb.Empty{}, nil
+		}
+	}
+	return nil, errors.New("container not found")
+}
+
+type applyFunc func(resources []*pb.ManifestResource, prune bool, dryRun bool) (*pb.ApplyResponse, error)
+
+func newService(t *testing.T) (applyFunc, *fakeContainers) {
+	t.Helper()
+
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	containers := &fakeContainers{}
+
+	service := apply.NewService(
+		network.NewService(networkRepository, subnetworkRepository, network.NewMockConfigurator(), quota.NewMockChecker(), idempotency.NewMockTracker()),
+		subnetwork.NewService(subnetworkRepository, networkRepository, subnetwork.NewMockConfigurator(), ipam.NewMemoryRepository(), quota.NewMockChecker(), idempotency.NewMockTracker()),
+		containers,
+		networkRepository,
+		subnetworkRepository,
+		&fakeContainerRepository{service: containers},
+		rbac.NewAllowAllAuthorizer(),
+	)
+
+	return func(resources []*pb.ManifestResource, prune bool, dryRun bool) (*pb.ApplyResponse, error) {
+		return service.Apply(t.Context(), &pb.ApplyRequest{
+			Resources: resources,
+			Manifest:  "env",
+			Prune:     prune,
+			DryRun:    dryRun,
+		})
+	}, containers
+}
+
+// 10.0.0.0/24 and 10.0.1.0/24
+const (
+	addressA = 0x0a000000
+	addressB = 0x0a000100
+)
+
+func manifest(address uint32, image string, networkLabels map[string]string) []*pb.ManifestResource {
+	return []*pb.ManifestResource{
+		{Resource: &pb.ManifestResource_Container{Container: &pb.ManifestContainer{Name: "web", Subnetwork: "front", Image: image}}},
+		{Resource: &pb.ManifestResource_Subnetwork{Subnetwork: &pb.ManifestSubnetwork{Name: "front", Network: "main", Address: address, PrefixLength: 24}}},
+		{Resource: &pb.ManifestResource_Network{Network: &pb.ManifestNetwork{Name: "main", Labels: networkLabels}}},
+	}
+}
+
+func actions(resp *pb.ApplyResponse) map[string]pb.ChangeAction {
+	result := make(map[string]pb.ChangeAction)
+	for _, change := range resp.Changes {
+		result[change.Kind+"/"+change.Name] = change.Action
+	}
+	return result
+}
+
+func expectActions(t *testing.T, resp *pb.ApplyResponse, expected map[string]pb.ChangeAction) {
+	t.Helper()
+
+	got := actions(resp)
+	if len(got) != len(expected) {
+		t.Errorf("expected changes %v, got %v", expected, got)
+	}
+	for resource, action := range expected {
+		if got[resource] != action {
+			t.Errorf("expected %s to be %s, got %s", resource, action, got[resource])
+		}
+	}
+}
+
+func TestApply_CreateThenUnchanged(t *testing.T) {
+	run, containers := newService(t)
+
+	resp, err := run(manifest(addressA, "nginx", nil), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Applied {
+		t.Error("expected the plan to be applied")
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main":     pb.ChangeAction_CREATE,
+		"subnetwork/front": pb.ChangeAction_CREATE,
+		"container/web":    pb.ChangeAction_CREATE,
+	})
+
+	subnetworkId := uint32(0)
+	for _, change := range resp.Changes {
+		if change.Id == 0 {
+			t.Errorf("expected created %s %q to have an id", change.Kind, change.Name)
+		}
+		if change.Kind == apply.KIND_SUBNETWORK {
+			subnetworkId = change.Id
+		}
+	}
+	if len(containers.containers) != 1 || containers.containers[0].SubnetworkId != subnetworkId {
+		t.Errorf("expected the container to be created in subnetwork %d", subnetworkId)
+	}
+
+	resp, err = run(manifest(addressA, "nginx", nil), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main":     pb.ChangeAction_UNCHANGED,
+		"subnetwork/front": pb.ChangeAction_UNCHANGED,
+		"container/web":    pb.ChangeAction_UNCHANGED,
+	})
+}
+
+func TestApply_UpdateAndReplace(t *testing.T) {
+	run, _ := newService(t)
+	if _, err := run(manifest(addressA, "nginx", nil), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := run(manifest(addressB, "nginx", map[string]string{"tier": "web"}), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main":     pb.ChangeAction_UPDATE,
+		"subnetwork/front": pb.ChangeAction_REPLACE,
+		"container/web":    pb.ChangeAction_REPLACE,
+	})
+
+	for _, change := range resp.Changes {
+		if change.Kind == apply.KIND_CONTAINER && change.Reason == "" {
+			t.Error("expected the container replacement to have a reason")
+		}
+	}
+
+	resp, err = run(manifest(addressB, "nginx", map[string]string{"tier": "web"}), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main":     pb.ChangeAction_UNCHANGED,
+		"subnetwork/front": pb.ChangeAction_UNCHANGED,
+		"container/web":    pb.ChangeAction_UNCHANGED,
+	})
+}
+
+func TestApply_FailureReportsAppliedChanges(t *testing.T) {
+	run, containers := newService(t)
+	if _, err := run(manifest(addressA, "nginx", nil), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	containers.createErr = apierrors.ResourceExhausted("no more containers")
+	_, err := run(manifest(addressB, "nginx", map[string]string{"tier": "web"}), false, false)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("expected the error of the failed change, got %v", err)
+	}
+
+	var applied *pb.ApplyResponse
+	for _, detail := range status.Convert(err).Details() {
+		if resp, ok := detail.(*pb.ApplyResponse); ok {
+			applied = resp
+		}
+	}
+	if applied == nil {
+		t.Fatal("expected the applied changes in the error details")
+	}
+
+	// The container was deleted to be replaced, but could not be created again
+	expectActions(t, applied, map[string]pb.ChangeAction{
+		"container/web":    pb.ChangeAction_DELETE,
+		"subnetwork/front": pb.ChangeAction_REPLACE,
+		"network/main":     pb.ChangeAction_UPDATE,
+	})
+	if applied.Applied {
+		t.Error("expected a failed apply not to be marked as applied")
+	}
+	if len(containers.containers) != 0 {
+		t.Errorf("expected the container to stay deleted, got %v", containers.containers)
+	}
+}
+
+func TestApply_DryRun(t *testing.T) {
+	run, containers := newService(t)
+
+	resp, err := run(manifest(addressA, "nginx", nil), false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Applied {
+		t.Error("expected a dry run not to be applied")
+	}
+	if len(containers.containers) != 0 {
+		t.Error("expected a dry run not to create containers")
+	}
+
+	resp, err = run(manifest(addressA, "nginx", nil), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions(resp)["network/main"] != pb.ChangeAction_CREATE {
+		t.Error("expected the network to be created after a dry run")
+	}
+}
+
+func TestApply_Prune(t *testing.T) {
+	run, containers := newService(t)
+	if _, err := run(manifest(addressA, "nginx", nil), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	network := manifest(addressA, "nginx", nil)[2:]
+	resp, err := run(network, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main": pb.ChangeAction_UNCHANGED,
+	})
+
+	resp, err = run(network, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectActions(t, resp, map[string]pb.ChangeAction{
+		"network/main":     pb.ChangeAction_UNCHANGED,
+		"subnetwork/front": pb.ChangeAction_DELETE,
+		"container/web":    pb.ChangeAction_DELETE,
+	})
+	if len(containers.containers) != 0 {
+		t.Error("expected the pruned container to be deleted")
+	}
+}
+
+func TestApply_InvalidManifest(t *testing.T) {
+	run, _ := newService(t)
+
+	for name, resources := range map[string][]*pb.ManifestResource{
+		"unnamed": {
+			{Resource: &pb.ManifestResource_Network{Network: &pb.ManifestNetwork{}}},
+		},
+		"duplicate": {
+			{Resource: &pb.ManifestResource_Network{Network: &pb.ManifestNetwork{Name: "main"}}},
+			{Resource: &pb.ManifestResource_Network{Network: &pb.ManifestNetwork{Name: "main"}}},
+		},
+		"unknown reference": {
+			{Resource: &pb.ManifestResource_Subnetwork{Subnetwork: &pb.ManifestSubnetwork{Name: "front", Network: "missing", Address: addressA, PrefixLength: 24}}},
+		},
+	} {
+		if _, err := run(resources, false, false); !errors.Is(err, apply.ErrInvalidManifest) {
+			t.Errorf("%s: expected %v, got %v", name, apply.ErrInvalidManifest, err)
+		}
+	}
+}
+
+func TestApply_DependencyConflict(t *testing.T) {
+	run, containers := newService(t)
+	if _, err := run(manifest(addressA, "nginx", nil), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A container that was created outside of the manifest
+	containers.containers[0].Name = "other"
+	containers.containers[0].Labels = nil
+
+	if _, err := run(manifest(addressB, "nginx", nil), false, false); !errors.Is(err, apply.ErrDependencyConflict) {
+		t.Errorf("expected %v, got %v", apply.ErrDependencyConflict, err)
+	}
+}
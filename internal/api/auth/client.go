@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Connection settings shared by the CLI and the Terraform provider, all of them are optional
+type ClientOptions struct {
+	// Uses TLS even if none of the certificate settings are set, for APIs with a certificate that the system CAs trust
+	TLS bool
+	// CA certificate that the API's certificate is verified with, the system CAs are used if it is empty
+	CAFile string
+	// Client certificate and key, for APIs that verify client certificates
+	CertFile string
+	KeyFile  string
+	Token    string
+	// Name of the project to work in, sent as metadata with every call
+	Project string
+}
+
+// TLS is used if it is turned on or any of the certificate settings are set, otherwise the connection is plaintext
+func (o *ClientOptions) DialOptions(target string) ([]grpc.DialOption, error) {
+	opts := make([]grpc.DialOption, 0)
+
+	useTls := o.TLS || o.CAFile != "" || o.CertFile != "" || o.KeyFile != ""
+	if useTls {
+		creds, err := o.transportCredentials()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if o.Token != "" {
+		// Unix sockets are only reachable from the same host, so sending the token over them in plaintext is fine
+		requireTls := !strings.HasPrefix(target, "unix:")
+		if requireTls && !useTls {
+			return nil, fmt.Errorf("a token can only be sent over TLS or a Unix socket, turn on TLS or set a CA certificate")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{
+			token:      o.Token,
+			requireTls: requireTls,
+		}))
+	}
+
+	// Errors name the request id that the API logged them with
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(logging.ClientUnaryInterceptor()),
+		grpc.WithChainStreamInterceptor(logging.ClientStreamInterceptor()),
+	)
+
+	if o.Project != "" {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+				return invoker(o.withProject(ctx), method, req, reply, cc, callOpts...)
+			}),
+			grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(o.withProject(ctx), desc, cc, method, callOpts...)
+			}),
+		)
+	}
+
+	return opts, nil
+}
+
+func (o *ClientOptions) withProject(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, PROJECT_METADATA_KEY, o.Project)
+}
+
+func (o *ClientOptions) transportCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if o.CAFile != "" {
+		pool, err := loadCertPool(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+type tokenCredentials struct {
+	token      string
+	requireTls bool
+}
+
+func (t *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		AUTHORIZATION_KEY: bearerPrefix + t.token,
+	}, nil
+}
+
+func (t *tokenCredentials) RequireTransportSecurity() bool {
+	return t.requireTls
+}
@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+var ErrUnauthenticated = apierrors.New(codes.Unauthenticated, "missing or invalid bearer token")
+
+// Metadata key of the bearer token, as in HTTP
+const AUTHORIZATION_KEY = "authorization"
+
+// Metadata key with the name of the project that the caller wants to work in
+const PROJECT_METADATA_KEY = "bx2cloud-project"
+
+const bearerPrefix = "Bearer "
+
+// Services that can be called without a token and regardless of access policies, so that load balancers can probe the API
+var PUBLIC_SERVICES = []string{healthpb.Health_ServiceDesc.ServiceName}
+
+type Token struct {
+	Value string
+	// Callers with this token can only access this project, empty if the token is not bound to a project
+	Project string
+	// Name of the caller that access policies refer to, empty if the token has no identity
+	Identity string
+}
+
+type callerKey struct{}
+
+type caller struct {
+	project  string
+	identity string
+}
+
+// Checks the bearer token of every call against a fixed set of tokens
+type tokenAuthenticator struct {
+	tokens []hashedToken
+}
+
+type hashedToken struct {
+	// Comparing hashes takes the same time no matter how long the given token is
+	hash   [sha256.Size]byte
+	caller caller
+}
+
+func NewTokenAuthenticator(tokens []Token) *tokenAuthenticator {
+	hashed := make([]hashedToken, 0, len(tokens))
+	for _, token := range tokens {
+		hashed = append(hashed, hashedToken{
+			hash: sha256.Sum256([]byte(token.Value)),
+			caller: caller{
+				project:  token.Project,
+				identity: token.Identity,
+			},
+		})
+	}
+
+	return &tokenAuthenticator{
+		tokens: hashed,
+	}
+}
+
+// Reads one "<token> [project] [identity=<name>]" per line, empty lines and lines starting with '#' are skipped.
+// The project can also be given as "project=<name>".
+func LoadTokens(path string) ([]Token, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the token file: %w", err)
+	}
+	defer f.Close()
+
+	tokens := make([]Token, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		token := Token{Value: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				key, value = "project", field
+			}
+
+			var target *string
+			switch key {
+			case "project":
+				target = &token.Project
+			case "identity":
+				target = &token.Identity
+			default:
+				return nil, fmt.Errorf("the token file %s has an unknown token attribute %q", path, key)
+			}
+			if *target != "" || value == "" {
+				return nil, fmt.Errorf("the token file %s has a token with a missing or repeated %s", path, key)
+			}
+			*target = value
+		}
+		tokens = append(tokens, token)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the token file: %w", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("the token file %s has no tokens", path)
+	}
+
+	return tokens, nil
+}
+
+func (a *tokenAuthenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if IsPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *tokenAuthenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if IsPublic(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		ctx, err := a.authenticate(stream.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, shared.WithContext(stream, ctx))
+	}
+}
+
+// Returns the context with the project and identity of the token
+func (a *tokenAuthenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AUTHORIZATION_KEY)
+	if len(values) != 1 {
+		return nil, ErrUnauthenticated
+	}
+
+	token, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	var match *hashedToken
+	for i, known := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], known.hash[:]) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+	if match == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	return context.WithValue(ctx, callerKey{}, match.caller), nil
+}
+
+// Whether the method, e.g. "/grpc.health.v1.Health/Check", belongs to one of the PUBLIC_SERVICES
+func IsPublic(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return slices.Contains(PUBLIC_SERVICES, service)
+}
+
+// Returns the project that the caller's token is bound to, or "" if it is not bound to one
+func BoundProject(ctx context.Context) string {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.project
+}
+
+// Returns the identity of the caller's token, or else the common name of its verified client certificate.
+// Returns "" for anonymous callers.
+func Identity(ctx context.Context) string {
+	if c, ok := ctx.Value(callerKey{}).(caller); ok && c.identity != "" {
+		return c.identity
+	}
+
+	if commonName, ok := ctx.Value(certificateKey{}).(string); ok {
+		return commonName
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+type certificateKey struct{}
+
+// Used by servers whose callers are verified by a proxy, such as the HTTP gateway, to pass on the common name of the caller's client certificate
+func WithCertificateName(ctx context.Context, commonName string) context.Context {
+	return context.WithValue(ctx, certificateKey{}, commonName)
+}
@@ -0,0 +1,154 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTokenAuthenticator_Unary(t *testing.T) {
+	interceptor := auth.NewTokenAuthenticator([]auth.Token{{Value: "first"}, {Value: "second"}}).UnaryInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+
+	tests := map[string]struct {
+		md   metadata.MD
+		want codes.Code
+	}{
+		"first token":    {metadata.Pairs("authorization", "Bearer first"), codes.OK},
+		"second token":   {metadata.Pairs("authorization", "Bearer second"), codes.OK},
+		"unknown token":  {metadata.Pairs("authorization", "Bearer third"), codes.Unauthenticated},
+		"missing scheme": {metadata.Pairs("authorization", "first"), codes.Unauthenticated},
+		"empty token":    {metadata.Pairs("authorization", "Bearer "), codes.Unauthenticated},
+		"no metadata":    {nil, codes.Unauthenticated},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), test.md)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if code := status.Code(err); code != test.want {
+				t.Errorf("expected %s, got %s (%v)", test.want, code, err)
+			}
+			if test.want == codes.Unauthenticated && !errors.Is(err, auth.ErrUnauthenticated) {
+				t.Errorf("expected ErrUnauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenAuthenticator_PublicMethods(t *testing.T) {
+	interceptor := auth.NewTokenAuthenticator([]auth.Token{{Value: "first"}}).UnaryInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	if err != nil {
+		t.Errorf("expected health checks to not need a token, got %v", err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/bx2cloud.NetworkService/Get"}, handler)
+	if !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	content := "# CI\nfirst\n\n  second  team-a\nthird identity=ci project=team-b\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := auth.LoadTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []auth.Token{{Value: "first"}, {Value: "second", Project: "team-a"}, {Value: "third", Project: "team-b", Identity: "ci"}}
+	if !slices.Equal(tokens, want) {
+		t.Errorf("expected %v, got %v", want, tokens)
+	}
+}
+
+func TestClientOptions_TokenRequiresTls(t *testing.T) {
+	options := &auth.ClientOptions{Token: "secret"}
+
+	if _, err := options.DialOptions("localhost:8080"); err == nil {
+		t.Error("expected an error when sending a token over plaintext TCP")
+	}
+
+	if _, err := options.DialOptions("unix:///run/bx2cloud.sock"); err != nil {
+		t.Errorf("expected a token to be allowed over a Unix socket, got %v", err)
+	}
+
+	// APIs with a publicly trusted certificate are verified with the system CAs
+	options.TLS = true
+	if _, err := options.DialOptions("api.example.com:443"); err != nil {
+		t.Errorf("expected a token to be allowed over TLS without a CA certificate, got %v", err)
+	}
+}
+
+func TestTokenAuthenticator_BoundProject(t *testing.T) {
+	interceptor := auth.NewTokenAuthenticator([]auth.Token{{Value: "global"}, {Value: "scoped", Project: "team"}}).UnaryInterceptor()
+
+	for token, want := range map[string]string{"global": "", "scoped": "team"} {
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			if got := auth.BoundProject(ctx); got != want {
+				t.Errorf("expected token %q to be bound to %q, got %q", token, want, got)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestLoadTokens_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown attribute": "first role=admin\n",
+		"two projects":      "first team-a project=team-b\n",
+		"empty identity":    "first identity=\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := auth.LoadTokens(path); err == nil {
+				t.Errorf("expected an error for %q", content)
+			}
+		})
+	}
+}
+
+func TestTokenAuthenticator_Identity(t *testing.T) {
+	interceptor := auth.NewTokenAuthenticator([]auth.Token{{Value: "anonymous"}, {Value: "named", Identity: "ci"}}).UnaryInterceptor()
+
+	for token, want := range map[string]string{"anonymous": "", "named": "ci"} {
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			if got := auth.Identity(ctx); got != want {
+				t.Errorf("expected token %q to have identity %q, got %q", token, want, got)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
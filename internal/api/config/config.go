@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+)
+
+type Config struct {
+	// Addresses to serve the API on, "unix:<path>" addresses are Unix sockets and anything else is a TCP address
+	Listen []string `yaml:"listen"`
+	Paths  Paths    `yaml:"paths"`
+	Host   Host     `yaml:"host"`
+	TLS    TLS      `yaml:"tls"`
+	// File with the bearer tokens that clients have to send, one per line, no authentication is required if it is empty
+	TokenFile string `yaml:"tokenFile"`
+	// YAML file granting callers access to API methods, every caller can call every method if it is empty
+	PolicyFile string `yaml:"policyFile"`
+	// TCP address to serve Prometheus metrics on over HTTP at /metrics, metrics are not served if it is empty
+	MetricsListen string `yaml:"metricsListen"`
+	// TCP address to serve the REST/JSON gateway of the API on, with the same TLS settings, the gateway is not served if it is empty
+	GatewayListen string  `yaml:"gatewayListen"`
+	Tracing       Tracing `yaml:"tracing"`
+	// Serves gRPC server reflection, so that clients such as grpcurl can discover the API without its .proto files
+	Reflection bool `yaml:"reflection"`
+	// How often to check the dependencies that the standard gRPC health service reports on
+	HealthCheckInterval time.Duration `yaml:"healthCheckInterval"`
+	Log                 Log           `yaml:"log"`
+	// How long to wait for calls to finish on shutdown before cancelling them
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	Quotas          Quotas        `yaml:"quotas"`
+	// How long a created resource is returned for repeated creation requests with the same idempotency key
+	IdempotencyRetention    time.Duration `yaml:"idempotencyRetention"`
+	ReconcileInterval       time.Duration `yaml:"reconcileInterval"`
+	ContainerStatusInterval time.Duration `yaml:"containerStatusInterval"`
+	// Only report the orphaned host resources found on startup instead of removing them
+	StartupGcDryRun bool `yaml:"startupGcDryRun"`
+	// How long host resources have to be orphaned before they are removed, since they may belong to a resource that is still being created
+	GcGracePeriod time.Duration `yaml:"gcGracePeriod"`
+}
+
+// Directories where the API keeps its data, separate instances on the same host need separate directories
+type Paths struct {
+	State      string `yaml:"state"`
+	Containers string `yaml:"containers"`
+	Images     string `yaml:"images"`
+	Logs       string `yaml:"logs"`
+}
+
+// Names and addresses of the host resources the API creates, separate instances on the same host need different values
+type Host struct {
+	// Prepended to the names of veths and bridges
+	InterfacePrefix string `yaml:"interfacePrefix"`
+	// Prepended to the names of network namespaces and cgroups
+	NamespacePrefix string `yaml:"namespacePrefix"`
+	// iptables chain of the filter table that isolates networks from each other
+	ForwardChain string `yaml:"forwardChain"`
+	// iptables chain of the nat table in the root namespace that gives networks internet access, routers always use POSTROUTING in their own namespace
+	NatChain string `yaml:"natChain"`
+	// Every network's router gets a /30 from this range to connect to the host
+	TransitRange string `yaml:"transitRange"`
+}
+
+// TLS is disabled if the certificate is not set
+type TLS struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// Clients have to present a certificate signed by this CA if it is set
+	ClientCAFile string `yaml:"clientCaFile"`
+}
+
+func (t *TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+const (
+	LOG_FORMAT_TEXT = "text"
+	LOG_FORMAT_JSON = "json"
+)
+
+type Log struct {
+	// "debug", "info", "warn" or "error"
+	Level string `yaml:"level"`
+	// "text" or "json"
+	Format string `yaml:"format"`
+}
+
+const (
+	TRACING_EXPORTER_OTLP   = "otlp"
+	TRACING_EXPORTER_STDOUT = "stdout"
+)
+
+// Spans are only recorded if an exporter is set
+type Tracing struct {
+	// "otlp" sends spans to an OpenTelemetry collector over gRPC, "stdout" writes them to the standard output as JSON
+	Exporter string `yaml:"exporter"`
+	// Address of the OTLP collector, OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317 is used if it is empty
+	Endpoint string `yaml:"endpoint"`
+	// Connects to the OTLP collector without TLS
+	Insecure bool `yaml:"insecure"`
+}
+
+func (t *Tracing) Enabled() bool {
+	return t.Exporter != ""
+}
+
+// Limits of the resources a single caller identity can create, 0 means unlimited
+type Limits struct {
+	Networks          int `yaml:"networks"`
+	Subnetworks       int `yaml:"subnetworks"`
+	Containers        int `yaml:"containers"`
+	RunningContainers int `yaml:"runningContainers"`
+	// IPs allocated to the identity's containers
+	Ips int `yaml:"ips"`
+}
+
+type Quotas struct {
+	// Applies to every identity, including anonymous callers
+	Default Limits `yaml:"default"`
+	// Limits of specific identities, the identity of a token or the common name of a client certificate.
+	// Each set limit replaces the default one.
+	Identities map[string]LimitOverrides `yaml:"identities"`
+}
+
+type LimitOverrides struct {
+	Networks          *int `yaml:"networks"`
+	Subnetworks       *int `yaml:"subnetworks"`
+	Containers        *int `yaml:"containers"`
+	RunningContainers *int `yaml:"runningContainers"`
+	Ips               *int `yaml:"ips"`
+}
+
+// Returns the limits of the given caller identity
+func (q *Quotas) For(identity string) Limits {
+	limits := q.Default
+	overrides, ok := q.Identities[identity]
+	if !ok {
+		return limits
+	}
+
+	for _, o := range []struct {
+		limit    *int
+		override *int
+	}{
+		{&limits.Networks, overrides.Networks},
+		{&limits.Subnetworks, overrides.Subnetworks},
+		{&limits.Containers, overrides.Containers},
+		{&limits.RunningContainers, overrides.RunningContainers},
+		{&limits.Ips, overrides.Ips},
+	} {
+		if o.override != nil {
+			*o.limit = *o.override
+		}
+	}
+
+	return limits
+}
+
+func (q *Quotas) Validate() error {
+	all := []Limits{q.Default}
+	for name := range q.Identities {
+		all = append(all, q.For(name))
+	}
+
+	for _, limits := range all {
+		if limits.Networks < 0 || limits.Subnetworks < 0 || limits.Containers < 0 || limits.RunningContainers < 0 || limits.Ips < 0 {
+			return fmt.Errorf("quota limits must not be negative, use 0 for no limit")
+		}
+	}
+
+	return nil
+}
+
+// Linux limits interface names to 15 characters, longer prefixes would not leave enough room for the ids
+const MAX_INTERFACE_PREFIX_LENGTH = 4
+
+func Default() *Config {
+	return &Config{
+		Listen: []string{":8080"},
+		Paths: Paths{
+			State:      "/var/lib/bx2cloud-state",
+			Containers: "/var/run/bx2cloud",
+			Images:     "/var/lib/bx2cloud",
+			Logs:       "/var/log/bx2cloud",
+		},
+		Host: Host{
+			InterfacePrefix: "bx2-",
+			NamespacePrefix: "bx2cloud-",
+			ForwardChain:    "FORWARD",
+			NatChain:        "POSTROUTING",
+			TransitRange:    "192.167.0.0/16",
+		},
+		Log: Log{
+			Level:  "info",
+			Format: LOG_FORMAT_TEXT,
+		},
+		HealthCheckInterval:     10 * time.Second,
+		ShutdownTimeout:         30 * time.Second,
+		IdempotencyRetention:    24 * time.Hour,
+		ReconcileInterval:       time.Minute,
+		ContainerStatusInterval: 2 * time.Second,
+		StartupGcDryRun:         true,
+		GcGracePeriod:           10 * time.Minute,
+	}
+}
+
+func (c *Config) Validate() error {
+	if len(c.Listen) == 0 {
+		return fmt.Errorf("at least one listen address is required")
+	}
+
+	for name, path := range map[string]string{
+		"state": c.Paths.State, "containers": c.Paths.Containers, "images": c.Paths.Images, "logs": c.Paths.Logs,
+	} {
+		if path == "" {
+			return fmt.Errorf("the %s path must not be empty", name)
+		}
+	}
+
+	if c.Host.InterfacePrefix == "" || len(c.Host.InterfacePrefix) > MAX_INTERFACE_PREFIX_LENGTH {
+		return fmt.Errorf("the interface prefix must be 1 to %d characters long", MAX_INTERFACE_PREFIX_LENGTH)
+	}
+
+	if c.Host.NamespacePrefix == "" {
+		return fmt.Errorf("the namespace prefix must not be empty")
+	}
+
+	if c.Host.ForwardChain == "" || c.Host.NatChain == "" {
+		return fmt.Errorf("the iptables chains must not be empty")
+	}
+
+	if _, err := c.Host.ParseTransitRange(); err != nil {
+		return err
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("the TLS certificate and key must be set together")
+	}
+
+	if c.TLS.ClientCAFile != "" && !c.TLS.Enabled() {
+		return fmt.Errorf("verifying client certificates requires a TLS certificate and key")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		return fmt.Errorf("the log level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	if c.Log.Format != LOG_FORMAT_TEXT && c.Log.Format != LOG_FORMAT_JSON {
+		return fmt.Errorf("the log format must be %q or %q, got %q", LOG_FORMAT_TEXT, LOG_FORMAT_JSON, c.Log.Format)
+	}
+
+	switch c.Tracing.Exporter {
+	case "", TRACING_EXPORTER_OTLP, TRACING_EXPORTER_STDOUT:
+	default:
+		return fmt.Errorf("the tracing exporter must be %q, %q or empty, got %q", TRACING_EXPORTER_OTLP, TRACING_EXPORTER_STDOUT, c.Tracing.Exporter)
+	}
+
+	if c.HealthCheckInterval <= 0 {
+		return fmt.Errorf("the health check interval must be positive")
+	}
+
+	if c.ContainerStatusInterval <= 0 {
+		return fmt.Errorf("the container status interval must be positive")
+	}
+
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("the shutdown timeout must not be negative")
+	}
+
+	if c.IdempotencyRetention <= 0 {
+		return fmt.Errorf("the idempotency retention must be positive")
+	}
+
+	if c.GcGracePeriod < 0 {
+		return fmt.Errorf("the garbage collection grace period must not be negative")
+	}
+
+	if err := c.Quotas.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Names of the optional features that the configuration turns on
+func (c *Config) Features() []string {
+	features := make([]string, 0)
+	for _, f := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"tls", c.TLS.Enabled()},
+		{"client-certificates", c.TLS.ClientCAFile != ""},
+		{"token-authentication", c.TokenFile != ""},
+		{"access-control", c.PolicyFile != ""},
+		{"metrics", c.MetricsListen != ""},
+		{"gateway", c.GatewayListen != ""},
+		{"tracing", c.Tracing.Enabled()},
+		{"reflection", c.Reflection},
+		{"quotas", c.Quotas.Default != Limits{} || len(c.Quotas.Identities) > 0},
+		{"periodic-reconciliation", c.ReconcileInterval > 0},
+	} {
+		if f.enabled {
+			features = append(features, f.name)
+		}
+	}
+
+	return features
+}
+
+func (h *Host) ParseTransitRange() (*net.IPNet, error) {
+	_, transitRange, err := net.ParseCIDR(h.TransitRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the transit range: %w", err)
+	}
+
+	ones, bits := transitRange.Mask.Size()
+	if bits != 32 || ones > 30 {
+		return nil, fmt.Errorf("the transit range must be an IPv4 range of at least /30, got %s", h.TransitRange)
+	}
+
+	return transitRange, nil
+}
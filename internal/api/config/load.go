@@ -0,0 +1,139 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ENV_PREFIX = "BX2CLOUD_"
+
+// Settings are read from the YAML config file, then environment variables and then flags, later sources take precedence
+func Load(name string, args []string) (*Config, error) {
+	config := Default()
+
+	// Binds every setting to its field, flags are only used here to parse and set the values
+	settings := flag.NewFlagSet(name, flag.ContinueOnError)
+	settings.Var((*listValue)(&config.Listen), "listen", "comma separated addresses to serve the API on, e.g. ':8080,unix:/run/bx2cloud.sock'")
+	settings.StringVar(&config.Paths.State, "state-dir", config.Paths.State, "directory of the network and subnetwork state")
+	settings.StringVar(&config.Paths.Containers, "container-dir", config.Paths.Containers, "directory of the container runtime state")
+	settings.StringVar(&config.Paths.Images, "image-dir", config.Paths.Images, "directory of the unpacked container images")
+	settings.StringVar(&config.Paths.Logs, "log-dir", config.Paths.Logs, "directory of the container logs")
+	settings.StringVar(&config.Host.InterfacePrefix, "interface-prefix", config.Host.InterfacePrefix, fmt.Sprintf("prepended to the names of veths and bridges, at most %d characters", MAX_INTERFACE_PREFIX_LENGTH))
+	settings.StringVar(&config.Host.NamespacePrefix, "namespace-prefix", config.Host.NamespacePrefix, "prepended to the names of network namespaces and cgroups")
+	settings.StringVar(&config.Host.ForwardChain, "forward-chain", config.Host.ForwardChain, "iptables filter chain that isolates networks, created and jumped to from FORWARD if it is not a built-in chain")
+	settings.StringVar(&config.Host.NatChain, "nat-chain", config.Host.NatChain, "iptables nat chain that gives networks internet access, created and jumped to from POSTROUTING if it is not a built-in chain")
+	settings.StringVar(&config.Host.TransitRange, "transit-range", config.Host.TransitRange, "IPv4 range that every network's router gets a /30 from to connect to the host")
+	settings.StringVar(&config.TLS.CertFile, "tls-cert", config.TLS.CertFile, "PEM certificate to serve TLS with, TLS is disabled if it is empty")
+	settings.StringVar(&config.TLS.KeyFile, "tls-key", config.TLS.KeyFile, "PEM private key of the TLS certificate")
+	settings.StringVar(&config.TLS.ClientCAFile, "tls-client-ca", config.TLS.ClientCAFile, "PEM CA certificate that client certificates are verified with, client certificates are not required if it is empty")
+	settings.StringVar(&config.TokenFile, "token-file", config.TokenFile, "file with the accepted bearer tokens, one per line, authentication is disabled if it is empty")
+	settings.StringVar(&config.PolicyFile, "policy-file", config.PolicyFile, "YAML file with the roles that callers are granted, access control is disabled if it is empty")
+	settings.StringVar(&config.MetricsListen, "metrics-listen", config.MetricsListen, "address to serve Prometheus metrics on over HTTP at /metrics, e.g. ':9090', metrics are not served if it is empty")
+	settings.StringVar(&config.GatewayListen, "gateway-listen", config.GatewayListen, "address to serve the HTTP/JSON gateway on, e.g. ':8081', with the same TLS settings as the API, the gateway is not served if it is empty")
+	settings.StringVar(&config.Tracing.Exporter, "tracing-exporter", config.Tracing.Exporter, fmt.Sprintf("where to export spans to, %q or %q, tracing is disabled if it is empty", TRACING_EXPORTER_OTLP, TRACING_EXPORTER_STDOUT))
+	settings.StringVar(&config.Tracing.Endpoint, "tracing-endpoint", config.Tracing.Endpoint, "address of the OTLP collector, e.g. 'localhost:4317'")
+	settings.BoolVar(&config.Tracing.Insecure, "tracing-insecure", config.Tracing.Insecure, "connect to the OTLP collector without TLS")
+	settings.IntVar(&config.Quotas.Default.Networks, "quota-networks", config.Quotas.Default.Networks, "maximum number of networks per caller identity, 0 is unlimited")
+	settings.IntVar(&config.Quotas.Default.Subnetworks, "quota-subnetworks", config.Quotas.Default.Subnetworks, "maximum number of subnetworks per caller identity, 0 is unlimited")
+	settings.IntVar(&config.Quotas.Default.Containers, "quota-containers", config.Quotas.Default.Containers, "maximum number of containers per caller identity, 0 is unlimited")
+	settings.IntVar(&config.Quotas.Default.RunningContainers, "quota-running-containers", config.Quotas.Default.RunningContainers, "maximum number of running containers per caller identity, 0 is unlimited")
+	settings.IntVar(&config.Quotas.Default.Ips, "quota-ips", config.Quotas.Default.Ips, "maximum number of IPs allocated to the containers of a caller identity, 0 is unlimited")
+	settings.BoolVar(&config.Reflection, "reflection", config.Reflection, "serve gRPC server reflection, so that clients such as grpcurl work without the .proto files")
+	settings.DurationVar(&config.HealthCheckInterval, "health-check-interval", config.HealthCheckInterval, "how often to check iptables, the data directories and the container state for the gRPC health service")
+	settings.StringVar(&config.Log.Level, "log-level", config.Log.Level, "minimum level of logged messages, debug, info, warn or error")
+	settings.StringVar(&config.Log.Format, "log-format", config.Log.Format, fmt.Sprintf("format of the logs, %q or %q", LOG_FORMAT_TEXT, LOG_FORMAT_JSON))
+	settings.DurationVar(&config.ShutdownTimeout, "shutdown-timeout", config.ShutdownTimeout, "how long to wait for calls to finish on SIGINT or SIGTERM before cancelling them, open streams are ended right away")
+	settings.DurationVar(&config.IdempotencyRetention, "idempotency-retention", config.IdempotencyRetention, "how long a created resource is returned for repeated creation requests with the same idempotency key")
+	settings.DurationVar(&config.ReconcileInterval, "reconcile-interval", config.ReconcileInterval, "how often to re-apply host networking of all resources, 0 disables periodic reconciliation")
+	settings.DurationVar(&config.ContainerStatusInterval, "container-status-interval", config.ContainerStatusInterval, "how often to check containers for status changes that happened outside of the API, such as the process exiting, to notify watchers")
+	settings.BoolVar(&config.StartupGcDryRun, "startup-gc-dry-run", config.StartupGcDryRun, "only report orphaned host resources found on startup instead of removing them, set it to false to remove them")
+	settings.DurationVar(&config.GcGracePeriod, "gc-grace-period", config.GcGracePeriod, "how long host resources have to be orphaned before garbage collection removes them")
+
+	// The flags that are actually parsed only record the given values, so that they can be applied after the other sources
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(ENV_PREFIX+"CONFIG"), fmt.Sprintf("path to a YAML config file, env %sCONFIG", ENV_PREFIX))
+	given := make(map[string]string)
+	settings.VisitAll(func(f *flag.Flag) {
+		usage := fmt.Sprintf("%s, env %s (default %q)", f.Usage, envName(f.Name), f.DefValue)
+		record := func(value string) error {
+			given[f.Name] = value
+			return nil
+		}
+
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			fs.BoolFunc(f.Name, usage, record)
+		} else {
+			fs.Func(f.Name, usage, record)
+		}
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		f, err := os.Open(*configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the config file: %w", err)
+		}
+		defer f.Close()
+
+		decoder := yaml.NewDecoder(f)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil {
+			return nil, fmt.Errorf("failed to parse the config file: %w", err)
+		}
+	}
+
+	var err error
+	settings.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok || err != nil {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid value of %s: %w", envName(f.Name), setErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range given {
+		if err := settings.Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid value of -%s: %w", name, err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// e.g. "state-dir" is read from BX2CLOUD_STATE_DIR
+func envName(flagName string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+type listValue []string
+
+func (l *listValue) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(value string) error {
+	*l = make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/config"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+listen: [":9090"]
+paths:
+  state: /tmp/yaml-state
+  logs: /tmp/yaml-logs
+host:
+  interfacePrefix: yml-
+reconcileInterval: 5m
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("BX2CLOUD_CONFIG", path)
+	t.Setenv("BX2CLOUD_STATE_DIR", "/tmp/env-state")
+	t.Setenv("BX2CLOUD_INTERFACE_PREFIX", "env-")
+
+	cfg, err := config.Load("test", []string{"-interface-prefix", "flg-", "-listen", "unix:/tmp/a.sock, :7070"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"unix:/tmp/a.sock", ":7070"}; !slices.Equal(cfg.Listen, want) {
+		t.Errorf("expected listen %v, got %v", want, cfg.Listen)
+	}
+	if cfg.Paths.State != "/tmp/env-state" {
+		t.Errorf("expected the env state dir, got %s", cfg.Paths.State)
+	}
+	if cfg.Paths.Logs != "/tmp/yaml-logs" {
+		t.Errorf("expected the YAML log dir, got %s", cfg.Paths.Logs)
+	}
+	if cfg.Paths.Images != config.Default().Paths.Images {
+		t.Errorf("expected the default image dir, got %s", cfg.Paths.Images)
+	}
+	if cfg.Host.InterfacePrefix != "flg-" {
+		t.Errorf("expected the flag interface prefix, got %s", cfg.Host.InterfacePrefix)
+	}
+	if cfg.ReconcileInterval != 5*time.Minute {
+		t.Errorf("expected the YAML reconcile interval, got %s", cfg.ReconcileInterval)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string][]string{
+		"long interface prefix":    {"-interface-prefix", "toolong-"},
+		"ipv6 transit range":       {"-transit-range", "fd00::/64"},
+		"no listen addresses":      {"-listen", ""},
+		"unknown flag":             {"-unknown"},
+		"unknown tracing exporter": {"-tracing-exporter", "jaeger"},
+		"zero status interval":     {"-container-status-interval", "0"},
+		"negative status interval": {"-container-status-interval", "-1s"},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := config.Load("test", args); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownYamlField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("listn: [\":9090\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.Load("test", []string{"-config", path}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -1,8 +1,14 @@
 package container
 
-import "github.com/BenasB/bx2cloud/internal/api/interfaces"
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+)
 
 type configurator interface {
-	Configure(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
-	Unconfigure(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
+	Configure(ctx context.Context, model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
+	Unconfigure(ctx context.Context, model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
+	// Returns an error describing how the host differs from the network configuration of a running container, if it does
+	Verify(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
 }
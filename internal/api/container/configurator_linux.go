@@ -1,12 +1,14 @@
 package container
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"runtime"
 
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netns"
@@ -18,17 +20,19 @@ type namespaceConfigurator struct {
 	getNetworkNamespaceName func(uint32) string
 	getBridgeName           func(uint32) string
 	ipamRepository          interfaces.IpamRepository
+	interfacePrefix         string
 }
 
-func NewNamespaceConfigurator(getNetworkNamespaceName func(uint32) string, getBridgeName func(uint32) string, ipamRepository interfaces.IpamRepository) *namespaceConfigurator {
+func NewNamespaceConfigurator(getNetworkNamespaceName func(uint32) string, getBridgeName func(uint32) string, ipamRepository interfaces.IpamRepository, interfacePrefix string) *namespaceConfigurator {
 	return &namespaceConfigurator{
 		getNetworkNamespaceName: getNetworkNamespaceName,
 		getBridgeName:           getBridgeName,
 		ipamRepository:          ipamRepository,
+		interfacePrefix:         interfacePrefix,
 	}
 }
 
-func (n *namespaceConfigurator) Configure(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error {
+func (n *namespaceConfigurator) Configure(ctx context.Context, model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error {
 	networkNsName := n.getNetworkNamespaceName(subnetworkModel.NetworkId)
 	networkNs, err := netns.GetFromName(networkNsName)
 	if err != nil {
@@ -176,20 +180,25 @@ func (n *namespaceConfigurator) Configure(model interfaces.ContainerModel, subne
 		return fmt.Errorf("failed to switch to the original network namespace: %w", err)
 	}
 
-	log.Printf("Successfully configured container with the id %d", modelData.Id)
+	slog.InfoContext(ctx, "Configured container", "container_id", modelData.Id)
 
 	return nil
 }
 
-func (n *namespaceConfigurator) Unconfigure(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error {
-	networkNsName := n.getNetworkNamespaceName(subnetworkModel.NetworkId)
+func (n *namespaceConfigurator) Unconfigure(ctx context.Context, model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error {
+	return n.UnconfigureOrphan(ctx, model.GetData().Id, subnetworkModel.NetworkId)
+}
+
+// Removes the network configuration of a container that might no longer have a model
+func (n *namespaceConfigurator) UnconfigureOrphan(ctx context.Context, id uint32, networkId uint32) error {
+	networkNsName := n.getNetworkNamespaceName(networkId)
 	networkNs, err := netns.GetFromName(networkNsName)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve the network's namespace: %w", err)
 	}
 	defer networkNs.Close()
 
-	modelData := model.GetData()
+	modelData := &interfaces.ContainerModelData{Id: id}
 
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -220,15 +229,120 @@ func (n *namespaceConfigurator) Unconfigure(model interfaces.ContainerModel, sub
 		return fmt.Errorf("failed to switch to the original network namespace: %w", err)
 	}
 
-	log.Printf("Successfully unconfigured container with the id %d", modelData.Id)
+	slog.InfoContext(ctx, "Unconfigured container", "container_id", modelData.Id)
+
+	return nil
+}
+
+func (n *namespaceConfigurator) Verify(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error {
+	networkNsName := n.getNetworkNamespaceName(subnetworkModel.NetworkId)
+	networkNs, err := netns.GetFromName(networkNsName)
+	if err != nil {
+		return fmt.Errorf("network namespace %q is missing", networkNsName)
+	}
+	defer networkNs.Close()
+
+	networkHandle, err := netlink.NewHandleAt(networkNs)
+	if err != nil {
+		return fmt.Errorf("failed to open the network's namespace: %w", err)
+	}
+	defer networkHandle.Close()
+
+	modelData := model.GetData()
+
+	bridgeName := n.getBridgeName(subnetworkModel.Id)
+	bridge, err := networkHandle.LinkByName(bridgeName)
+	if err != nil {
+		return fmt.Errorf("bridge %q is missing", bridgeName)
+	}
+
+	networkVethName := n.getNetworkVethName(modelData)
+	networkVeth, err := networkHandle.LinkByName(networkVethName)
+	if err != nil {
+		return fmt.Errorf("veth %q is missing in the network's namespace", networkVethName)
+	}
+
+	if networkVeth.Attrs().MasterIndex != bridge.Attrs().Index {
+		return fmt.Errorf("veth %q is not attached to bridge %q", networkVethName, bridgeName)
+	}
+
+	if networkVeth.Attrs().Flags&net.FlagUp == 0 {
+		return fmt.Errorf("veth %q is down", networkVethName)
+	}
+
+	state, err := model.GetState()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the container's state: %w", err)
+	}
+
+	containerNsPath := (&configs.Namespace{Type: configs.NEWNET}).GetPath(state.Pid)
+	containerNs, err := netns.GetFromPath(containerNsPath)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the network namespace of the container from the file path: %w", err)
+	}
+	defer containerNs.Close()
+
+	containerHandle, err := netlink.NewHandleAt(containerNs)
+	if err != nil {
+		return fmt.Errorf("failed to open the container's network namespace: %w", err)
+	}
+	defer containerHandle.Close()
+
+	containerVethName := n.getContainerVethName(modelData)
+	containerVeth, err := containerHandle.LinkByName(containerVethName)
+	if err != nil {
+		return fmt.Errorf("veth %q is missing in the container's namespace", containerVethName)
+	}
+
+	containerVethAddrs, err := containerHandle.AddrList(containerVeth, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve IP addresses of the container's namespace veth end: %w", err)
+	}
+
+	containerVethAddr := &netlink.Addr{
+		IPNet: modelData.Ip,
+	}
+
+	if len(containerVethAddrs) != 1 || !containerVethAddr.Equal(containerVethAddrs[0]) {
+		return fmt.Errorf("veth %q does not have exactly the address %s", containerVethName, modelData.Ip.String())
+	}
 
 	return nil
 }
 
+// Returns ids of all containers that have a veth in the namespace of the specified network
+func (n *namespaceConfigurator) ListConfigured(networkId uint32) ([]uint32, error) {
+	networkNs, err := netns.GetFromName(n.getNetworkNamespaceName(networkId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the network's namespace: %w", err)
+	}
+	defer networkNs.Close()
+
+	handle, err := netlink.NewHandleAt(networkNs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the network's namespace: %w", err)
+	}
+	defer handle.Close()
+
+	links, err := handle.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links of the network's namespace: %w", err)
+	}
+
+	ids := make([]uint32, 0)
+	for _, link := range links {
+		if id, ok := shared.ParseIdSuffix(link.Attrs().Name, n.interfacePrefix+"c-"); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
 func (n *namespaceConfigurator) getNetworkVethName(modelData *interfaces.ContainerModelData) string {
-	return fmt.Sprintf("bx2-c-%d", modelData.Id)
+	return fmt.Sprintf("%sc-%d", n.interfacePrefix, modelData.Id)
 }
 
 func (n *namespaceConfigurator) getContainerVethName(modelData *interfaces.ContainerModelData) string {
-	return fmt.Sprintf("bx2-c-%d-ns", modelData.Id)
+	return fmt.Sprintf("%sc-%d-ns", n.interfacePrefix, modelData.Id)
 }
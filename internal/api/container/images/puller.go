@@ -3,10 +3,11 @@ package images
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,9 +15,15 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/metrics"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/BenasB/bx2cloud/internal/api/tracing"
 	"github.com/opencontainers/go-digest"
 	imgspecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type RegistryEntity string
@@ -27,9 +34,11 @@ const (
 )
 
 type Puller interface {
-	GatherImageMetadata(imageName string) (*imageMetadata, error)
-	PrepareRootFs(id uint32, metadata *imageMetadata) (string, error)
+	GatherImageMetadata(ctx context.Context, imageName string) (*imageMetadata, error)
+	PrepareRootFs(ctx context.Context, id uint32, metadata *imageMetadata) (string, error)
 	RemoveRootFs(id uint32) error
+	// Returns the ids of all prepared rootfs along with their modification times
+	ListRootFs() (map[uint32]time.Time, error)
 }
 
 var _ Puller = &flatPuller{}
@@ -53,9 +62,7 @@ type imageContext struct {
 	token string
 }
 
-func NewFlatPuller() (*flatPuller, error) {
-	dir := "/var/lib/bx2cloud"
-
+func NewFlatPuller(dir string) (*flatPuller, error) {
 	if err := os.MkdirAll(dir, 0644); err != nil {
 		return nil, fmt.Errorf("failed to create the directory that stores rootfs of containers: %w", err)
 	}
@@ -68,7 +75,10 @@ func NewFlatPuller() (*flatPuller, error) {
 	}, nil
 }
 
-func (p *flatPuller) GatherImageMetadata(imageName string) (*imageMetadata, error) {
+func (p *flatPuller) GatherImageMetadata(ctx context.Context, imageName string) (_ *imageMetadata, err error) {
+	_, span := tracing.Start(ctx, "images.GatherImageMetadata", attribute.String("image", imageName))
+	defer func() { tracing.End(span, err) }()
+
 	ref, context := p.parseImageName(imageName)
 
 	initialManifestBytes, contentType, err := p.fetchRegistry(ref, REGISTRY_ENTITY_MANIFEST, context)
@@ -135,14 +145,20 @@ func (p *flatPuller) GatherImageMetadata(imageName string) (*imageMetadata, erro
 	}, nil
 }
 
-func (p *flatPuller) PrepareRootFs(id uint32, metadata *imageMetadata) (string, error) {
+func (p *flatPuller) PrepareRootFs(ctx context.Context, id uint32, metadata *imageMetadata) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "images.PrepareRootFs", attribute.Int("layers", len(metadata.manifest.Layers)))
+	defer func() { tracing.End(span, err) }()
+
 	rootfsDir := p.getRootFsDir(id)
 	if _, err := os.Stat(rootfsDir); err == nil {
 		return "", fmt.Errorf("something already exsits at the rootfs path %q", rootfsDir)
 	}
 
+	start := time.Now()
+	defer func() { metrics.ImagePullDuration.Observe(time.Since(start).Seconds()) }()
+
 	for _, layer := range metadata.manifest.Layers {
-		err := p.fetchAndUnpackLayer(layer.Digest.String(), metadata.context, rootfsDir)
+		err := p.fetchAndUnpackLayer(ctx, layer.Digest.String(), metadata.context, rootfsDir)
 		if err != nil {
 			return "", fmt.Errorf("failed to fetch and unpack layer: %w", err)
 		}
@@ -208,6 +224,11 @@ func (p *flatPuller) requestRegistry(ref string, entity RegistryEntity, context
 		}
 	}
 
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, apierrors.NotFound("could not find %s %q of image %q in registry %s", entity, ref, context.name, context.host)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		bytes, err := io.ReadAll(resp.Body)
 		if err != nil {
@@ -227,6 +248,7 @@ func (p *flatPuller) fetchRegistry(ref string, entity RegistryEntity, context *i
 	defer resp.Body.Close()
 
 	bytes, err := io.ReadAll(resp.Body)
+	metrics.ImagePullBytes.Add(float64(len(bytes)))
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to read body: %w", err)
 	}
@@ -234,18 +256,26 @@ func (p *flatPuller) fetchRegistry(ref string, entity RegistryEntity, context *i
 	return bytes, resp.Header.Get("Content-Type"), nil
 }
 
-func (p *flatPuller) fetchAndUnpackLayer(ref string, context *imageContext, dir string) error {
+func (p *flatPuller) fetchAndUnpackLayer(ctx context.Context, ref string, context *imageContext, dir string) (err error) {
+	_, span := tracing.Start(ctx, "images.fetchAndUnpackLayer", attribute.String("digest", ref))
+	downloaded := &countingReader{}
+	defer func() {
+		span.SetAttributes(attribute.Int64("bytes", downloaded.n))
+		tracing.End(span, err)
+	}()
+
 	resp, err := p.requestRegistry(ref, REGISTRY_ENTITY_BLOB, context)
 	if err != nil {
 		return fmt.Errorf("failed to download layer: %w", err)
 	}
 	defer resp.Body.Close()
+	downloaded.reader = resp.Body
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("fetch failed with status %d", resp.StatusCode)
 	}
 
-	gzipReader, err := gzip.NewReader(resp.Body)
+	gzipReader, err := gzip.NewReader(downloaded)
 	if err != nil {
 		return fmt.Errorf("failed to create gzip reader: %w", err)
 	}
@@ -335,12 +365,25 @@ func (p *flatPuller) fetchAndUnpackLayer(ref string, context *imageContext, dir
 				return err
 			}
 		default:
-			log.Printf("Skipping unsupported tar entry type %c for file %s", header.Typeflag, header.Name)
+			slog.WarnContext(ctx, "Skipping an unsupported tar entry", "type", string(header.Typeflag), "file", header.Name)
 		}
 	}
 	return nil
 }
 
+// Counts the compressed bytes of layers as they are downloaded
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	r.n += int64(n)
+	metrics.ImagePullBytes.Add(float64(n))
+	return n, err
+}
+
 func (p *flatPuller) fetchToken(wwwAuthenticateHeader string) (string, error) {
 	params := p.parseWwwAuthenticate(wwwAuthenticateHeader)
 	realm, ok := params["realm"]
@@ -448,3 +491,7 @@ func (p *flatPuller) findManifestDigestInIndex(index *imgspecs.Index) (*digest.D
 func (p *flatPuller) RemoveRootFs(id uint32) error {
 	return os.RemoveAll(p.getRootFsDir(id))
 }
+
+func (p *flatPuller) ListRootFs() (map[uint32]time.Time, error) {
+	return shared.ListIdEntries(p.dir)
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 )
 
 var _ Logger = &fsLogger{}
@@ -12,8 +15,7 @@ type fsLogger struct {
 	root string
 }
 
-func NewFsLogger() (Logger, error) {
-	root := "/var/log/bx2cloud"
+func NewFsLogger(root string) (Logger, error) {
 	if err := os.MkdirAll(root, 0o700); err != nil {
 		return nil, err
 	}
@@ -37,3 +39,7 @@ func (l *fsLogger) Remove(containerId uint32) error {
 	idString := strconv.FormatInt(int64(containerId), 10)
 	return os.Remove(filepath.Join(l.root, idString))
 }
+
+func (l *fsLogger) List() (map[uint32]time.Time, error) {
+	return shared.ListIdEntries(l.root)
+}
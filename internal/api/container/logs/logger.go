@@ -2,10 +2,13 @@ package logs
 
 import (
 	"os"
+	"time"
 )
 
 type Logger interface {
 	Init(containerId uint32) (*os.File, error)
 	Remove(containerId uint32) error
 	Get(containerId uint32) (*os.File, error)
+	// Returns the ids of all containers that have a log file along with its modification time
+	List() (map[uint32]time.Time, error)
 }
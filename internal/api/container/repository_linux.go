@@ -3,17 +3,25 @@ package container
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
 	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	_ "github.com/opencontainers/cgroups/devices"
 	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/specconv"
@@ -25,11 +33,20 @@ import (
 var _ interfaces.ContainerRepository = &libcontainerRepository{}
 
 type libcontainerRepository struct {
-	root string
+	root         string
+	cgroupPrefix string
+	// Container ids by project and name, so that looking a container up by its name does not load every container
+	namesMu sync.Mutex
+	names   map[containerName]uint32
 }
 
-func NewLibcontainerRepository() (interfaces.ContainerRepository, error) {
-	root := "/var/run/bx2cloud"
+// Names are only unique within a project
+type containerName struct {
+	projectId uint32
+	name      string
+}
+
+func NewLibcontainerRepository(root string, cgroupPrefix string) (interfaces.ContainerRepository, error) {
 	if err := os.MkdirAll(root, 0o700); err != nil {
 		return nil, err
 	}
@@ -76,9 +93,50 @@ func NewLibcontainerRepository() (interfaces.ContainerRepository, error) {
 		_ = id.Skip("container", *maxId)
 	}
 
-	return &libcontainerRepository{
-		root: root,
-	}, nil
+	r := &libcontainerRepository{
+		root:         root,
+		cgroupPrefix: cgroupPrefix,
+		names:        make(map[containerName]uint32),
+	}
+
+	for _, item := range list {
+		if !item.IsDir() {
+			continue
+		}
+
+		container, err := libcontainer.Load(root, item.Name())
+		if errors.Is(err, libcontainer.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			var model interfaces.ContainerModel
+			if model, err = r.mapToContainerModel(container); err == nil {
+				r.index(model.GetData())
+				continue
+			}
+		}
+		slog.Warn("Failed to load a container, it can not be found by its name", "container_id", item.Name(), "error", err)
+	}
+
+	return r, nil
+}
+
+func (r *libcontainerRepository) index(data *interfaces.ContainerModelData) {
+	if data.Name == "" {
+		return
+	}
+
+	r.namesMu.Lock()
+	defer r.namesMu.Unlock()
+
+	r.names[containerName{projectId: data.ProjectId, name: data.Name}] = data.Id
+}
+
+func (r *libcontainerRepository) unindex(id uint32) {
+	r.namesMu.Lock()
+	defer r.namesMu.Unlock()
+
+	maps.DeleteFunc(r.names, func(_ containerName, indexedId uint32) bool { return indexedId == id })
 }
 
 func (r *libcontainerRepository) mapToContainerModel(container *libcontainer.Container) (interfaces.ContainerModel, error) {
@@ -143,6 +201,47 @@ func (r *libcontainerRepository) mapToContainerModel(container *libcontainer.Con
 			continue
 		}
 
+		if after, found := strings.CutPrefix(label, "name="); found {
+			data.Name = after
+			continue
+		}
+
+		// Containers created before projects were introduced have no project label and belong to the default project
+		if after, found := strings.CutPrefix(label, "projectId="); found {
+			projectId, err := strconv.ParseUint(after, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the container's project id: %w", err)
+			}
+			data.ProjectId = uint32(projectId)
+			continue
+		}
+
+		if after, found := strings.CutPrefix(label, "idempotencyKey="); found {
+			data.IdempotencyKey = after
+			continue
+		}
+
+		if after, found := strings.CutPrefix(label, "createdBy="); found {
+			data.CreatedBy = after
+			continue
+		}
+
+		if after, found := strings.CutPrefix(label, "labels="); found {
+			if err := json.Unmarshal([]byte(after), &data.Labels); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the container's labels: %w", err)
+			}
+			continue
+		}
+
+		if after, found := strings.CutPrefix(label, "resourceVersion="); found {
+			resourceVersion, err := strconv.ParseUint(after, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the container's resource version: %w", err)
+			}
+			data.ResourceVersion = resourceVersion
+			continue
+		}
+
 		if after, found := strings.CutPrefix(label, "createdAt="); found {
 			createdAt, err := time.Parse(time.RFC3339, after)
 			if err != nil {
@@ -153,6 +252,9 @@ func (r *libcontainerRepository) mapToContainerModel(container *libcontainer.Con
 		}
 	}
 
+	// Containers created before resource versions were introduced
+	data.ResourceVersion = max(data.ResourceVersion, 1)
+
 	if data.Image == "" {
 		return nil, fmt.Errorf("failed to locate metadata about the container's image")
 	}
@@ -182,7 +284,9 @@ func (r *libcontainerRepository) mapToContainerModel(container *libcontainer.Con
 
 func (r *libcontainerRepository) Get(id uint32) (interfaces.ContainerModel, error) {
 	container, err := libcontainer.Load(r.root, strconv.FormatInt(int64(id), 10))
-
+	if errors.Is(err, libcontainer.ErrNotExist) {
+		return nil, apierrors.NotFound("could not find container with id %d", id)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +294,18 @@ func (r *libcontainerRepository) Get(id uint32) (interfaces.ContainerModel, erro
 	return r.mapToContainerModel(container)
 }
 
+func (r *libcontainerRepository) GetByName(projectId uint32, name string) (interfaces.ContainerModel, error) {
+	r.namesMu.Lock()
+	id, ok := r.names[containerName{projectId: projectId, name: name}]
+	r.namesMu.Unlock()
+
+	if !ok || name == "" {
+		return nil, apierrors.NotFound("could not find container with name %q", name)
+	}
+
+	return r.Get(id)
+}
+
 func (r *libcontainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
 	results := make(chan interfaces.ContainerModel, 0)
 	errChan := make(chan error, 1)
@@ -233,9 +349,47 @@ func (r *libcontainerRepository) GetAll(ctx context.Context) (<-chan interfaces.
 	return results, errChan
 }
 
+func (r *libcontainerRepository) List(ctx context.Context, filter *interfaces.ContainerFilter, options *listing.Options) ([]interfaces.ContainerModel, bool, error) {
+	containers, err := shared.CollectAll(r.GetAll(ctx))
+	if err != nil {
+		return nil, false, err
+	}
+
+	matching := make([]interfaces.ContainerModel, 0)
+	for _, container := range containers {
+		data := container.GetData()
+		if data.ProjectId != filter.ProjectId {
+			continue
+		}
+		if filter.SubnetworkIds != nil && !slices.Contains(filter.SubnetworkIds, data.SubnetworkId) {
+			continue
+		}
+		if filter.Image != "" && data.Image != filter.Image {
+			continue
+		}
+		if !options.MatchesLabels(data.Labels) {
+			continue
+		}
+		if filter.Status != "" {
+			state, err := container.GetState()
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to retrieve the state of container %d: %w", data.Id, err)
+			}
+			if string(state.Status) != filter.Status {
+				continue
+			}
+		}
+
+		matching = append(matching, container)
+	}
+
+	page, more := listing.Page(matching, options, interfaces.ContainerOrderFields)
+	return page, more, nil
+}
+
 func (r *libcontainerRepository) Create(creationModel *interfaces.ContainerCreationModel) (interfaces.ContainerModel, error) {
 	config, err := specconv.CreateLibcontainerConfig(&specconv.CreateOpts{
-		CgroupName:       fmt.Sprintf("bx2cloud-container-%d", creationModel.Id),
+		CgroupName:       fmt.Sprintf("%scontainer-%d", r.cgroupPrefix, creationModel.Id),
 		UseSystemdCgroup: false,
 		NoPivotRoot:      false,
 		NoNewKeyring:     false,
@@ -255,12 +409,24 @@ func (r *libcontainerRepository) Create(creationModel *interfaces.ContainerCreat
 		return nil, fmt.Errorf("failed to serialize the entrypoint customization: %w", err)
 	}
 
+	// User labels are kept in a single libcontainer label, so they can't clash with the metadata labels above
+	serializedLabels, err := json.Marshal(creationModel.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the container's labels: %w", err)
+	}
+
 	config.Labels = append(config.Labels, fmt.Sprintf("image=%s", creationModel.Image))
 	config.Labels = append(config.Labels, fmt.Sprintf("subnetworkId=%d", creationModel.SubnetworkId))
 	config.Labels = append(config.Labels, fmt.Sprintf("ip=%s", creationModel.Ip.String()))
 	config.Labels = append(config.Labels, fmt.Sprintf("spec=%s", serializedSpec))
 	config.Labels = append(config.Labels, fmt.Sprintf("entrypointCustomization=%s", serializedEntryCustomization))
 	config.Labels = append(config.Labels, fmt.Sprintf("createdAt=%s", creationModel.CreatedAt.Format(time.RFC3339)))
+	config.Labels = append(config.Labels, fmt.Sprintf("resourceVersion=%d", creationModel.ResourceVersion))
+	config.Labels = append(config.Labels, fmt.Sprintf("labels=%s", serializedLabels))
+	config.Labels = append(config.Labels, fmt.Sprintf("name=%s", creationModel.Name))
+	config.Labels = append(config.Labels, fmt.Sprintf("projectId=%d", creationModel.ProjectId))
+	config.Labels = append(config.Labels, fmt.Sprintf("idempotencyKey=%s", creationModel.IdempotencyKey))
+	config.Labels = append(config.Labels, fmt.Sprintf("createdBy=%s", creationModel.CreatedBy))
 
 	container, err := libcontainer.Create(
 		r.root,
@@ -293,6 +459,44 @@ func (r *libcontainerRepository) Create(creationModel *interfaces.ContainerCreat
 		initProcess.Wait()
 	}()
 
+	model, err := r.mapToContainerModel(container)
+	if err != nil {
+		return nil, err
+	}
+	r.index(model.GetData())
+
+	return model, nil
+}
+
+func (r *libcontainerRepository) Stop(id uint32) (interfaces.ContainerModel, error) {
+	container, err := libcontainer.Load(r.root, strconv.FormatInt(int64(id), 10))
+	if errors.Is(err, libcontainer.ErrNotExist) {
+		return nil, apierrors.NotFound("could not find container with id %d", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := r.mapToContainerModel(container)
+	if err != nil {
+		return nil, err
+	}
+
+	config := container.Config()
+	config.Labels = slices.DeleteFunc(slices.Clone(config.Labels), func(label string) bool {
+		return strings.HasPrefix(label, "resourceVersion=")
+	})
+	config.Labels = append(config.Labels, fmt.Sprintf("resourceVersion=%d", model.GetData().ResourceVersion+1))
+
+	// libcontainer does not save the configuration of stopped containers, so the version is bumped before stopping
+	if err := container.Set(config); err != nil {
+		return nil, fmt.Errorf("failed to bump the container's resource version: %w", err)
+	}
+
+	if err := model.Stop(); err != nil {
+		return nil, err
+	}
+
 	return r.mapToContainerModel(container)
 }
 
@@ -305,6 +509,7 @@ func (r *libcontainerRepository) Delete(id uint32) (interfaces.ContainerModel, e
 	if err := container.Destroy(); err != nil {
 		return nil, err
 	}
+	r.unindex(id)
 
 	return r.mapToContainerModel(container)
 }
@@ -327,6 +532,22 @@ func (w *wrappedContainer) GetState() (*runspecs.State, error) {
 	return w.container.OCIState()
 }
 
+func (w *wrappedContainer) GetStats() (*interfaces.ContainerStats, error) {
+	stats, err := w.container.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cgroup stats: %w", err)
+	}
+
+	if stats.CgroupStats == nil {
+		return &interfaces.ContainerStats{}, nil
+	}
+
+	return &interfaces.ContainerStats{
+		CpuUsage:    time.Duration(stats.CgroupStats.CpuStats.CpuUsage.TotalUsage),
+		MemoryBytes: stats.CgroupStats.MemoryStats.Usage.Usage,
+	}, nil
+}
+
 func (w *wrappedContainer) Exec() error {
 	return w.container.Exec()
 }
@@ -338,7 +559,7 @@ func (w *wrappedContainer) StartAdditionalProcess(spec *runspecs.Process) (inter
 	}
 
 	if status != libcontainer.Running {
-		return nil, fmt.Errorf("the container is not running")
+		return nil, apierrors.FailedPrecondition("the container is not running")
 	}
 
 	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
@@ -381,7 +602,7 @@ func (w *wrappedContainer) Stop() error {
 	}
 
 	if status != libcontainer.Running {
-		return fmt.Errorf("can't stop a container that is not running")
+		return apierrors.FailedPrecondition("can't stop a container that is not running")
 	}
 
 	return stopSignalable(w.container)
@@ -3,16 +3,29 @@ package container
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"sync"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
 	"github.com/BenasB/bx2cloud/internal/api/container/images"
 	"github.com/BenasB/bx2cloud/internal/api/container/logs"
+	"github.com/BenasB/bx2cloud/internal/api/events"
 	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/BenasB/bx2cloud/internal/api/tracing"
 	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -25,6 +38,23 @@ type service struct {
 	imagePuller          images.Puller
 	ipamRepository       interfaces.IpamRepository
 	containerLogger      logs.Logger
+	quotas               interfaces.QuotaChecker
+	idempotency          interfaces.IdempotencyTracker
+	// Serializes Create, Delete, Start and Stop of the same container, and the reconciler's repairs of it
+	locks  *shared.KeyedMutex
+	events *events.Broker[*pb.Container]
+	// Last published status of every container, used to detect status changes that happen outside of the API
+	statusesMu sync.Mutex
+	statuses   map[uint32]string
+	// Names of containers that are being created and are not in the repository yet
+	creatingNamesMu sync.Mutex
+	creatingNames   map[creatingName]struct{}
+}
+
+// Names are only unique within a project
+type creatingName struct {
+	projectId uint32
+	name      string
 }
 
 func NewService(
@@ -34,6 +64,8 @@ func NewService(
 	imagePuller images.Puller,
 	ipamRepository interfaces.IpamRepository,
 	containerLogger logs.Logger,
+	quotas interfaces.QuotaChecker,
+	idempotency interfaces.IdempotencyTracker,
 ) *service {
 	return &service{
 		repository:           containerRepository,
@@ -42,11 +74,22 @@ func NewService(
 		imagePuller:          imagePuller,
 		ipamRepository:       ipamRepository,
 		containerLogger:      containerLogger,
+		quotas:               quotas,
+		idempotency:          idempotency,
+		locks:                shared.ContainerLocks,
+		events:               events.NewBroker[*pb.Container](events.DEFAULT_HISTORY_SIZE),
+		statuses:             make(map[uint32]string),
+		creatingNames:        make(map[creatingName]struct{}),
 	}
 }
 
 func (s *service) Get(ctx context.Context, req *pb.ContainerIdentificationRequest) (*pb.Container, error) {
-	container, err := s.repository.Get(req.Id)
+	id, err := s.resolveId(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return nil, err
 	}
@@ -55,11 +98,22 @@ func (s *service) Get(ctx context.Context, req *pb.ContainerIdentificationReques
 }
 
 func (s *service) Delete(ctx context.Context, req *pb.ContainerIdentificationRequest) (*emptypb.Empty, error) {
-	container, err := s.repository.Get(req.Id)
+	id, err := s.resolveId(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.Lock(id)()
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := interfaces.CheckResourceVersion(req.ResourceVersion, container.GetData().ResourceVersion); err != nil {
+		return nil, err
+	}
+
 	data := container.GetData()
 	subnetwork, err := s.subnetworkRepository.Get(data.SubnetworkId)
 	if err != nil {
@@ -68,7 +122,7 @@ func (s *service) Delete(ctx context.Context, req *pb.ContainerIdentificationReq
 
 	state, err := container.GetState()
 	if err != nil {
-		log.Printf("Will skip killing the container process, since we can't determine if the container is in a running status: %v", err)
+		slog.WarnContext(ctx, "Will skip killing the container process, since we can't determine if the container is in a running status", "container_id", id, "error", err)
 	}
 
 	if err == nil && state.Status == runspecs.StateRunning {
@@ -77,7 +131,7 @@ func (s *service) Delete(ctx context.Context, req *pb.ContainerIdentificationReq
 		}
 	}
 
-	if err := s.configurator.Unconfigure(container, subnetwork); err != nil {
+	if err := s.configurator.Unconfigure(ctx, container, subnetwork); err != nil {
 		return nil, err
 	}
 
@@ -93,41 +147,122 @@ func (s *service) Delete(ctx context.Context, req *pb.ContainerIdentificationReq
 		return nil, fmt.Errorf("failed to deallocate an IP for the container: %w", err)
 	}
 
+	dto, err := mapModelToDto(container)
+	if err != nil {
+		dto = &pb.Container{Id: data.Id, SubnetworkId: data.SubnetworkId, ResourceVersion: data.ResourceVersion, ProjectId: data.ProjectId}
+	}
+
 	_, err = s.repository.Delete(data.Id)
 	if err != nil {
 		return nil, err
 	}
 
+	s.publish(pb.EventType_DELETED, dto)
+
 	return &emptypb.Empty{}, nil
 }
 
 func (s *service) Create(ctx context.Context, req *pb.ContainerCreationRequest) (*pb.Container, error) {
+	if err := labels.Validate(req.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claimed before the name, so a repeated request waits for the first one instead of failing on the name it is taking
+	releaseKey, err := s.idempotency.Claim(ctx, projectId, interfaces.IDEMPOTENCY_CONTAINERS, req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseKey()
+
+	if existing, err := s.createdWithKey(ctx, projectId, req.IdempotencyKey); err != nil || existing != nil {
+		return existing, err
+	}
+
+	releaseName, err := s.reserveName(projectId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseName()
+
 	subnetwork, err := s.subnetworkRepository.Get(req.SubnetworkId)
 	if err != nil {
 		return nil, err
 	}
 
-	id := id.NextId("container")
+	// Containers can only join subnetworks of the caller's project, other subnetworks do not exist for the caller
+	if subnetwork.ProjectId != projectId {
+		return nil, apierrors.NotFound("could not find subnetwork with id %d", req.SubnetworkId)
+	}
 
-	imgMetadata, err := s.imagePuller.GatherImageMetadata(req.Image)
+	// Created containers are started right away and take up an IP, they count towards the quotas of the caller that created them
+	createdBy := auth.Identity(ctx)
+	release, err := s.quotas.Reserve(ctx, createdBy, interfaces.QUOTA_CONTAINERS, interfaces.QUOTA_RUNNING_CONTAINERS, interfaces.QUOTA_IPS)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+
+	id := id.NextId("container")
 
-	rootFsDir, err := s.imagePuller.PrepareRootFs(id, imgMetadata)
+	// Held until the container is configured and running, before it can be seen in the repository
+	defer s.locks.Lock(id)()
+
+	imgMetadata, err := s.imagePuller.GatherImageMetadata(ctx, req.Image)
 	if err != nil {
 		return nil, err
 	}
 
-	ip, err := s.ipamRepository.Allocate(subnetwork, interfaces.IPAM_CONTAINER)
+	// Every applied step registers how to undo it, so that a failure does not leave a partially created container behind
+	rollback := shared.NewRollback()
+	fail := func(err error) (*pb.Container, error) {
+		return nil, rollback.Run(fmt.Errorf("failed to create container %d: %w", id, err))
+	}
+
+	rootFsDir, err := s.imagePuller.PrepareRootFs(ctx, id, imgMetadata)
+	if err != nil {
+		return fail(err)
+	}
+	rollback.Add("remove the rootfs", func() error {
+		return s.imagePuller.RemoveRootFs(id)
+	})
+
+	// Allocating under the subnetwork's lock keeps the subnetwork from being deleted while it gets its first IP,
+	// since deleting it checks that it has no IPs allocated under the same lock
+	ip, err := func() (*net.IPNet, error) {
+		defer shared.SubnetworkLocks.Lock(subnetwork.Id)()
+
+		if _, err := s.subnetworkRepository.Get(subnetwork.Id); err != nil {
+			return nil, err
+		}
+
+		_, span := tracing.Start(ctx, "ipam.Allocate", attribute.Int64("subnetwork_id", int64(subnetwork.Id)))
+		ip, err := s.ipamRepository.Allocate(subnetwork, interfaces.IPAM_CONTAINER)
+		tracing.End(span, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate a new IP for the container: %w", err)
+		}
+		return ip, nil
+	}()
 	if err != nil {
-		return nil, fmt.Errorf("failed to allocate a new IP for the container: %w", err)
+		return fail(err)
 	}
+	rollback.Add("release the IP", func() error {
+		return s.ipamRepository.Deallocate(subnetwork, ip)
+	})
 
 	stdout, err := s.containerLogger.Init(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create a file for the container logs: %w", err)
+		return fail(fmt.Errorf("failed to create a file for the container logs: %w", err))
 	}
+	rollback.Add("remove the log file", func() error {
+		stdout.Close()
+		return s.containerLogger.Remove(id)
+	})
 
 	entrypointCust := &interfaces.ContainerProcessCustomization{
 		Entrypoint: req.Entrypoint,
@@ -147,7 +282,12 @@ func (s *service) Create(ctx context.Context, req *pb.ContainerCreationRequest)
 		imgMetadata.Image.Config.Env = append(imgMetadata.Image.Config.Env, entrypointCust.Env...)
 	}
 
-	spec := imageSpecToRuntimeSpec(id, rootFsDir, &imgMetadata.Image.Config)
+	hostname := req.Name
+	if hostname == "" {
+		hostname = fmt.Sprintf("container-%d", id)
+	}
+
+	spec := imageSpecToRuntimeSpec(hostname, rootFsDir, &imgMetadata.Image.Config)
 	creationModel := &interfaces.ContainerCreationModel{
 		Id:                      id,
 		Ip:                      ip,
@@ -157,75 +297,153 @@ func (s *service) Create(ctx context.Context, req *pb.ContainerCreationRequest)
 		EntrypointCustomization: entrypointCust,
 		CreatedAt:               time.Now(),
 		Stdout:                  stdout,
+		ResourceVersion:         1,
+		Labels:                  req.Labels,
+		Name:                    req.Name,
+		ProjectId:               projectId,
+		IdempotencyKey:          req.IdempotencyKey,
+		CreatedBy:               createdBy,
 	}
 
+	_, span := tracing.Start(ctx, "container.repository.Create")
 	container, err := s.repository.Create(creationModel)
+	tracing.End(span, err)
 	if err != nil {
-		return nil, err
+		return fail(err)
+	}
+	// The container and its IP are counted from the repository now, it only counts as running once it is started
+	release(interfaces.QUOTA_CONTAINERS, interfaces.QUOTA_IPS)
+	rollback.Add("destroy the container", func() error {
+		if state, err := container.GetState(); err == nil && state.Status == runspecs.StateRunning {
+			if err := container.Stop(); err != nil {
+				return err
+			}
+		}
+		_, err := s.repository.Delete(id)
+		return err
+	})
+
+	// Unconfiguring is idempotent, so it is registered upfront to also clean up after a partially applied configuration
+	rollback.Add("unconfigure the container's network", func() error {
+		return s.configurator.Unconfigure(ctx, container, subnetwork)
+	})
+	_, span = tracing.Start(ctx, "container.configurator.Configure")
+	err = s.configurator.Configure(ctx, container, subnetwork)
+	tracing.End(span, err)
+	if err != nil {
+		return fail(err)
 	}
 
-	if err := s.configurator.Configure(container, subnetwork); err != nil {
-		return nil, err
+	_, span = tracing.Start(ctx, "container.Exec")
+	err = container.Exec()
+	tracing.End(span, err)
+	if err != nil {
+		return fail(err)
 	}
 
-	if err := container.Exec(); err != nil {
+	dto, err := mapModelToDto(container)
+	if err != nil {
 		return nil, err
 	}
 
-	return mapModelToDto(container)
+	s.publish(pb.EventType_ADDED, dto)
+
+	return dto, nil
 }
 
-func (s *service) List(req *emptypb.Empty, stream grpc.ServerStreamingServer[pb.Container]) error {
-	containers, errors := s.repository.GetAll(stream.Context())
-
-	for {
-		select {
-		case container, ok := <-containers:
-			if !ok {
-				select {
-				case err := <-errors:
-					return err
-				default:
-					return nil
-				}
-			}
-			dto, err := mapModelToDto(container)
-			if err != nil {
-				return err
-			}
-			if err := stream.Send(dto); err != nil {
-				return err
-			}
-		case err, ok := <-errors:
-			if ok {
-				return err
+func (s *service) List(req *pb.ListRequest, stream grpc.ServerStreamingServer[pb.Container]) error {
+	options, err := listing.FromRequest(req, interfaces.ContainerOrderFields)
+	if err != nil {
+		return err
+	}
+
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	filter := &interfaces.ContainerFilter{
+		ProjectId: projectId,
+		Status:    req.Status,
+		Image:     req.Image,
+	}
+	if req.SubnetworkId != nil {
+		filter.SubnetworkIds = []uint32{*req.SubnetworkId}
+	}
+	if req.NetworkId != nil {
+		subnetworks, err := shared.CollectAll(s.subnetworkRepository.GetAllByNetworkId(*req.NetworkId, stream.Context()))
+		if err != nil {
+			return err
+		}
+
+		// Containers are only attached to subnetworks, so a network filter becomes a filter on its subnetworks
+		ids := make([]uint32, 0)
+		for _, subnetwork := range subnetworks {
+			if req.SubnetworkId == nil || subnetwork.Id == *req.SubnetworkId {
+				ids = append(ids, subnetwork.Id)
 			}
 		}
+		filter.SubnetworkIds = ids
+	}
+
+	containers, more, err := s.repository.List(stream.Context(), filter, options)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		dto, err := mapModelToDto(container)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(dto); err != nil {
+			return err
+		}
 	}
+
+	listing.SetNextPageToken(stream, options.NextPageToken(more))
+	return nil
 }
 
 func (s *service) Start(ctx context.Context, req *pb.ContainerIdentificationRequest) (*pb.Container, error) {
-	container, err := s.repository.Get(req.Id)
+	id, err := s.resolveId(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.Lock(id)()
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return nil, err
 	}
 
 	data := container.GetData()
+	if err := interfaces.CheckResourceVersion(req.ResourceVersion, data.ResourceVersion); err != nil {
+		return nil, err
+	}
+
 	state, err := container.GetState()
 	if err != nil {
 		return nil, err
 	}
 
 	if state.Status != runspecs.StateStopped {
-		return nil, fmt.Errorf("can't start a container that is not %q", runspecs.StateStopped)
+		return nil, apierrors.FailedPrecondition("can't start a container that is not %q", runspecs.StateStopped)
 	}
 
+	release, err := s.quotas.Reserve(ctx, data.CreatedBy, interfaces.QUOTA_RUNNING_CONTAINERS)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	subnetwork, err := s.subnetworkRepository.Get(data.SubnetworkId)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.configurator.Unconfigure(container, subnetwork); err != nil {
+	if err := s.configurator.Unconfigure(ctx, container, subnetwork); err != nil {
 		return nil, err
 	}
 
@@ -247,6 +465,13 @@ func (s *service) Start(ctx context.Context, req *pb.ContainerIdentificationRequ
 		EntrypointCustomization: data.EntrypointCustomization,
 		CreatedAt:               data.CreatedAt,
 		Stdout:                  stdout,
+		// The container is recreated, which counts as a change
+		ResourceVersion: data.ResourceVersion + 1,
+		Labels:          data.Labels,
+		Name:            data.Name,
+		ProjectId:       data.ProjectId,
+		IdempotencyKey:  data.IdempotencyKey,
+		CreatedBy:       data.CreatedBy,
 	}
 
 	newContainer, err := s.repository.Create(creationModel)
@@ -254,7 +479,7 @@ func (s *service) Start(ctx context.Context, req *pb.ContainerIdentificationRequ
 		return nil, err
 	}
 
-	if err := s.configurator.Configure(newContainer, subnetwork); err != nil {
+	if err := s.configurator.Configure(ctx, newContainer, subnetwork); err != nil {
 		return nil, err
 	}
 
@@ -262,29 +487,55 @@ func (s *service) Start(ctx context.Context, req *pb.ContainerIdentificationRequ
 		return nil, err
 	}
 
-	return mapModelToDto(newContainer)
+	dto, err := mapModelToDto(newContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(pb.EventType_MODIFIED, dto)
+
+	return dto, nil
 }
 
 func (s *service) Stop(ctx context.Context, req *pb.ContainerIdentificationRequest) (*pb.Container, error) {
-	container, err := s.repository.Get(req.Id)
+	id, err := s.resolveId(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.locks.Lock(id)()
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := interfaces.CheckResourceVersion(req.ResourceVersion, container.GetData().ResourceVersion); err != nil {
+		return nil, err
+	}
+
 	state, err := container.GetState()
 	if err != nil {
 		return nil, err
 	}
 
 	if state.Status != runspecs.StateRunning {
-		return nil, fmt.Errorf("can't stop a container that is not %q", runspecs.StateRunning)
+		return nil, apierrors.FailedPrecondition("can't stop a container that is not %q", runspecs.StateRunning)
 	}
 
-	if err := container.Stop(); err != nil {
+	container, err = s.repository.Stop(id)
+	if err != nil {
 		return nil, err
 	}
 
-	return mapModelToDto(container)
+	dto, err := mapModelToDto(container)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(pb.EventType_MODIFIED, dto)
+
+	return dto, nil
 }
 
 func mapModelToDto(container interfaces.ContainerModel) (*pb.Container, error) {
@@ -299,16 +550,109 @@ func mapModelToDto(container interfaces.ContainerModel) (*pb.Container, error) {
 	prefixLength, _ := data.Ip.Mask.Size()
 
 	return &pb.Container{
-		Id:           data.Id,
-		Address:      address,
-		PrefixLength: uint32(prefixLength),
-		Status:       string(state.Status),
-		Image:        data.Image,
-		StartedAt:    timestamppb.New(data.StartedAt),
-		CreatedAt:    timestamppb.New(data.CreatedAt),
-		SubnetworkId: data.SubnetworkId,
-		Entrypoint:   data.EntrypointCustomization.Entrypoint,
-		Cmd:          data.EntrypointCustomization.Cmd,
-		Env:          data.EntrypointCustomization.Env,
+		Id:              data.Id,
+		Address:         address,
+		PrefixLength:    uint32(prefixLength),
+		Status:          string(state.Status),
+		Image:           data.Image,
+		StartedAt:       timestamppb.New(data.StartedAt),
+		CreatedAt:       timestamppb.New(data.CreatedAt),
+		SubnetworkId:    data.SubnetworkId,
+		Entrypoint:      data.EntrypointCustomization.Entrypoint,
+		Cmd:             data.EntrypointCustomization.Cmd,
+		Env:             data.EntrypointCustomization.Env,
+		ResourceVersion: data.ResourceVersion,
+		Labels:          data.Labels,
+		Name:            data.Name,
+		ProjectId:       data.ProjectId,
+		IdempotencyKey:  data.IdempotencyKey,
+		CreatedBy:       data.CreatedBy,
+	}, nil
+}
+
+// Containers can be identified either by id or by name, containers of other projects are not found
+func (s *service) resolveId(ctx context.Context, req *pb.ContainerIdentificationRequest) (uint32, error) {
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ok := req.Identifier.(*pb.ContainerIdentificationRequest_Name); ok {
+		container, err := s.repository.GetByName(projectId, req.GetName())
+		if err != nil {
+			return 0, err
+		}
+
+		return container.GetData().Id, nil
+	}
+
+	container, err := s.repository.Get(req.GetId())
+	if err != nil {
+		return 0, err
+	}
+
+	if container.GetData().ProjectId != projectId {
+		return 0, apierrors.NotFound("could not find container with id %d", req.GetId())
+	}
+
+	return req.GetId(), nil
+}
+
+// Claims the name for a container that is about to be created, until the returned release function is called.
+// Covers the window in which the new container is not in the repository yet.
+func (s *service) reserveName(projectId uint32, name string) (func(), error) {
+	if name == "" {
+		return func() {}, nil
+	}
+
+	if err := interfaces.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	s.creatingNamesMu.Lock()
+	defer s.creatingNamesMu.Unlock()
+
+	key := creatingName{projectId: projectId, name: name}
+	if _, creating := s.creatingNames[key]; creating {
+		return nil, fmt.Errorf("%w: a container named %q is being created", interfaces.ErrNameTaken, name)
+	}
+
+	existing, err := s.repository.GetByName(projectId, name)
+	if err == nil {
+		return nil, fmt.Errorf("%w: container %d is already named %q", interfaces.ErrNameTaken, existing.GetData().Id, name)
+	}
+	// Only a name that is known to be free can be taken, any other failure could be hiding a container with the name
+	if status.Code(err) != codes.NotFound {
+		return nil, fmt.Errorf("failed to check whether the name %q is taken: %w", name, err)
+	}
+
+	s.creatingNames[key] = struct{}{}
+
+	return func() {
+		s.creatingNamesMu.Lock()
+		defer s.creatingNamesMu.Unlock()
+
+		delete(s.creatingNames, key)
 	}, nil
 }
+
+// Returns the container that an earlier request with the same idempotency key created, or nil if there is none
+func (s *service) createdWithKey(ctx context.Context, projectId uint32, key string) (*pb.Container, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	containers, err := shared.CollectAll(s.repository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, container := range containers {
+		data := container.GetData()
+		if data.ProjectId == projectId && data.IdempotencyKey == key && s.idempotency.Retains(data.CreatedAt) {
+			return mapModelToDto(container)
+		}
+	}
+
+	return nil, nil
+}
@@ -1,14 +1,17 @@
 package container
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"unsafe"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/metrics"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
 	runspecs "github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/sys/unix"
@@ -22,10 +25,15 @@ func (s *service) Exec(stream pb.ContainerService_ExecServer) error {
 	}
 	init := first.GetInitialization()
 	if init == nil {
-		return fmt.Errorf("first message in the stream is expected to be an initialization message")
+		return apierrors.InvalidArgument("initialization", fmt.Errorf("first message in the stream is expected to be an initialization message"))
 	}
 
-	container, err := s.repository.Get(init.Identification.Id)
+	id, err := s.resolveId(stream.Context(), init.Identification)
+	if err != nil {
+		return err
+	}
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve the container for command execution: %w", err)
 	}
@@ -62,7 +70,9 @@ func (s *service) Exec(stream pb.ContainerService_ExecServer) error {
 	pty := process.GetPty()
 	defer pty.Close()
 
-	log.Printf("Started an additional process in container %d", init.Identification.Id)
+	slog.InfoContext(stream.Context(), "Started an additional process", "container_id", id)
+	metrics.ExecSessions.Inc()
+	defer metrics.ExecSessions.Dec()
 
 	results := make(chan error, 2)
 	go func() {
@@ -111,7 +121,12 @@ func (s *service) Exec(stream pb.ContainerService_ExecServer) error {
 		}
 	}()
 
-	err = <-results
+	select {
+	case err = <-results:
+	case <-stream.Context().Done():
+		// The process is killed, but the container keeps running
+		err = context.Cause(stream.Context())
+	}
 
 	if err != nil {
 		if stopErr := process.Stop(); stopErr != nil {
@@ -137,13 +152,18 @@ func (s *service) Exec(stream pb.ContainerService_ExecServer) error {
 		return fmt.Errorf("failed to send the exit code: %w", err)
 	}
 
-	log.Printf("Successfully finished an additional process in container %d", init.Identification.Id)
+	slog.InfoContext(stream.Context(), "Finished an additional process", "container_id", id, "exit_code", exitCode)
 
 	return nil
 }
 
 func (s *service) Logs(req *pb.ContainerLogsRequest, stream grpc.ServerStreamingServer[pb.ContainerLogsResponse]) error {
-	container, err := s.repository.Get(req.Identification.Id)
+	id, err := s.resolveId(stream.Context(), req.Identification)
+	if err != nil {
+		return err
+	}
+
+	container, err := s.repository.Get(id)
 	if err != nil {
 		return err
 	}
@@ -176,22 +196,28 @@ func (s *service) Logs(req *pb.ContainerLogsRequest, stream grpc.ServerStreaming
 		return nil
 	}
 
-	fd, err := unix.InotifyInit()
+	// Non-blocking, so that reads go through the runtime poller and closing the file interrupts them
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
 	if err != nil {
 		return fmt.Errorf("failed to initialize log watching: %w", err)
 	}
-	defer unix.Close(fd)
+	changes := os.NewFile(uintptr(fd), "inotify")
+	defer changes.Close()
+	stop := context.AfterFunc(stream.Context(), func() { changes.Close() })
+	defer stop()
 
 	// We can't use IN_DELETE_SELF here, because it is not sent when the file is deleted (because inotify monitors inodes)
-	wd, err := unix.InotifyAddWatch(fd, f.Name(), unix.IN_MODIFY|unix.IN_ATTRIB)
-	if err != nil {
+	// Closing the inotify file removes the watch
+	if _, err := unix.InotifyAddWatch(fd, f.Name(), unix.IN_MODIFY|unix.IN_ATTRIB); err != nil {
 		return fmt.Errorf("failed to watch logs: %w", err)
 	}
-	defer unix.InotifyRmWatch(fd, uint32(wd))
 
 	for {
-		n, err := unix.Read(fd, buf)
+		n, err := changes.Read(buf)
 		if err != nil {
+			if stream.Context().Err() != nil {
+				return nil
+			}
 			return fmt.Errorf("failed to read new changes to logs: %w", err)
 		}
 
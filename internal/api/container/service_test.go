@@ -0,0 +1,117 @@
+package container_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/container"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeContainer struct {
+	interfaces.ContainerModel
+	data   *interfaces.ContainerModelData
+	status runspecs.ContainerState
+}
+
+func (c *fakeContainer) GetData() *interfaces.ContainerModelData {
+	return c.data
+}
+
+func (c *fakeContainer) GetState() (*runspecs.State, error) {
+	return &runspecs.State{Status: c.status}, nil
+}
+
+// Keeps containers in memory, stopping one bumps its resource version like the libcontainer repository does
+type fakeContainerRepository struct {
+	interfaces.ContainerRepository
+	containers   map[uint32]*fakeContainer
+	getByNameErr error
+}
+
+func (r *fakeContainerRepository) Get(id uint32) (interfaces.ContainerModel, error) {
+	container, ok := r.containers[id]
+	if !ok {
+		return nil, apierrors.NotFound("could not find container with id %d", id)
+	}
+	return container, nil
+}
+
+func (r *fakeContainerRepository) GetByName(projectId uint32, name string) (interfaces.ContainerModel, error) {
+	if r.getByNameErr != nil {
+		return nil, r.getByNameErr
+	}
+	for _, container := range r.containers {
+		if container.data.ProjectId == projectId && container.data.Name == name {
+			return container, nil
+		}
+	}
+	return nil, apierrors.NotFound("could not find container with name %q", name)
+}
+
+func (r *fakeContainerRepository) Stop(id uint32) (interfaces.ContainerModel, error) {
+	container, ok := r.containers[id]
+	if !ok {
+		return nil, apierrors.NotFound("could not find container with id %d", id)
+	}
+	container.status = runspecs.StateStopped
+	container.data.ResourceVersion++
+	return container, nil
+}
+
+func TestContainer_StopBumpsResourceVersion(t *testing.T) {
+	repository := &fakeContainerRepository{containers: map[uint32]*fakeContainer{
+		3: {
+			data: &interfaces.ContainerModelData{
+				Id:                      3,
+				Ip:                      &net.IPNet{IP: net.IPv4(10, 0, 0, 2).To4(), Mask: net.CIDRMask(24, 32)},
+				EntrypointCustomization: &interfaces.ContainerProcessCustomization{},
+				ResourceVersion:         1,
+				ProjectId:               project.DEFAULT_PROJECT_ID,
+			},
+			status: runspecs.StateRunning,
+		},
+	}}
+	service := container.NewService(repository, nil, nil, nil, nil, nil, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	version := uint64(1)
+	stopped, err := service.Stop(t.Context(), &pb.ContainerIdentificationRequest{
+		Identifier:      &pb.ContainerIdentificationRequest_Id{Id: 3},
+		ResourceVersion: &version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.ResourceVersion <= version {
+		t.Errorf("expected stopping to bump the resource version past %d, got %d", version, stopped.ResourceVersion)
+	}
+
+	_, err = service.Delete(t.Context(), &pb.ContainerIdentificationRequest{
+		Identifier:      &pb.ContainerIdentificationRequest_Id{Id: 3},
+		ResourceVersion: &version,
+	})
+	if code := status.Code(err); code != codes.Aborted {
+		t.Errorf("expected deleting with the version from before the stop to be aborted, got %v", err)
+	}
+}
+
+func TestContainer_Create_NameCheckFails(t *testing.T) {
+	loadErr := errors.New("failed to load the state of container 5")
+	repository := &fakeContainerRepository{getByNameErr: loadErr}
+	service := container.NewService(repository, subnetwork.NewMemoryRepository(nil), nil, nil, nil, nil, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	_, err := service.Create(t.Context(), &pb.ContainerCreationRequest{Name: "web", SubnetworkId: 1})
+	if !errors.Is(err, loadErr) {
+		t.Errorf("expected a failure to look the name up to fail the creation, got %v", err)
+	}
+}
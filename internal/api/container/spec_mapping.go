@@ -1,7 +1,6 @@
 package container
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -10,7 +9,7 @@ import (
 	runspecs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
-func imageSpecToRuntimeSpec(containerId uint32, rootFsDir string, img *imgspecs.ImageConfig) *runspecs.Spec {
+func imageSpecToRuntimeSpec(hostname string, rootFsDir string, img *imgspecs.ImageConfig) *runspecs.Spec {
 	dnsSource := "/etc/resolv.conf"
 	const systemdDnsSource = "/run/systemd/resolve/resolv.conf"
 	if _, err := os.Stat(systemdDnsSource); err == nil {
@@ -115,7 +114,7 @@ func imageSpecToRuntimeSpec(containerId uint32, rootFsDir string, img *imgspecs.
 				{Type: runspecs.NetworkNamespace},
 			},
 		},
-		Hostname: fmt.Sprintf("container-%d", containerId),
+		Hostname: hostname,
 	}
 
 	return spec
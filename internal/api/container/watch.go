@@ -0,0 +1,121 @@
+package container
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/events"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/grpc"
+)
+
+func (s *service) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.ContainerEvent]) error {
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	return events.Serve(stream.Context(), s.events, req.SinceRevision,
+		func() ([]*pb.Container, error) {
+			containers, err := shared.CollectAll(s.repository.GetAll(stream.Context()))
+			if err != nil {
+				return nil, err
+			}
+
+			dtos := make([]*pb.Container, 0, len(containers))
+			for _, container := range containers {
+				if container.GetData().ProjectId != projectId {
+					continue
+				}
+				dto, err := mapModelToDto(container)
+				if err != nil {
+					return nil, err
+				}
+				dtos = append(dtos, dto)
+			}
+			return dtos, nil
+		},
+		func(event events.Event[*pb.Container]) error {
+			if event.Object.ProjectId != projectId {
+				return nil
+			}
+			return stream.Send(&pb.ContainerEvent{
+				Type:      event.Type,
+				Revision:  event.Revision,
+				Container: event.Object,
+			})
+		},
+	)
+}
+
+// Periodically compares the status of every container with the last published one, until the context is cancelled.
+// Catches transitions that do not go through the API, such as the container process exiting on its own.
+func (s *service) WatchStatuses(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pollStatuses(ctx)
+		}
+	}
+}
+
+func (s *service) pollStatuses(ctx context.Context) {
+	containers, err := shared.CollectAll(s.repository.GetAll(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to retrieve containers for status polling", "error", err)
+		return
+	}
+
+	for _, listed := range containers {
+		s.pollStatus(listed.GetData().Id)
+	}
+}
+
+func (s *service) pollStatus(id uint32) {
+	// Prevents racing with Delete, Start and Stop, which publish their own events
+	defer s.locks.Lock(id)()
+
+	container, err := s.repository.Get(id)
+	if err != nil {
+		// Deleted since it was listed
+		return
+	}
+
+	dto, err := mapModelToDto(container)
+	if err != nil {
+		slog.Error("Failed to determine the status of a container", "container_id", id, "error", err)
+		return
+	}
+
+	s.statusesMu.Lock()
+	previous, known := s.statuses[id]
+	if !known {
+		// Containers that existed before the server started have no published status yet
+		s.statuses[id] = dto.Status
+	}
+	s.statusesMu.Unlock()
+
+	if known && previous != dto.Status {
+		s.publish(pb.EventType_MODIFIED, dto)
+	}
+}
+
+func (s *service) publish(eventType pb.EventType, dto *pb.Container) {
+	s.statusesMu.Lock()
+	if eventType == pb.EventType_DELETED {
+		delete(s.statuses, dto.Id)
+	} else {
+		s.statuses[dto.Id] = dto.Status
+	}
+	s.statusesMu.Unlock()
+
+	s.events.Publish(eventType, dto)
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc/codes"
+)
+
+// Number of most recent events retained for resuming watches
+const DEFAULT_HISTORY_SIZE = 1000
+
+// Revisions count events in their lower bits, the upper bits hold the epoch of the broker that published them
+const epochShift = 40
+
+var (
+	ErrRevisionUnavailable       = apierrors.New(codes.OutOfRange, "requested revision is no longer retained")
+	ErrRevisionOfAnotherInstance = apierrors.New(codes.OutOfRange, "requested revision was published by another instance of the API, which may have restarted")
+	ErrSubscriberTooSlow         = apierrors.New(codes.Unavailable, "subscriber did not keep up with the events")
+)
+
+type Event[T any] struct {
+	Type     pb.EventType
+	Revision uint64
+	Object   T
+}
+
+// Fans out resource change events to subscribers and retains the most recent ones so that watches can be resumed
+type Broker[T any] struct {
+	mu sync.Mutex
+	// Random for every broker, so that revisions of a restarted API can be told apart from the ones of the previous instance
+	epoch       uint64
+	revision    uint64
+	history     []Event[T]
+	historySize int
+	subscribers map[*Subscription[T]]struct{}
+}
+
+type Subscription[T any] struct {
+	broker *Broker[T]
+	events chan Event[T]
+	err    error
+}
+
+func NewBroker[T any](historySize int) *Broker[T] {
+	epoch := rand.Uint64N(1<<(64-epochShift)-1) + 1
+	return &Broker[T]{
+		epoch:       epoch,
+		revision:    epoch << epochShift,
+		history:     make([]Event[T], 0, historySize),
+		historySize: historySize,
+		subscribers: make(map[*Subscription[T]]struct{}),
+	}
+}
+
+func (b *Broker[T]) Publish(eventType pb.EventType, object T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revision++
+	event := Event[T]{
+		Type:     eventType,
+		Revision: b.revision,
+		Object:   object,
+	}
+
+	if len(b.history) == b.historySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, event)
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			// Blocking here would stall every other subscriber and the publisher
+			subscription.err = ErrSubscriberTooSlow
+			b.remove(subscription)
+		}
+	}
+}
+
+// Subscribes to the events published after the given revision, or to new events if it is nil.
+// Returns the revision the subscription starts from.
+func (b *Broker[T]) Subscribe(since *uint64) (*Subscription[T], uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []Event[T]
+	if since != nil {
+		if *since>>epochShift != b.epoch {
+			return nil, 0, ErrRevisionOfAnotherInstance
+		}
+
+		oldest := b.revision - uint64(len(b.history))
+		if *since < oldest || *since > b.revision {
+			return nil, 0, ErrRevisionUnavailable
+		}
+		missed = b.history[len(b.history)-int(b.revision-*since):]
+	}
+
+	subscription := &Subscription[T]{
+		broker: b,
+		events: make(chan Event[T], b.historySize+len(missed)),
+	}
+	for _, event := range missed {
+		subscription.events <- event
+	}
+	b.subscribers[subscription] = struct{}{}
+
+	return subscription, b.revision, nil
+}
+
+// Closed once the subscription ends, after which Err reports why
+func (s *Subscription[T]) Events() <-chan Event[T] {
+	return s.events
+}
+
+func (s *Subscription[T]) Err() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Subscription[T]) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+func (b *Broker[T]) remove(subscription *Subscription[T]) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
@@ -0,0 +1,127 @@
+package events_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/events"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Revisions do not start from 0, the broker's first revision is the one a subscription starts from before anything is published
+func startRevision[T any](t *testing.T, broker *events.Broker[T]) uint64 {
+	subscription, revision, err := broker.Subscribe(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription.Close()
+	return revision
+}
+
+func TestBroker_Subscribe_ReplaysMissedEvents(t *testing.T) {
+	broker := events.NewBroker[string](10)
+	start := startRevision(t, broker)
+	broker.Publish(pb.EventType_ADDED, "a")
+	broker.Publish(pb.EventType_MODIFIED, "b")
+	broker.Publish(pb.EventType_DELETED, "c")
+
+	since := start + 1
+	subscription, revision, err := broker.Subscribe(&since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+
+	if revision != start+3 {
+		t.Errorf("Expected the subscription to start from revision %d, got %d", start+3, revision)
+	}
+
+	broker.Publish(pb.EventType_ADDED, "d")
+
+	expected := []string{"b", "c", "d"}
+	for i, object := range expected {
+		event := <-subscription.Events()
+		if event.Object != object || event.Revision != start+uint64(i+2) {
+			t.Errorf("Expected %q at revision %d, got %q at revision %d", object, start+uint64(i+2), event.Object, event.Revision)
+		}
+	}
+}
+
+func TestBroker_Subscribe_RevisionNoLongerRetained(t *testing.T) {
+	broker := events.NewBroker[string](2)
+	start := startRevision(t, broker)
+	for _, object := range []string{"a", "b", "c", "d"} {
+		broker.Publish(pb.EventType_ADDED, object)
+	}
+
+	tests := map[string]uint64{
+		"evicted": start + 1,
+		"future":  start + 5,
+	}
+	for name, since := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := broker.Subscribe(&since)
+			if !errors.Is(err, events.ErrRevisionUnavailable) {
+				t.Errorf("Expected %v, got %v", events.ErrRevisionUnavailable, err)
+			}
+		})
+	}
+
+	since := start + 2
+	subscription, _, err := broker.Subscribe(&since)
+	if err != nil {
+		t.Fatalf("Expected the oldest retained revision to be resumable: %v", err)
+	}
+	subscription.Close()
+}
+
+func TestBroker_Subscribe_RevisionOfAnotherInstance(t *testing.T) {
+	previous := events.NewBroker[string](10)
+	for _, object := range []string{"a", "b", "c"} {
+		previous.Publish(pb.EventType_ADDED, object)
+	}
+	since := startRevision(t, previous)
+
+	// The restarted instance has published as many events, so the revision would otherwise look retained
+	restarted := events.NewBroker[string](10)
+	for _, object := range []string{"d", "e", "f"} {
+		restarted.Publish(pb.EventType_ADDED, object)
+	}
+
+	_, _, err := restarted.Subscribe(&since)
+	if !errors.Is(err, events.ErrRevisionOfAnotherInstance) {
+		t.Errorf("Expected %v, got %v", events.ErrRevisionOfAnotherInstance, err)
+	}
+	if code := status.Code(err); code != codes.OutOfRange {
+		t.Errorf("Expected %s, got %s", codes.OutOfRange, code)
+	}
+}
+
+func TestBroker_Publish_DropsSlowSubscriber(t *testing.T) {
+	broker := events.NewBroker[int](2)
+	subscription, _, err := broker.Subscribe(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 3 {
+		broker.Publish(pb.EventType_ADDED, i)
+	}
+
+	received := 0
+	for range subscription.Events() {
+		received++
+	}
+
+	if received != 2 {
+		t.Errorf("Expected the 2 buffered events before the subscription was closed, got %d", received)
+	}
+	if !errors.Is(subscription.Err(), events.ErrSubscriberTooSlow) {
+		t.Errorf("Expected %v, got %v", events.ErrSubscriberTooSlow, subscription.Err())
+	}
+
+	// Closing an already dropped subscription is a no-op
+	subscription.Close()
+}
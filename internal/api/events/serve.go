@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+)
+
+// Streams events to a single watcher until the context is cancelled.
+// Without a starting revision, the current resources (as listed by snapshot) are sent as ADDED events first,
+// so a resource that changes while the snapshot is taken may also be delivered again.
+func Serve[T any](
+	ctx context.Context,
+	broker *Broker[T],
+	since *uint64,
+	snapshot func() ([]T, error),
+	send func(Event[T]) error,
+) error {
+	subscription, revision, err := broker.Subscribe(since)
+	if err != nil {
+		return err
+	}
+	defer subscription.Close()
+
+	if since == nil {
+		objects, err := snapshot()
+		if err != nil {
+			return err
+		}
+
+		for _, object := range objects {
+			event := Event[T]{
+				Type:     pb.EventType_ADDED,
+				Revision: revision,
+				Object:   object,
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return fmt.Errorf("%w, resume from the last received revision", subscription.Err())
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
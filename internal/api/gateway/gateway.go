@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/logging"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Generated from the google.api.http annotations of the protos, see the pb-generate task
+//
+//go:embed bx2cloud.swagger.json
+var openapi []byte
+
+// Path that the OpenAPI document of the gateway is served at
+const OPENAPI_PATH = "/openapi.json"
+
+// HTTP headers that are passed on to the API as metadata of the same name.
+// The Authorization header is always passed on.
+var FORWARDED_HEADERS = []string{auth.PROJECT_METADATA_KEY, logging.REQUEST_ID_METADATA_KEY}
+
+const backendBufferSize = 1024 * 1024
+
+// Metadata key that the gateway passes the common name of the caller's verified client certificate in.
+// HTTP headers are never forwarded under it, so the backend can trust it.
+const certificateNameMetadataKey = "bx2cloud-gateway-certificate-name"
+
+// Serves the API over HTTP/JSON by calling a gRPC server of its own over an in-memory connection
+type server struct {
+	backend  *grpc.Server
+	listener *bufconn.Listener
+	conn     *grpc.ClientConn
+	http     *http.Server
+}
+
+// The services have to be registered on the backend before serving.
+// The options should be the API's server options without the transport credentials, so that the same interceptors run.
+// HTTPS is served if the TLS config is not nil, and callers identified by a client certificate keep that identity on the backend.
+func NewServer(tlsConfig *tls.Config, opts ...grpc.ServerOption) (*server, error) {
+	listener := bufconn.Listen(backendBufferSize)
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the gateway's connection: %w", err)
+	}
+
+	handler, err := NewHandler(context.Background(), conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server{
+		backend: grpc.NewServer(slices.Concat([]grpc.ServerOption{
+			// Runs before the API's interceptors, so that they see the caller's identity
+			grpc.ChainUnaryInterceptor(certificateNameUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(certificateNameStreamInterceptor()),
+		}, opts)...),
+		listener: listener,
+		conn:     conn,
+		http: &http.Server{
+			Handler:   handler,
+			TLSConfig: tlsConfig,
+		},
+	}, nil
+}
+
+func (s *server) Backend() *grpc.Server {
+	return s.backend
+}
+
+// Blocks until the server is stopped, returns nil if it was stopped on purpose
+func (s *server) Serve(lis net.Listener) error {
+	go func() {
+		_ = s.backend.Serve(s.listener)
+	}()
+
+	var err error
+	if s.http.TLSConfig != nil {
+		err = s.http.ServeTLS(lis, "", "")
+	} else {
+		err = s.http.Serve(lis)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Stops accepting requests and waits for the open ones to finish
+func (s *server) GracefulStop() {
+	_ = s.http.Shutdown(context.Background())
+	s.backend.GracefulStop()
+	_ = s.conn.Close()
+}
+
+// Cancels the open requests
+func (s *server) Stop() {
+	_ = s.http.Close()
+	s.backend.Stop()
+	_ = s.conn.Close()
+}
+
+// Serves every service of the API over HTTP/JSON by calling them over the connection, except for container Exec, which streams both ways.
+// Server streaming methods respond with newline delimited JSON objects.
+func NewHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(forwardedHeader),
+		runtime.WithMetadata(certificateName),
+	)
+
+	for _, register := range []func(context.Context, *runtime.ServeMux, *grpc.ClientConn) error{
+		pb.RegisterProjectServiceHandler,
+		pb.RegisterNetworkServiceHandler,
+		pb.RegisterSubnetworkServiceHandler,
+		pb.RegisterContainerServiceHandler,
+		pb.RegisterApplyServiceHandler,
+		pb.RegisterQuotaServiceHandler,
+		pb.RegisterIntrospectionServiceHandler,
+		pb.RegisterAdminServiceHandler,
+	} {
+		if err := register(ctx, mux, conn); err != nil {
+			return nil, fmt.Errorf("failed to register the gateway's routes: %w", err)
+		}
+	}
+
+	err := mux.HandlePath(http.MethodGet, OPENAPI_PATH, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openapi)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the OpenAPI document: %w", err)
+	}
+
+	return withRequestId(mux), nil
+}
+
+func forwardedHeader(key string) (string, bool) {
+	key = strings.ToLower(key)
+	return key, slices.Contains(FORWARDED_HEADERS, key)
+}
+
+// Passes on the common name of the caller's verified client certificate, which the backend can not see over its in-memory connection
+func certificateName(ctx context.Context, r *http.Request) metadata.MD {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return metadata.Pairs(certificateNameMetadataKey, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+}
+
+func certificateNameUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withCertificateName(ctx), req)
+	}
+}
+
+func certificateNameStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, shared.WithContext(stream, withCertificateName(stream.Context())))
+	}
+}
+
+func withCertificateName(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(certificateNameMetadataKey); len(values) == 1 {
+		return auth.WithCertificateName(ctx, values[0])
+	}
+	return ctx
+}
+
+// The request id is chosen before the call, so that it can be returned in the headers even if the response is streamed
+func withRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.REQUEST_ID_METADATA_KEY)
+		if id == "" || len(id) > logging.MAX_REQUEST_ID_LENGTH {
+			id = logging.NewRequestId()
+			r.Header.Set(logging.REQUEST_ID_METADATA_KEY, id)
+		}
+		w.Header().Set(logging.REQUEST_ID_METADATA_KEY, id)
+
+		next.ServeHTTP(w, r)
+	})
+}
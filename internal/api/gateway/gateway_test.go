@@ -0,0 +1,353 @@
+package gateway_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/gateway"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/logging"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(logging.UnaryInterceptor()),
+		grpc.StreamInterceptor(logging.StreamInterceptor()),
+	)
+	pb.RegisterNetworkServiceServer(server, network.NewService(
+		network.NewMemoryRepository(nil),
+		subnetwork.NewMemoryRepository(nil),
+		network.NewMockConfigurator(),
+		quota.NewMockChecker(),
+		idempotency.NewMockTracker(),
+	))
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///test",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	handler, err := gateway.NewHandler(t.Context(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// Returns the id of the created network
+func createNetwork(t *testing.T, server *httptest.Server, body string) string {
+	t.Helper()
+
+	resp := request(t, http.MethodPost, server.URL+"/v1/networks", body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var created struct {
+		Id uint32 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	return strconv.FormatUint(uint64(created.Id), 10)
+}
+
+func TestGateway_CreateAndGet(t *testing.T) {
+	server := newGateway(t)
+	id := createNetwork(t, server, `{"name": "web", "internetAccess": true}`)
+
+	for _, path := range []string{"/v1/networks/" + id, "/v1/networks/by-name/web"} {
+		resp := request(t, http.MethodGet, server.URL+path, "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", path, http.StatusOK, resp.StatusCode)
+		}
+
+		var got struct {
+			Id             uint32 `json:"id"`
+			Name           string `json:"name"`
+			InternetAccess bool   `json:"internetAccess"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if strconv.FormatUint(uint64(got.Id), 10) != id || got.Name != "web" || !got.InternetAccess {
+			t.Errorf("%s: unexpected network %+v", path, got)
+		}
+	}
+}
+
+func TestGateway_UpdateAndDelete(t *testing.T) {
+	server := newGateway(t)
+	id := createNetwork(t, server, `{"name": "web"}`)
+
+	resp := request(t, http.MethodPut, server.URL+"/v1/networks/by-name/web", `{"name": "web", "internetAccess": true}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp = request(t, http.MethodDelete, server.URL+"/v1/networks/"+id+"?resourceVersion=1", "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status %d for a stale resource version, got %d", http.StatusConflict, resp.StatusCode)
+	}
+
+	resp = request(t, http.MethodDelete, server.URL+"/v1/networks/"+id, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp = request(t, http.MethodGet, server.URL+"/v1/networks/"+id, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d after deletion, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestGateway_NotFound(t *testing.T) {
+	server := newGateway(t)
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/networks/42", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestGateway_ListIsNewlineDelimited(t *testing.T) {
+	server := newGateway(t)
+	for _, name := range []string{"a", "b", "c"} {
+		createNetwork(t, server, `{"name": "`+name+`"}`)
+	}
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/networks?orderBy=name%20desc", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line struct {
+			Result struct {
+				Name string `json:"name"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, line.Result.Name)
+	}
+
+	if strings.Join(names, ",") != "c,b,a" {
+		t.Errorf("expected networks c,b,a, got %v", names)
+	}
+}
+
+func TestGateway_RequestId(t *testing.T) {
+	server := newGateway(t)
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/networks/42", "")
+	if resp.Header.Get(logging.REQUEST_ID_METADATA_KEY) == "" {
+		t.Error("expected a generated request id")
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/v1/networks/42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(logging.REQUEST_ID_METADATA_KEY, "my-id")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get(logging.REQUEST_ID_METADATA_KEY); got != "my-id" {
+		t.Errorf("expected request id my-id, got %q", got)
+	}
+}
+
+func TestGateway_OpenApi(t *testing.T) {
+	server := newGateway(t)
+
+	resp := request(t, http.MethodGet, server.URL+gateway.OPENAPI_PATH, "")
+	var document struct {
+		Paths map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/v1/networks", "/v1/subnetworks/{id}", "/v1/containers/{identification.id}/logs", "/v1/projects/by-name/{name}", "/v1/quota/usage", "/v1:apply"} {
+		if _, ok := document.Paths[path]; !ok {
+			t.Errorf("expected the document to describe %s", path)
+		}
+	}
+}
+
+func newCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, key
+}
+
+// Returns a certificate for the common name signed by the CA
+func newCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, commonName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestGateway_ClientCertificateIdentity(t *testing.T) {
+	ca, caKey := newCA(t)
+	serverCert := newCertificate(t, ca, caKey, "server", x509.ExtKeyUsageServerAuth)
+	clientCert := newCertificate(t, ca, caKey, "alice", x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	var mu sync.Mutex
+	identities := make([]string, 0)
+	server, err := gateway.NewServer(
+		&tls.Config{Certificates: []tls.Certificate{serverCert}, ClientCAs: pool, ClientAuth: tls.VerifyClientCertIfGiven},
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			mu.Lock()
+			identities = append(identities, auth.Identity(ctx))
+			mu.Unlock()
+			return handler(ctx, req)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb.RegisterNetworkServiceServer(server.Backend(), network.NewService(
+		network.NewMemoryRepository(nil),
+		subnetwork.NewMemoryRepository(nil),
+		network.NewMockConfigurator(),
+		quota.NewMockChecker(),
+		idempotency.NewMockTracker(),
+	))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	url := "https://" + lis.Addr().String() + "/v1/networks/42"
+	for _, certificates := range [][]tls.Certificate{{clientCert}, nil} {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: certificates}}}
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Callers can not claim an identity by setting the gateway's metadata themselves
+		req.Header.Set("bx2cloud-gateway-certificate-name", "mallory")
+		req.Header.Set("Grpc-Metadata-bx2cloud-gateway-certificate-name", "mallory")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(identities, ",") != "alice," {
+		t.Errorf("expected the identities alice and none, got %q", identities)
+	}
+}
@@ -0,0 +1,273 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+)
+
+type networkConfigurator interface {
+	ListConfigured() ([]uint32, error)
+	Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error
+}
+
+type subnetworkConfigurator interface {
+	ListConfigured(networkId uint32) ([]uint32, error)
+	Unconfigure(ctx context.Context, model *interfaces.SubnetworkModel) error
+}
+
+type containerConfigurator interface {
+	ListConfigured(networkId uint32) ([]uint32, error)
+	UnconfigureOrphan(ctx context.Context, id uint32, networkId uint32) error
+}
+
+type rootFsStore interface {
+	ListRootFs() (map[uint32]time.Time, error)
+	RemoveRootFs(id uint32) error
+}
+
+type logStore interface {
+	List() (map[uint32]time.Time, error)
+	Remove(containerId uint32) error
+}
+
+const (
+	KIND_NETWORK    = "network"
+	KIND_SUBNETWORK = "subnetwork"
+	KIND_CONTAINER  = "container"
+	KIND_ROOTFS     = "rootfs"
+	KIND_LOG        = "log"
+)
+
+type collector struct {
+	mu                     sync.Mutex
+	networkRepository      interfaces.NetworkRepository
+	subnetworkRepository   interfaces.SubnetworkRepository
+	containerRepository    interfaces.ContainerRepository
+	networkConfigurator    networkConfigurator
+	subnetworkConfigurator subnetworkConfigurator
+	containerConfigurator  containerConfigurator
+	rootFsStore            rootFsStore
+	logStore               logStore
+	// Host resources that have been orphaned for less than this are left alone, since they may belong to a resource that is still being created
+	gracePeriod time.Duration
+	// When each orphan without a modification time, such as a namespace or a link, was first found
+	firstSeen map[string]time.Time
+}
+
+func NewCollector(
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+	networkConfigurator networkConfigurator,
+	subnetworkConfigurator subnetworkConfigurator,
+	containerConfigurator containerConfigurator,
+	rootFsStore rootFsStore,
+	logStore logStore,
+	gracePeriod time.Duration,
+) *collector {
+	return &collector{
+		networkRepository:      networkRepository,
+		subnetworkRepository:   subnetworkRepository,
+		containerRepository:    containerRepository,
+		networkConfigurator:    networkConfigurator,
+		subnetworkConfigurator: subnetworkConfigurator,
+		containerConfigurator:  containerConfigurator,
+		rootFsStore:            rootFsStore,
+		logStore:               logStore,
+		gracePeriod:            gracePeriod,
+		firstSeen:              make(map[string]time.Time),
+	}
+}
+
+// Finds host artifacts that have no owning resource and removes them, unless dryRun is set.
+// Host artifacts are listed before the repositories, so that a resource created in between is never seen as an orphan.
+// Only artifacts that have been orphaned for the grace period are reported, files by their modification time
+// and other artifacts since the collection that first found them, so those are only removed by a later collection.
+func (c *collector) Collect(ctx context.Context, dryRun bool) ([]*pb.Orphan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hostNetworkIds, err := c.networkConfigurator.ListConfigured()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks on the host: %w", err)
+	}
+
+	hostSubnetworkIds := make(map[uint32][]uint32)
+	hostContainerIds := make(map[uint32][]uint32)
+	for _, networkId := range hostNetworkIds {
+		if ids, err := c.subnetworkConfigurator.ListConfigured(networkId); err == nil {
+			hostSubnetworkIds[networkId] = ids
+		}
+		if ids, err := c.containerConfigurator.ListConfigured(networkId); err == nil {
+			hostContainerIds[networkId] = ids
+		}
+	}
+
+	rootFs, err := c.rootFsStore.ListRootFs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container rootfs: %w", err)
+	}
+
+	logFiles, err := c.logStore.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container log files: %w", err)
+	}
+
+	networks, err := shared.CollectAll(c.networkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve networks: %w", err)
+	}
+
+	subnetworks, err := shared.CollectAll(c.subnetworkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve subnetworks: %w", err)
+	}
+
+	containers, err := shared.CollectAll(c.containerRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve containers: %w", err)
+	}
+
+	knownNetworks := make(map[uint32]struct{}, len(networks))
+	for _, network := range networks {
+		knownNetworks[network.Id] = struct{}{}
+	}
+
+	// Subnetwork id -> network id
+	knownSubnetworks := make(map[uint32]uint32, len(subnetworks))
+	for _, subnetwork := range subnetworks {
+		knownSubnetworks[subnetwork.Id] = subnetwork.NetworkId
+	}
+
+	// Container id -> network id
+	knownContainers := make(map[uint32]uint32, len(containers))
+	// Containers whose network can not be resolved, their host artifacts are never seen as orphans
+	unresolvedContainers := make(map[uint32]struct{})
+	for _, container := range containers {
+		data := container.GetData()
+		networkId, ok := knownSubnetworks[data.SubnetworkId]
+		if !ok {
+			unresolvedContainers[data.Id] = struct{}{}
+			slog.WarnContext(ctx, "Found a container whose subnetwork does not exist, leaving its host artifacts alone", "id", data.Id, "subnetwork_id", data.SubnetworkId)
+			continue
+		}
+		knownContainers[data.Id] = networkId
+	}
+	known := func(containerId uint32) bool {
+		_, known := knownContainers[containerId]
+		_, unresolved := unresolvedContainers[containerId]
+		return known || unresolved
+	}
+
+	orphans := make([]*pb.Orphan, 0)
+	report := func(kind string, id uint32, location string, remove func() error) {
+		orphan := &pb.Orphan{
+			Kind:     kind,
+			Id:       id,
+			Location: location,
+		}
+		orphans = append(orphans, orphan)
+
+		if dryRun {
+			slog.InfoContext(ctx, "Found an orphan", "kind", kind, "id", id, "location", location)
+			return
+		}
+
+		if err := remove(); err != nil {
+			orphan.Error = err.Error()
+			slog.ErrorContext(ctx, "Failed to remove an orphan", "kind", kind, "id", id, "location", location, "error", err)
+			return
+		}
+
+		orphan.Removed = true
+		slog.InfoContext(ctx, "Removed an orphan", "kind", kind, "id", id, "location", location)
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-c.gracePeriod)
+
+	firstSeen := make(map[string]time.Time)
+	expired := func(kind string, id uint32, networkId uint32) bool {
+		key := fmt.Sprintf("%s/%d/%d", kind, networkId, id)
+		seen, ok := c.firstSeen[key]
+		if !ok {
+			seen = now
+		}
+		firstSeen[key] = seen
+		return !seen.After(cutoff)
+	}
+	// Artifacts that are no longer orphaned are forgotten
+	defer func() { c.firstSeen = firstSeen }()
+
+	for _, networkId := range hostNetworkIds {
+		location := fmt.Sprintf("network namespace of network %d", networkId)
+
+		if _, ok := knownNetworks[networkId]; !ok {
+			if !expired(KIND_NETWORK, networkId, networkId) {
+				continue
+			}
+
+			// Removing the namespace also removes the bridges and veths inside of it
+			report(KIND_NETWORK, networkId, location, func() error {
+				return c.networkConfigurator.Unconfigure(ctx, &interfaces.NetworkModel{Id: networkId})
+			})
+			continue
+		}
+
+		for _, subnetworkId := range hostSubnetworkIds[networkId] {
+			if owner, ok := knownSubnetworks[subnetworkId]; ok && owner == networkId || !expired(KIND_SUBNETWORK, subnetworkId, networkId) {
+				continue
+			}
+
+			report(KIND_SUBNETWORK, subnetworkId, location, func() error {
+				return c.subnetworkConfigurator.Unconfigure(ctx, &interfaces.SubnetworkModel{Id: subnetworkId, NetworkId: networkId})
+			})
+		}
+
+		for _, containerId := range hostContainerIds[networkId] {
+			if _, ok := unresolvedContainers[containerId]; ok {
+				continue
+			}
+			if owner, ok := knownContainers[containerId]; ok && owner == networkId || !expired(KIND_CONTAINER, containerId, networkId) {
+				continue
+			}
+
+			report(KIND_CONTAINER, containerId, location, func() error {
+				return c.containerConfigurator.UnconfigureOrphan(ctx, containerId, networkId)
+			})
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(rootFs)) {
+		if known(id) || rootFs[id].After(cutoff) {
+			continue
+		}
+
+		report(KIND_ROOTFS, id, "rootfs directory", func() error {
+			return c.rootFsStore.RemoveRootFs(id)
+		})
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(logFiles)) {
+		if known(id) || logFiles[id].After(cutoff) {
+			continue
+		}
+
+		report(KIND_LOG, id, "log file", func() error {
+			return c.logStore.Remove(id)
+		})
+	}
+
+	slog.InfoContext(ctx, "Garbage collection finished", "orphans", len(orphans), "dry_run", dryRun)
+
+	return orphans, nil
+}
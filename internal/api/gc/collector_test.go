@@ -0,0 +1,241 @@
+package gc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/gc"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+type fakeHost struct {
+	networks []uint32
+	rootFs   map[uint32]time.Time
+}
+
+func (h *fakeHost) ListConfigured() ([]uint32, error) {
+	return h.networks, nil
+}
+
+func (h *fakeHost) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
+	for i, id := range h.networks {
+		if id == model.Id {
+			h.networks = append(h.networks[:i], h.networks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (h *fakeHost) ListRootFs() (map[uint32]time.Time, error) {
+	return h.rootFs, nil
+}
+
+func (h *fakeHost) RemoveRootFs(id uint32) error {
+	delete(h.rootFs, id)
+	return nil
+}
+
+type emptyHost struct{}
+
+func (h *emptyHost) ListConfigured(networkId uint32) ([]uint32, error) {
+	return nil, nil
+}
+
+func (h *emptyHost) Unconfigure(ctx context.Context, model *interfaces.SubnetworkModel) error {
+	return nil
+}
+
+func (h *emptyHost) UnconfigureOrphan(ctx context.Context, id uint32, networkId uint32) error {
+	return nil
+}
+
+func (h *emptyHost) List() (map[uint32]time.Time, error) {
+	return map[uint32]time.Time{}, nil
+}
+
+func (h *emptyHost) Remove(containerId uint32) error {
+	return nil
+}
+
+type emptyContainerRepository struct {
+	interfaces.ContainerRepository
+}
+
+func (r *emptyContainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	results := make(chan interfaces.ContainerModel)
+	errChan := make(chan error)
+	close(results)
+	close(errChan)
+	return results, errChan
+}
+
+func TestCollector_DryRunThenCollect(t *testing.T) {
+	host := &fakeHost{
+		networks: []uint32{1, 2},
+		rootFs: map[uint32]time.Time{
+			5: time.Now().Add(-time.Hour),
+		},
+	}
+
+	collector := gc.NewCollector(
+		network.NewMemoryRepository([]*interfaces.NetworkModel{{Id: 1}}),
+		subnetwork.NewMemoryRepository(nil),
+		&emptyContainerRepository{},
+		host,
+		&emptyHost{},
+		&emptyHost{},
+		host,
+		&emptyHost{},
+		0,
+	)
+
+	orphans, err := collector.Collect(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*pb.Orphan{
+		{Kind: gc.KIND_NETWORK, Id: 2, Location: "network namespace of network 2"},
+		{Kind: gc.KIND_ROOTFS, Id: 5, Location: "rootfs directory"},
+	}
+	if diff := cmp.Diff(expected, orphans, protocmp.Transform()); diff != "" {
+		t.Errorf("dry run orphans mismatch (-want +got):\n%s", diff)
+	}
+	if len(host.networks) != 2 || len(host.rootFs) != 1 {
+		t.Fatal("Dry run removed host resources")
+	}
+
+	orphans, err = collector.Collect(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, orphan := range orphans {
+		if !orphan.Removed {
+			t.Errorf("Orphan %s %d was not removed: %s", orphan.Kind, orphan.Id, orphan.Error)
+		}
+	}
+	if len(host.networks) != 1 || len(host.rootFs) != 0 {
+		t.Errorf("Orphans are still present on the host: networks %v, rootfs %v", host.networks, host.rootFs)
+	}
+}
+
+func TestCollector_GracePeriod(t *testing.T) {
+	host := &fakeHost{
+		networks: []uint32{1, 2},
+		rootFs: map[uint32]time.Time{
+			5: time.Now(),
+		},
+	}
+
+	collector := gc.NewCollector(
+		network.NewMemoryRepository([]*interfaces.NetworkModel{{Id: 1}}),
+		subnetwork.NewMemoryRepository(nil),
+		&emptyContainerRepository{},
+		host,
+		&emptyHost{},
+		&emptyHost{},
+		host,
+		&emptyHost{},
+		10*time.Minute,
+	)
+
+	// The namespace is only found now, so it has not been orphaned for the grace period yet, no matter how many collections see it
+	for range 2 {
+		orphans, err := collector.Collect(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(orphans) != 0 {
+			t.Errorf("Expected no orphans within the grace period, got %v", orphans)
+		}
+	}
+	if len(host.networks) != 2 || len(host.rootFs) != 1 {
+		t.Errorf("Host resources within the grace period were removed: networks %v, rootfs %v", host.networks, host.rootFs)
+	}
+}
+
+type fakeContainer struct {
+	interfaces.ContainerModel
+	data *interfaces.ContainerModelData
+}
+
+func (c *fakeContainer) GetData() *interfaces.ContainerModelData {
+	return c.data
+}
+
+type fakeContainerRepository struct {
+	interfaces.ContainerRepository
+	containers []interfaces.ContainerModel
+}
+
+func (r *fakeContainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	results := make(chan interfaces.ContainerModel, len(r.containers))
+	errChan := make(chan error)
+	for _, container := range r.containers {
+		results <- container
+	}
+	close(results)
+	close(errChan)
+	return results, errChan
+}
+
+// Lists the same containers in every network, remembering the ones that were removed
+type fakeContainerHost struct {
+	emptyHost
+	containers []uint32
+	removed    []uint32
+}
+
+func (h *fakeContainerHost) ListConfigured(networkId uint32) ([]uint32, error) {
+	return h.containers, nil
+}
+
+func (h *fakeContainerHost) UnconfigureOrphan(ctx context.Context, id uint32, networkId uint32) error {
+	h.removed = append(h.removed, id)
+	return nil
+}
+
+func TestCollector_ContainerWithoutSubnetwork(t *testing.T) {
+	host := &fakeHost{
+		networks: []uint32{1},
+		rootFs: map[uint32]time.Time{
+			3: time.Now().Add(-time.Hour),
+		},
+	}
+	containerHost := &fakeContainerHost{containers: []uint32{3}}
+
+	collector := gc.NewCollector(
+		network.NewMemoryRepository([]*interfaces.NetworkModel{{Id: 1}}),
+		subnetwork.NewMemoryRepository(nil),
+		&fakeContainerRepository{containers: []interfaces.ContainerModel{
+			&fakeContainer{data: &interfaces.ContainerModelData{Id: 3, SubnetworkId: 9}},
+		}},
+		host,
+		&emptyHost{},
+		containerHost,
+		host,
+		&emptyHost{},
+		0,
+	)
+
+	// The container's network can not be resolved, which must not make its veth look like it belongs to another network
+	for range 2 {
+		orphans, err := collector.Collect(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(orphans) != 0 {
+			t.Errorf("Expected the container's host artifacts to not be orphans, got %v", orphans)
+		}
+	}
+	if len(containerHost.removed) != 0 || len(host.rootFs) != 1 {
+		t.Errorf("Host artifacts of the container were removed: veths %v, rootfs %v", containerHost.removed, host.rootFs)
+	}
+}
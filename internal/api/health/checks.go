@@ -0,0 +1,31 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+)
+
+// Fails if a file can not be created in the directory, e.g. because its disk is full or it was mounted read-only
+func DirectoryWritable(dir string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		f, err := os.CreateTemp(dir, ".health-*")
+		if err != nil {
+			return fmt.Errorf("failed to create a file in %s: %w", dir, err)
+		}
+		f.Close()
+
+		return os.Remove(f.Name())
+	}
+}
+
+// Fails if the state of any container can not be loaded
+func ContainersLoadable(repository interfaces.ContainerRepository) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := shared.CollectAll(repository.GetAll(ctx))
+		return err
+	}
+}
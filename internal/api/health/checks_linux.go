@@ -0,0 +1,24 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coreos/go-iptables/iptables"
+)
+
+// Fails if the chains of the filter table can not be listed, e.g. because the iptables binary or the kernel modules are gone
+func IptablesUsable() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ipt, err := iptables.NewWithProtocol(iptables.ProtocolIPv4)
+		if err != nil {
+			return fmt.Errorf("failed to create iptables instance: %w", err)
+		}
+
+		if _, err := ipt.ListChains("filter"); err != nil {
+			return fmt.Errorf("failed to list the iptables chains: %w", err)
+		}
+
+		return nil
+	}
+}
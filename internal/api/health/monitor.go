@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// A dependency of some of the API's services, such as a directory they write to
+type Check struct {
+	Name string
+	// Fully qualified names of the services that can not work while the check fails, e.g. "bx2cloud.NetworkService"
+	Services []string
+	Probe    func(ctx context.Context) error
+}
+
+// Keeps the statuses of a health server in line with the checks, a service is serving while all of its checks pass.
+// The overall status, of the "" service, is serving while all checks pass.
+type monitor struct {
+	server   *grpchealth.Server
+	services []string
+	checks   []Check
+	// Errors of the last run, to only log changes
+	failing map[string]error
+}
+
+func NewMonitor(server *grpchealth.Server, services []string, checks ...Check) *monitor {
+	return &monitor{
+		server:   server,
+		services: services,
+		checks:   checks,
+		failing:  make(map[string]error),
+	}
+}
+
+// Updates the statuses every interval, until the context is cancelled
+func (m *monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Update(ctx)
+		}
+	}
+}
+
+// Runs every check once and sets the statuses from the results
+func (m *monitor) Update(ctx context.Context) {
+	unhealthy := make([]string, 0)
+	failing := make(map[string]error)
+	for _, check := range m.checks {
+		err := check.Probe(ctx)
+		if err == nil {
+			if _, failed := m.failing[check.Name]; failed {
+				slog.InfoContext(ctx, "Health check passes again", "check", check.Name)
+			}
+			continue
+		}
+
+		if _, failed := m.failing[check.Name]; !failed {
+			slog.WarnContext(ctx, "Health check failed", "check", check.Name, "services", check.Services, "error", err)
+		}
+		failing[check.Name] = err
+		unhealthy = append(unhealthy, check.Services...)
+	}
+	m.failing = failing
+
+	for _, service := range m.services {
+		m.server.SetServingStatus(service, status(!slices.Contains(unhealthy, service)))
+	}
+	m.server.SetServingStatus("", status(len(failing) == 0))
+}
+
+func status(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,67 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/health"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestMonitor_Update(t *testing.T) {
+	server := grpchealth.NewServer()
+	broken := errors.New("iptables is gone")
+	var iptablesErr error
+
+	monitor := health.NewMonitor(server, []string{"bx2cloud.NetworkService", "bx2cloud.ContainerService"},
+		health.Check{
+			Name:     "iptables",
+			Services: []string{"bx2cloud.NetworkService"},
+			Probe:    func(ctx context.Context) error { return iptablesErr },
+		},
+		health.Check{
+			Name:     "logs directory",
+			Services: []string{"bx2cloud.ContainerService"},
+			Probe:    health.DirectoryWritable(t.TempDir()),
+		},
+	)
+
+	expect := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := server.Check(t.Context(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != want {
+			t.Errorf("expected %q to be %s, got %s", service, want, resp.Status)
+		}
+	}
+
+	monitor.Update(t.Context())
+	expect("", healthpb.HealthCheckResponse_SERVING)
+	expect("bx2cloud.NetworkService", healthpb.HealthCheckResponse_SERVING)
+
+	iptablesErr = broken
+	monitor.Update(t.Context())
+	expect("", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("bx2cloud.NetworkService", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("bx2cloud.ContainerService", healthpb.HealthCheckResponse_SERVING)
+
+	iptablesErr = nil
+	monitor.Update(t.Context())
+	expect("", healthpb.HealthCheckResponse_SERVING)
+	expect("bx2cloud.NetworkService", healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestDirectoryWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := health.DirectoryWritable(dir)(t.Context()); err != nil {
+		t.Errorf("expected the directory to be writable, got %v", err)
+	}
+
+	if err := health.DirectoryWritable(dir + "/missing")(t.Context()); err == nil {
+		t.Error("expected a missing directory to fail the check")
+	}
+}
@@ -43,3 +43,32 @@ func Skip(counterKey string, delta uint32) uint32 {
 
 	return atomic.AddUint32(counter, delta)
 }
+
+// Moves the counter forward so that the next id is greater than value.
+// Has no effect if the counter is already past value.
+func Restore(counterKey string, value uint32) uint32 {
+	mu.RLock()
+	counter, exists := counters[counterKey]
+	mu.RUnlock()
+
+	if !exists {
+		mu.Lock()
+		if counter, exists = counters[counterKey]; !exists {
+			var initial uint32
+			counter = &initial
+			counters[counterKey] = counter
+		}
+		mu.Unlock()
+	}
+
+	for {
+		current := atomic.LoadUint32(counter)
+		if current >= value {
+			return current
+		}
+
+		if atomic.CompareAndSwapUint32(counter, current, value) {
+			return value
+		}
+	}
+}
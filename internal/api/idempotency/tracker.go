@@ -0,0 +1,114 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+)
+
+// Long enough for UUIDs and hashes, short enough to keep as a container label
+const MAX_KEY_LENGTH = 128
+
+var ErrInvalidKey = errors.New("invalid idempotency key")
+
+var _ interfaces.IdempotencyTracker = &tracker{}
+
+// Only tracks creations that are in progress, created resources keep their key themselves
+type tracker struct {
+	retention time.Duration
+	mu        sync.Mutex
+	// Closed once the creation with the key finished
+	claimed map[claim]chan struct{}
+}
+
+type claim struct {
+	projectId uint32
+	kind      interfaces.IdempotencyKind
+	key       string
+}
+
+func NewTracker(retention time.Duration) *tracker {
+	return &tracker{
+		retention: retention,
+		claimed:   make(map[claim]chan struct{}),
+	}
+}
+
+func (t *tracker) Claim(ctx context.Context, projectId uint32, kind interfaces.IdempotencyKind, key string) (func(), error) {
+	if key == "" {
+		return func() {}, nil
+	}
+
+	if err := Validate(key); err != nil {
+		return nil, apierrors.InvalidArgument("idempotency_key", err)
+	}
+
+	c := claim{projectId: projectId, kind: kind, key: key}
+	for {
+		t.mu.Lock()
+		done, inProgress := t.claimed[c]
+		if !inProgress {
+			done = make(chan struct{})
+			t.claimed[c] = done
+			t.mu.Unlock()
+			break
+		}
+		t.mu.Unlock()
+
+		// The repeated request returns whatever the first one created, or takes over if it failed
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		close(t.claimed[c])
+		delete(t.claimed, c)
+	}, nil
+}
+
+func (t *tracker) Retains(createdAt time.Time) bool {
+	return time.Since(createdAt) < t.retention
+}
+
+// Keys are 1 to MAX_KEY_LENGTH printable ASCII characters without spaces
+func Validate(key string) error {
+	if key == "" || len(key) > MAX_KEY_LENGTH {
+		return fmt.Errorf("%w: must be 1 to %d characters long", ErrInvalidKey, MAX_KEY_LENGTH)
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r > '~' {
+			return fmt.Errorf("%w %q: must only contain printable ASCII characters without spaces", ErrInvalidKey, key)
+		}
+	}
+
+	return nil
+}
+
+var _ interfaces.IdempotencyTracker = &mockTracker{}
+
+// Ignores idempotency keys, every request creates a new resource
+type mockTracker struct{}
+
+func NewMockTracker() interfaces.IdempotencyTracker {
+	return &mockTracker{}
+}
+
+func (m *mockTracker) Claim(ctx context.Context, projectId uint32, kind interfaces.IdempotencyKind, key string) (func(), error) {
+	return func() {}, nil
+}
+
+func (m *mockTracker) Retains(createdAt time.Time) bool {
+	return false
+}
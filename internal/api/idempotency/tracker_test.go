@@ -0,0 +1,96 @@
+package idempotency_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTracker_Claim_WaitsForTheSameKey(t *testing.T) {
+	tracker := idempotency.NewTracker(time.Hour)
+
+	release, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_NETWORKS, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed := make(chan struct{})
+	go func() {
+		release, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_NETWORKS, "key")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		release()
+		close(claimed)
+	}()
+
+	select {
+	case <-claimed:
+		t.Fatal("the key was claimed twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	<-claimed
+}
+
+func TestTracker_Claim_OtherScopes(t *testing.T) {
+	tracker := idempotency.NewTracker(time.Hour)
+
+	if _, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_NETWORKS, "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither should wait for the claim above
+	if _, err := tracker.Claim(t.Context(), 2, interfaces.IDEMPOTENCY_NETWORKS, "key"); err != nil {
+		t.Error(err)
+	}
+	if _, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_CONTAINERS, "key"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTracker_Claim_Cancelled(t *testing.T) {
+	tracker := idempotency.NewTracker(time.Hour)
+
+	if _, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_NETWORKS, "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := tracker.Claim(ctx, 1, interfaces.IDEMPOTENCY_NETWORKS, "key"); err == nil {
+		t.Error("expected the claim to give up once the context is done")
+	}
+}
+
+func TestTracker_Claim_InvalidKey(t *testing.T) {
+	tracker := idempotency.NewTracker(time.Hour)
+
+	for _, key := range []string{"with space", "new\nline", "ąžuolas", strings.Repeat("a", idempotency.MAX_KEY_LENGTH+1)} {
+		t.Run(key, func(t *testing.T) {
+			_, err := tracker.Claim(t.Context(), 1, interfaces.IDEMPOTENCY_NETWORKS, key)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected %v, got %v", codes.InvalidArgument, err)
+			}
+		})
+	}
+}
+
+func TestTracker_Retains(t *testing.T) {
+	tracker := idempotency.NewTracker(time.Hour)
+
+	if !tracker.Retains(time.Now().Add(-time.Minute)) {
+		t.Error("expected a resource created a minute ago to be retained")
+	}
+	if tracker.Retains(time.Now().Add(-2 * time.Hour)) {
+		t.Error("expected a resource created two hours ago to not be retained")
+	}
+}
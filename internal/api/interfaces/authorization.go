@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+type Authorizer interface {
+	// Fails with PERMISSION_DENIED if the caller may not call the method, e.g. "/bx2cloud.NetworkService/Create"
+	Authorize(ctx context.Context, fullMethod string) error
+}
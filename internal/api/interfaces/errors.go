@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrResourceVersionMismatch = apierrors.New(codes.Aborted, "resource version does not match")
+
+// Returns ErrResourceVersionMismatch if an expected version was given and it differs from the actual one
+func CheckResourceVersion(expected *uint64, actual uint64) error {
+	if expected != nil && *expected != actual {
+		return fmt.Errorf("%w: expected %d, but the current version is %d", ErrResourceVersionMismatch, *expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of resources that idempotency keys are scoped to, so the same key can be used to create resources of different kinds
+type IdempotencyKind string
+
+const (
+	IDEMPOTENCY_NETWORKS    IdempotencyKind = "networks"
+	IDEMPOTENCY_SUBNETWORKS IdempotencyKind = "subnetworks"
+	IDEMPOTENCY_CONTAINERS  IdempotencyKind = "containers"
+)
+
+type IdempotencyTracker interface {
+	// Fails with INVALID_ARGUMENT if the key is malformed. Otherwise waits until no other creation with the same key is in progress
+	// and claims the key until release is called, once the resource is created or the creation failed.
+	// Empty keys are never claimed.
+	Claim(ctx context.Context, projectId uint32, kind IdempotencyKind, key string) (release func(), err error)
+	// Whether a resource created with a key at the given time is still returned for repeated requests with that key
+	Retains(createdAt time.Time) bool
+}
@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+)
+
+// Filters always have a project, resources of different projects are never listed together
+type NetworkFilter struct {
+	ProjectId uint32
+}
+
+type SubnetworkFilter struct {
+	ProjectId uint32
+	NetworkId *uint32
+}
+
+type ContainerFilter struct {
+	ProjectId uint32
+	// nil matches containers in any subnetwork
+	SubnetworkIds []uint32
+	// Empty matches any status
+	Status string
+	// Empty matches any image
+	Image string
+}
+
+var ProjectOrderFields = listing.Fields[*ProjectModel]{
+	"id":         listing.By(func(p *ProjectModel) uint32 { return p.Id }),
+	"name":       listing.By(func(p *ProjectModel) string { return p.Name }),
+	"created_at": listing.By(func(p *ProjectModel) int64 { return p.CreatedAt.AsTime().UnixNano() }),
+}
+
+var NetworkOrderFields = listing.Fields[*NetworkModel]{
+	"id":         listing.By(func(n *NetworkModel) uint32 { return n.Id }),
+	"name":       listing.By(func(n *NetworkModel) string { return n.Name }),
+	"created_at": listing.By(func(n *NetworkModel) int64 { return n.CreatedAt.AsTime().UnixNano() }),
+}
+
+var SubnetworkOrderFields = listing.Fields[*SubnetworkModel]{
+	"id":         listing.By(func(s *SubnetworkModel) uint32 { return s.Id }),
+	"name":       listing.By(func(s *SubnetworkModel) string { return s.Name }),
+	"created_at": listing.By(func(s *SubnetworkModel) int64 { return s.CreatedAt.AsTime().UnixNano() }),
+	"network_id": listing.By(func(s *SubnetworkModel) uint32 { return s.NetworkId }),
+	"address":    listing.By(func(s *SubnetworkModel) uint32 { return s.Address }),
+}
+
+var ContainerOrderFields = listing.Fields[ContainerModel]{
+	"id":            listing.By(func(c ContainerModel) uint32 { return c.GetData().Id }),
+	"name":          listing.By(func(c ContainerModel) string { return c.GetData().Name }),
+	"created_at":    listing.By(func(c ContainerModel) int64 { return c.GetData().CreatedAt.UnixNano() }),
+	"image":         listing.By(func(c ContainerModel) string { return c.GetData().Image }),
+	"subnetwork_id": listing.By(func(c ContainerModel) uint32 { return c.GetData().SubnetworkId }),
+}
@@ -9,6 +9,7 @@ import (
 	runspecs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
+type ProjectModel = pb.Project
 type NetworkModel = pb.Network
 type SubnetworkModel = pb.Subnetwork
 
@@ -28,6 +29,12 @@ type ContainerModelData struct {
 	StartedAt               time.Time
 	EntrypointCustomization *ContainerProcessCustomization
 	Spec                    *runspecs.Spec
+	ResourceVersion         uint64
+	Labels                  map[string]string
+	Name                    string
+	ProjectId               uint32
+	IdempotencyKey          string
+	CreatedBy               string
 }
 
 type ContainerProcessCustomization struct {
@@ -39,12 +46,20 @@ type ContainerProcessCustomization struct {
 type ContainerModel interface {
 	GetData() *ContainerModelData
 	GetState() (*runspecs.State, error)
+	// Only running containers have stats
+	GetStats() (*ContainerStats, error)
 	// Executes the user program in a 'created' container
 	Exec() error
 	Stop() error
 	StartAdditionalProcess(process *runspecs.Process) (ContainerProcess, error)
 }
 
+// Resource usage of the container's cgroup
+type ContainerStats struct {
+	CpuUsage    time.Duration
+	MemoryBytes uint64
+}
+
 type ContainerProcess interface {
 	GetPty() *os.File
 	Wait() (int, error)
@@ -60,4 +75,10 @@ type ContainerCreationModel struct {
 	EntrypointCustomization *ContainerProcessCustomization
 	Spec                    *runspecs.Spec
 	Stdout                  *os.File
+	ResourceVersion         uint64
+	Labels                  map[string]string
+	Name                    string
+	ProjectId               uint32
+	IdempotencyKey          string
+	CreatedBy               string
 }
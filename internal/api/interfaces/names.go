@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ErrInvalidName = apierrors.New(codes.InvalidArgument, "invalid name")
+	ErrNameTaken   = apierrors.New(codes.AlreadyExists, "name is already taken")
+)
+
+// Names double as hostnames, so they follow DNS label rules.
+// They have to start with a letter, so that they can't be confused with ids.
+var namePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Empty names are valid, since naming a resource is optional
+func ValidateName(name string) error {
+	if name != "" && !namePattern.MatchString(name) {
+		return apierrors.InvalidArgument("name", fmt.Errorf("%w %q: must be at most 63 lowercase alphanumeric characters or '-', start with a letter and end with an alphanumeric character", ErrInvalidName, name))
+	}
+
+	return nil
+}
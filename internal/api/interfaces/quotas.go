@@ -0,0 +1,22 @@
+package interfaces
+
+import "context"
+
+type QuotaResource string
+
+const (
+	QUOTA_NETWORKS           QuotaResource = "networks"
+	QUOTA_SUBNETWORKS        QuotaResource = "subnetworks"
+	QUOTA_CONTAINERS         QuotaResource = "containers"
+	QUOTA_RUNNING_CONTAINERS QuotaResource = "running_containers"
+	QUOTA_IPS                QuotaResource = "ips"
+)
+
+var QuotaResources = []QuotaResource{QUOTA_NETWORKS, QUOTA_SUBNETWORKS, QUOTA_CONTAINERS, QUOTA_RUNNING_CONTAINERS, QUOTA_IPS}
+
+type QuotaChecker interface {
+	// Fails with RESOURCE_EXHAUSTED if the caller identity can not have one more of each resource, "" is the identity of anonymous callers.
+	// Otherwise they count towards the identity's usage until they are released, which should happen as soon as a resource is in its repository,
+	// since it is counted from there, or the creation failed. release releases the given resources or, without any, every resource that is left.
+	Reserve(ctx context.Context, identity string, resources ...QuotaResource) (release func(resources ...QuotaResource), err error)
+}
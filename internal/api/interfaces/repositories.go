@@ -3,38 +3,78 @@ package interfaces
 import (
 	"context"
 	"net"
+
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 )
 
+type ProjectRepository interface {
+	Get(id uint32) (*ProjectModel, error)
+	GetByName(name string) (*ProjectModel, error)
+	// Returns a single page of projects ordered by ProjectOrderFields, and whether there are more projects after it
+	List(ctx context.Context, options *listing.Options) ([]*ProjectModel, bool, error)
+	// Fails with ErrNameTaken if another project already has the same name
+	Add(project *ProjectModel) (*ProjectModel, error)
+	// Fails with ErrResourceVersionMismatch if expectedVersion is set and does not match
+	Delete(id uint32, expectedVersion *uint64) (*ProjectModel, error)
+}
+
 type NetworkRepository interface {
 	Get(id uint32) (*NetworkModel, error)
+	// Names are only unique within a project
+	GetByName(projectId uint32, name string) (*NetworkModel, error)
 	// TODO: Maybe Reader/Writer would work better here than two manually handled channels?
 	GetAll(ctx context.Context) (<-chan *NetworkModel, <-chan error)
+	// Returns a single page of networks ordered by NetworkOrderFields, and whether there are more networks after it
+	List(ctx context.Context, filter *NetworkFilter, options *listing.Options) ([]*NetworkModel, bool, error)
+	// Fails with ErrNameTaken if another network of the same project already has the same name.
+	// Assigns the next id unless the id is set.
 	Add(network *NetworkModel) (*NetworkModel, error)
-	Delete(id uint32) (*NetworkModel, error)
-	Update(id uint32, updateFn func(*NetworkModel)) (*NetworkModel, error)
+	// Fails with ErrResourceVersionMismatch if expectedVersion is set and does not match
+	Delete(id uint32, expectedVersion *uint64) (*NetworkModel, error)
+	// Bumps the resource version, fails with ErrResourceVersionMismatch if expectedVersion is set and does not match
+	Update(id uint32, expectedVersion *uint64, updateFn func(*NetworkModel)) (*NetworkModel, error)
 }
 
 type SubnetworkRepository interface {
 	Get(id uint32) (*SubnetworkModel, error)
+	// Names are only unique within a project
+	GetByName(projectId uint32, name string) (*SubnetworkModel, error)
 	GetAll(ctx context.Context) (<-chan *SubnetworkModel, <-chan error)
 	GetAllByNetworkId(id uint32, ctx context.Context) (<-chan *SubnetworkModel, <-chan error)
+	// Returns a single page of subnetworks ordered by SubnetworkOrderFields, and whether there are more subnetworks after it
+	List(ctx context.Context, filter *SubnetworkFilter, options *listing.Options) ([]*SubnetworkModel, bool, error)
+	// Fails with ErrNameTaken if another subnetwork of the same project already has the same name.
+	// Assigns the next id unless the id is set.
 	Add(subnetwork *SubnetworkModel) (*SubnetworkModel, error)
-	Delete(id uint32) (*SubnetworkModel, error)
-	Update(id uint32, updateFn func(*SubnetworkModel)) (*SubnetworkModel, error)
+	// Fails with ErrResourceVersionMismatch if expectedVersion is set and does not match
+	Delete(id uint32, expectedVersion *uint64) (*SubnetworkModel, error)
+	// Bumps the resource version, fails with ErrResourceVersionMismatch if expectedVersion is set and does not match
+	Update(id uint32, expectedVersion *uint64, updateFn func(*SubnetworkModel)) (*SubnetworkModel, error)
 }
 
 type IpamRepository interface {
 	GetSubnetworkGateway(subnetwork *SubnetworkModel) *net.IPNet
 	Allocate(subnetwork *SubnetworkModel, resourceType IpamType) (*net.IPNet, error)
 	Deallocate(subnetwork *SubnetworkModel, ip *net.IPNet) error
+	// Marks a specific IP as allocated, used to restore allocations of existing resources
+	Reserve(subnetwork *SubnetworkModel, ip *net.IPNet, resourceType IpamType) error
 	// Returns the first allocation found
 	HasAllocations(subnetwork *SubnetworkModel) (IpamType, bool)
+	CountAllocations(subnetwork *SubnetworkModel) int
+	// Returns how many IPs of the subnetwork can be allocated in total
+	CountAllocatable(subnetwork *SubnetworkModel) int
 }
 
 type ContainerRepository interface {
 	Get(id uint32) (ContainerModel, error)
+	// Names are only unique within a project
+	GetByName(projectId uint32, name string) (ContainerModel, error)
 	GetAll(ctx context.Context) (<-chan ContainerModel, <-chan error)
+	// Returns a single page of containers ordered by ContainerOrderFields, and whether there are more containers after it
+	List(ctx context.Context, filter *ContainerFilter, options *listing.Options) ([]ContainerModel, bool, error)
 	// Returns a container in a 'created' state
 	Create(creationModel *ContainerCreationModel) (ContainerModel, error)
+	// Stops a running container and bumps its resource version
+	Stop(id uint32) (ContainerModel, error)
 	Delete(id uint32) (ContainerModel, error)
 }
@@ -0,0 +1,40 @@
+package introspection
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+)
+
+// Set at build time with -ldflags "-X ...", like the version
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// Falls back to the VCS information that go build embeds, which is missing in builds outside of a repository
+func buildInfo() *pb.BuildInfo {
+	info := &pb.BuildInfo{
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, setting := range build.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,239 @@
+package introspection
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"golang.org/x/sys/unix"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// Probing never fails the call, whatever could not be determined is logged and left empty
+func probeHost(ctx context.Context, paths config.Paths) *pb.HostInfo {
+	host := &pb.HostInfo{
+		KernelVersion:   kernelRelease(),
+		CgroupVersion:   cgroupVersion(),
+		IptablesBackend: iptablesBackend(ctx),
+		Cpus:            uint32(runtime.NumCPU()),
+	}
+
+	total, available, err := memory()
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read the memory of the host", "error", err)
+	}
+	host.MemoryTotalBytes = total
+	host.MemoryAvailableBytes = available
+
+	for _, dir := range []struct {
+		name string
+		path string
+	}{
+		{"state", paths.State},
+		{"containers", paths.Containers},
+		{"images", paths.Images},
+		{"logs", paths.Logs},
+	} {
+		var stat unix.Statfs_t
+		if err := unix.Statfs(dir.path, &stat); err != nil {
+			slog.WarnContext(ctx, "Failed to read the capacity of a data directory", "path", dir.path, "error", err)
+			continue
+		}
+
+		host.DataDirectories = append(host.DataDirectories, &pb.DataDirectory{
+			Name:       dir.name,
+			Path:       dir.path,
+			TotalBytes: stat.Blocks * uint64(stat.Bsize),
+			FreeBytes:  stat.Bavail * uint64(stat.Bsize),
+		})
+	}
+
+	return host
+}
+
+func probeCapabilities(ctx context.Context) []*pb.Capability {
+	release := kernelRelease()
+
+	netns := &pb.Capability{Name: "kernel:netns", Required: true}
+	if _, err := os.Stat("/proc/self/ns/net"); err == nil {
+		netns.Available = true
+		netns.Detail = "network namespaces are supported"
+	} else {
+		netns.Detail = "the kernel does not support network namespaces"
+	}
+
+	// Images are unpacked into plain directories, overlay is reported for the hosts that will need it
+	overlay := kernelModule(release, "overlay", false)
+	if !overlay.Available && fileSystemRegistered("overlay") {
+		overlay.Available = true
+		overlay.Detail = "registered file system"
+	}
+
+	return []*pb.Capability{
+		binary("iptables", true),
+		netns,
+		kernelModule(release, "bridge", true),
+		kernelModule(release, "veth", true),
+		overlay,
+	}
+}
+
+func kernelRelease() string {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return ""
+	}
+
+	return unix.ByteSliceToString(uts.Release[:])
+}
+
+func cgroupVersion() uint32 {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(cgroupRoot, &stat); err != nil {
+		return 0
+	}
+
+	switch stat.Type {
+	case unix.CGROUP2_SUPER_MAGIC:
+		return 2
+	case unix.TMPFS_MAGIC:
+		// cgroup v1 hierarchies are mounted under a tmpfs
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Reads the mode from e.g. "iptables v1.8.9 (nf_tables)", versions without a mode only have the legacy backend
+func iptablesBackend(ctx context.Context) string {
+	output, err := exec.CommandContext(ctx, "iptables", "--version").Output()
+	if err != nil {
+		return ""
+	}
+
+	version := strings.TrimSpace(string(output))
+	if start := strings.LastIndex(version, "("); start != -1 && strings.HasSuffix(version, ")") {
+		return version[start+1 : len(version)-1]
+	}
+
+	return "legacy"
+}
+
+// Returns the total and available memory in bytes
+func memory() (uint64, uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// e.g. "MemTotal:       16318480 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[2] != "kB" {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s: %w", fields[0], err)
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value * 1024
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	return values["MemTotal"], values["MemAvailable"], nil
+}
+
+func binary(name string, required bool) *pb.Capability {
+	capability := &pb.Capability{Name: "binary:" + name, Required: required}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		capability.Detail = fmt.Sprintf("%s was not found in PATH", name)
+		return capability
+	}
+
+	capability.Available = true
+	capability.Detail = path
+	return capability
+}
+
+// Modules are usable if they are loaded, built into the kernel or can be loaded on demand
+func kernelModule(release string, name string, required bool) *pb.Capability {
+	capability := &pb.Capability{Name: "kernel:" + name, Required: required}
+
+	if _, err := os.Stat(filepath.Join("/sys/module", name)); err == nil {
+		capability.Available = true
+		capability.Detail = "loaded"
+		return capability
+	}
+
+	modulesDir := filepath.Join("/lib/modules", release)
+	for _, source := range []struct {
+		file   string
+		detail string
+	}{
+		{"modules.builtin", "built into the kernel"},
+		{"modules.dep", "loadable module"},
+	} {
+		if listsModule(filepath.Join(modulesDir, source.file), name) {
+			capability.Available = true
+			capability.Detail = source.detail
+			return capability
+		}
+	}
+
+	capability.Detail = fmt.Sprintf("not loaded and not found in %s", modulesDir)
+	return capability
+}
+
+// Module lists have one path per line, e.g. "kernel/net/bridge/bridge.ko.zst: kernel/net/llc/llc.ko.zst"
+func listsModule(path string, name string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		module, _, _ := strings.Cut(scanner.Text(), ":")
+		base, _, _ := strings.Cut(filepath.Base(module), ".ko")
+		if base == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fileSystemRegistered(name string) bool {
+	filesystems, err := os.ReadFile("/proc/filesystems")
+	if err != nil {
+		return false
+	}
+
+	// e.g. "nodev	overlay"
+	for _, line := range strings.Split(string(filesystems), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[len(fields)-1] == name {
+			return true
+		}
+	}
+
+	return false
+}
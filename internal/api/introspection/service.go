@@ -2,23 +2,102 @@ package introspection
 
 import (
 	"context"
+	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-var version = "dev"
-
 type service struct {
 	pb.UnimplementedIntrospectionServiceServer
+	ipamIssues           []*pb.IpamIssue
+	paths                config.Paths
+	features             []string
+	startedAt            time.Time
+	projectRepository    interfaces.ProjectRepository
+	networkRepository    interfaces.NetworkRepository
+	subnetworkRepository interfaces.SubnetworkRepository
+	containerRepository  interfaces.ContainerRepository
 }
 
-func NewService() *service {
-	return &service{}
+func NewService(
+	ipamIssues []*pb.IpamIssue,
+	cfg *config.Config,
+	projectRepository interfaces.ProjectRepository,
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+) *service {
+	return &service{
+		ipamIssues:           ipamIssues,
+		paths:                cfg.Paths,
+		features:             cfg.Features(),
+		startedAt:            time.Now(),
+		projectRepository:    projectRepository,
+		networkRepository:    networkRepository,
+		subnetworkRepository: subnetworkRepository,
+		containerRepository:  containerRepository,
+	}
 }
 
 func (s *service) Get(ctx context.Context, req *emptypb.Empty) (*pb.IntrospectionResponse, error) {
+	resources, err := s.countResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &pb.IntrospectionResponse{
-		Version: version,
+		Version:      version,
+		IpamIssues:   s.ipamIssues,
+		Build:        buildInfo(),
+		Uptime:       durationpb.New(time.Since(s.startedAt)),
+		Host:         probeHost(ctx, s.paths),
+		Capabilities: probeCapabilities(ctx),
+		Resources:    resources,
+		Features:     s.features,
+	}, nil
+}
+
+// Counts the resources of all projects
+func (s *service) countResources(ctx context.Context) (*pb.ResourceCounts, error) {
+	projects, _, err := s.projectRepository.List(ctx, &listing.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	networks, err := shared.CollectAll(s.networkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	subnetworks, err := shared.CollectAll(s.subnetworkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := shared.CollectAll(s.containerRepository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	running := 0
+	for _, container := range containers {
+		if state, err := container.GetState(); err == nil && state.Status == runspecs.StateRunning {
+			running++
+		}
+	}
+
+	return &pb.ResourceCounts{
+		Projects:          uint32(len(projects)),
+		Networks:          uint32(len(networks)),
+		Subnetworks:       uint32(len(subnetworks)),
+		Containers:        uint32(len(containers)),
+		RunningContainers: uint32(running),
 	}, nil
 }
@@ -0,0 +1,75 @@
+package introspection_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/introspection"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type emptyContainerRepository struct {
+	interfaces.ContainerRepository
+}
+
+func (r *emptyContainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	results := make(chan interfaces.ContainerModel)
+	errChan := make(chan error)
+	close(results)
+	close(errChan)
+	return results, errChan
+}
+
+func TestIntrospection_Get(t *testing.T) {
+	cfg := config.Default()
+	cfg.MetricsListen = ":9090"
+	cfg.Paths.State = t.TempDir()
+
+	service := introspection.NewService(
+		nil,
+		cfg,
+		project.NewMemoryRepository(nil),
+		network.NewMemoryRepository([]*interfaces.NetworkModel{{Id: 1}, {Id: 2}}),
+		subnetwork.NewMemoryRepository([]*interfaces.SubnetworkModel{{Id: 1, NetworkId: 1}}),
+		&emptyContainerRepository{},
+	)
+
+	resp, err := service.Get(t.Context(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default project always exists
+	expected := &pb.ResourceCounts{Projects: 1, Networks: 2, Subnetworks: 1}
+	if diff := cmp.Diff(expected, resp.Resources, protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected resource counts (-expected +actual):\n%s", diff)
+	}
+
+	if !slices.Equal(resp.Features, []string{"metrics", "periodic-reconciliation"}) {
+		t.Errorf("Unexpected features %v", resp.Features)
+	}
+
+	if resp.Build.GoVersion == "" || resp.Uptime == nil || resp.Host.KernelVersion == "" {
+		t.Errorf("Expected build, uptime and host information, got %v", resp)
+	}
+
+	// Data directories that do not exist are left out
+	if len(resp.Host.DataDirectories) == 0 || resp.Host.DataDirectories[0].Name != "state" || resp.Host.DataDirectories[0].TotalBytes == 0 {
+		t.Errorf("Expected the capacity of the state directory, got %v", resp.Host.DataDirectories)
+	}
+
+	for _, capability := range resp.Capabilities {
+		if capability.Detail == "" {
+			t.Errorf("Capability %s has no detail", capability.Name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package labels
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrInvalidLabel = apierrors.New(codes.InvalidArgument, "invalid label")
+
+const MAX_LENGTH = 63
+
+// Keys and values are restricted to characters that have no meaning in a selector
+func Validate(labels map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if err := validateKey(key); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidLabel, key, err)
+		}
+		if err := validateValue(labels[key]); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidLabel, key, err)
+		}
+	}
+
+	return nil
+}
+
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("key must not be empty")
+	}
+
+	return validateValue(key)
+}
+
+func validateValue(value string) error {
+	if len(value) > MAX_LENGTH {
+		return fmt.Errorf("%q is longer than %d characters", value, MAX_LENGTH)
+	}
+
+	for _, c := range []byte(value) {
+		if !isNameChar(c) {
+			return fmt.Errorf("%q may only contain alphanumeric characters, '-', '_', '.' and '/'", value)
+		}
+	}
+
+	return nil
+}
+
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' ||
+		c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '/'
+}
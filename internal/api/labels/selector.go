@@ -0,0 +1,197 @@
+package labels
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrInvalidSelector = apierrors.New(codes.InvalidArgument, "invalid label selector")
+
+type operator int
+
+const (
+	operatorEquals operator = iota
+	operatorNotEquals
+	operatorIn
+	operatorNotIn
+	operatorExists
+	operatorNotExists
+)
+
+type requirement struct {
+	key      string
+	operator operator
+	values   []string
+}
+
+// A parsed label selector, every requirement has to match. The zero value matches everything.
+type Selector struct {
+	requirements []requirement
+}
+
+// Parses a comma separated list of requirements, each being one of:
+//
+//	key=value, key==value, key!=value
+//	key in (value1,value2), key notin (value1,value2)
+//	key, !key
+func Parse(selector string) (*Selector, error) {
+	p := &parser{input: selector}
+	s := &Selector{}
+
+	p.skipSpaces()
+	if p.done() {
+		return s, nil
+	}
+
+	for {
+		r, err := p.requirement()
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidSelector, selector, err)
+		}
+		s.requirements = append(s.requirements, *r)
+
+		p.skipSpaces()
+		if p.done() {
+			return s, nil
+		}
+		if !p.consume(",") {
+			return nil, fmt.Errorf("%w %q: expected ',' at position %d", ErrInvalidSelector, selector, p.pos)
+		}
+	}
+}
+
+func (s *Selector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		value, exists := labels[r.key]
+
+		var matches bool
+		switch r.operator {
+		case operatorEquals:
+			matches = exists && value == r.values[0]
+		case operatorNotEquals:
+			matches = !exists || value != r.values[0]
+		case operatorIn:
+			matches = exists && slices.Contains(r.values, value)
+		case operatorNotIn:
+			matches = !exists || !slices.Contains(r.values, value)
+		case operatorExists:
+			matches = exists
+		case operatorNotExists:
+			matches = !exists
+		}
+
+		if !matches {
+			return false
+		}
+	}
+
+	return true
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) requirement() (*requirement, error) {
+	p.skipSpaces()
+	if p.consume("!") {
+		p.skipSpaces()
+		key := p.word()
+		if err := validateKey(key); err != nil {
+			return nil, err
+		}
+		return &requirement{key: key, operator: operatorNotExists}, nil
+	}
+
+	key := p.word()
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	switch {
+	case p.consume("=="), p.consume("="):
+		return p.singleValue(key, operatorEquals)
+	case p.consume("!="):
+		return p.singleValue(key, operatorNotEquals)
+	}
+
+	start := p.pos
+	switch p.word() {
+	case "in":
+		return p.valueSet(key, operatorIn)
+	case "notin":
+		return p.valueSet(key, operatorNotIn)
+	}
+
+	p.pos = start
+	return &requirement{key: key, operator: operatorExists}, nil
+}
+
+func (p *parser) singleValue(key string, operator operator) (*requirement, error) {
+	p.skipSpaces()
+	value := p.word()
+	if err := validateValue(value); err != nil {
+		return nil, err
+	}
+
+	return &requirement{key: key, operator: operator, values: []string{value}}, nil
+}
+
+func (p *parser) valueSet(key string, operator operator) (*requirement, error) {
+	p.skipSpaces()
+	if !p.consume("(") {
+		return nil, fmt.Errorf("expected '(' at position %d", p.pos)
+	}
+
+	values := make([]string, 0)
+	for {
+		p.skipSpaces()
+		value := p.word()
+		if err := validateValue(value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipSpaces()
+		if p.consume(")") {
+			return &requirement{key: key, operator: operator, values: values}, nil
+		}
+		if !p.consume(",") {
+			return nil, fmt.Errorf("expected ',' or ')' at position %d", p.pos)
+		}
+	}
+}
+
+func (p *parser) word() string {
+	start := p.pos
+	for !p.done() && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *parser) consume(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
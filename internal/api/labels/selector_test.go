@@ -0,0 +1,88 @@
+package labels_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+)
+
+var testLabels = map[string]string{
+	"team": "payments",
+	"env":  "prod",
+	"tier": "",
+}
+
+func TestSelector_Matches(t *testing.T) {
+	tests := map[string]bool{
+		"":                           true,
+		"env=prod":                   true,
+		"env==prod":                  true,
+		"env=dev":                    false,
+		"env!=dev":                   true,
+		"missing!=dev":               true,
+		"env in (dev, prod)":         true,
+		"env in (dev,staging)":       false,
+		"env notin (dev,staging)":    true,
+		"missing notin (dev)":        true,
+		"team":                       true,
+		"missing":                    false,
+		"!missing":                   true,
+		"!team":                      false,
+		"tier=":                      true,
+		"team=payments, env=prod":    true,
+		"team=payments,env in (dev)": false,
+	}
+
+	for selector, expected := range tests {
+		t.Run(selector, func(t *testing.T) {
+			parsed, err := labels.Parse(selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual := parsed.Matches(testLabels); actual != expected {
+				t.Errorf("Expected %t, got %t", expected, actual)
+			}
+		})
+	}
+}
+
+func TestSelector_Parse_Invalid(t *testing.T) {
+	tests := []string{
+		"=prod",
+		"env=prod,",
+		"env in dev",
+		"env in (dev",
+		"env in (dev prod)",
+		"env prod",
+		"env=pr*d",
+		"!",
+	}
+
+	for _, selector := range tests {
+		t.Run(selector, func(t *testing.T) {
+			_, err := labels.Parse(selector)
+			if !errors.Is(err, labels.ErrInvalidSelector) {
+				t.Errorf("Expected %v, got %v", labels.ErrInvalidSelector, err)
+			}
+		})
+	}
+}
+
+func TestLabels_Validate(t *testing.T) {
+	if err := labels.Validate(testLabels); err != nil {
+		t.Errorf("Expected valid labels, got %v", err)
+	}
+
+	invalid := []map[string]string{
+		{"": "value"},
+		{"key with spaces": "value"},
+		{"key": "a,b"},
+	}
+	for _, l := range invalid {
+		if err := labels.Validate(l); !errors.Is(err, labels.ErrInvalidLabel) {
+			t.Errorf("Expected %v for %v, got %v", labels.ErrInvalidLabel, l, err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package listing
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrInvalidOrderBy    = apierrors.New(codes.InvalidArgument, "invalid order by")
+	ErrInvalidPageToken  = apierrors.New(codes.InvalidArgument, "invalid page token")
+	ErrUnsupportedFilter = apierrors.New(codes.InvalidArgument, "unsupported filter")
+)
+
+// Larger page sizes are reduced to this
+const MAX_PAGE_SIZE = 1000
+
+// Trailer metadata key that holds the token of the next page
+const NEXT_PAGE_TOKEN_KEY = "next-page-token"
+
+type Order struct {
+	Field      string
+	Descending bool
+}
+
+// Parameters of a List that the repositories apply after filtering
+type Options struct {
+	// Maximum number of resources to return, 0 returns all of them
+	Limit    int
+	OrderBy  []Order
+	Selector *labels.Selector
+	// Identifies the filters and ordering, so that a page token can not be used with a different List
+	fingerprint string
+	// Ordering values of the resource the page continues after, nil starts from the first resource
+	after []json.RawMessage
+	// Ordering values of the last resource of a page that has more resources after it, set by Page
+	last []json.RawMessage
+}
+
+// Continues a List after the resource whose ordering values it holds
+type pageToken struct {
+	After       []json.RawMessage `json:"after"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+func (o *Options) MatchesLabels(l map[string]string) bool {
+	return o.Selector == nil || o.Selector.Matches(l)
+}
+
+// Validates the request against the fields that resources can be ordered by
+func FromRequest[T any](req *pb.ListRequest, fields Fields[T]) (*Options, error) {
+	selector, err := labels.Parse(req.LabelSelector)
+	if err != nil {
+		return nil, apierrors.InvalidArgument("label_selector", err)
+	}
+
+	orderBy, err := parseOrderBy(req.OrderBy, fields)
+	if err != nil {
+		return nil, apierrors.InvalidArgument("order_by", err)
+	}
+
+	options := &Options{
+		Limit:       int(min(req.PageSize, MAX_PAGE_SIZE)),
+		OrderBy:     orderBy,
+		Selector:    selector,
+		fingerprint: fingerprint(req),
+	}
+
+	if req.PageToken != "" {
+		after, err := decodePageToken(req.PageToken, options.fingerprint, sortKey(orderBy), fields)
+		if err != nil {
+			return nil, apierrors.InvalidArgument("page_token", err)
+		}
+		options.after = after
+	}
+
+	return options, nil
+}
+
+// Fails if the request sets a filter that the listed resources do not support
+func CheckFilters(req *pb.ListRequest, resources string, supported ...string) error {
+	set := map[string]bool{
+		"network_id":    req.NetworkId != nil,
+		"subnetwork_id": req.SubnetworkId != nil,
+		"status":        req.Status != "",
+		"image":         req.Image != "",
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(set)) {
+		if set[field] && !slices.Contains(supported, field) {
+			return apierrors.InvalidArgument(field, fmt.Errorf("%w: %s can not be filtered by %s", ErrUnsupportedFilter, resources, field))
+		}
+	}
+
+	return nil
+}
+
+// Returns the token of the page that follows the one cut out by Page, or "" if there are no more resources
+func (o *Options) NextPageToken(more bool) string {
+	if !more || o.last == nil {
+		return ""
+	}
+
+	token, _ := json.Marshal(&pageToken{After: o.last, Fingerprint: o.fingerprint})
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// Sends the token in the trailer, because List streams the resources one by one
+func SetNextPageToken(stream grpc.ServerStream, token string) {
+	stream.SetTrailer(metadata.Pairs(NEXT_PAGE_TOKEN_KEY, token))
+}
+
+func decodePageToken[T any](token string, fingerprint string, key []Order, fields Fields[T]) ([]json.RawMessage, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+
+	var parsed pageToken
+	if err := json.Unmarshal(decoded, &parsed); err != nil {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidPageToken)
+	}
+
+	if parsed.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("%w: the token belongs to a List with different filters or ordering", ErrInvalidPageToken)
+	}
+
+	if len(parsed.After) != len(key) {
+		return nil, fmt.Errorf("%w: malformed position", ErrInvalidPageToken)
+	}
+	for i, order := range key {
+		if err := fields[order.Field].validate(parsed.After[i]); err != nil {
+			return nil, fmt.Errorf("%w: malformed %s", ErrInvalidPageToken, order.Field)
+		}
+	}
+
+	return parsed.After, nil
+}
+
+// Everything but the paging itself has to stay the same between pages
+func fingerprint(req *pb.ListRequest) string {
+	query := proto.Clone(req).(*pb.ListRequest)
+	query.PageSize = 0
+	query.PageToken = ""
+
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(query)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func parseOrderBy[T any](orderBy string, fields Fields[T]) ([]Order, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+
+	result := make([]Order, 0)
+	for _, part := range strings.Split(orderBy, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("%w %q: expected a field with an optional 'asc' or 'desc'", ErrInvalidOrderBy, part)
+		}
+
+		if _, ok := fields[words[0]]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidOrderBy, words[0], fields.names())
+		}
+
+		order := Order{Field: words[0]}
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				order.Descending = true
+			default:
+				return nil, fmt.Errorf("%w %q: expected 'asc' or 'desc'", ErrInvalidOrderBy, words[1])
+			}
+		}
+
+		result = append(result, order)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package listing_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/google/go-cmp/cmp"
+)
+
+type item struct {
+	id   uint32
+	name string
+}
+
+var fields = listing.Fields[item]{
+	"id":   listing.By(func(i item) uint32 { return i.id }),
+	"name": listing.By(func(i item) string { return i.name }),
+}
+
+var items = []item{{3, "b"}, {1, "b"}, {2, "a"}}
+
+func TestListing_Page(t *testing.T) {
+	tests := map[string][]uint32{
+		"":                  {1, 2, 3},
+		"id desc":           {3, 2, 1},
+		"name":              {2, 1, 3},
+		"name desc, id":     {1, 3, 2},
+		" name asc,id desc": {2, 3, 1},
+	}
+
+	for orderBy, expected := range tests {
+		t.Run(orderBy, func(t *testing.T) {
+			options, err := listing.FromRequest(&pb.ListRequest{OrderBy: orderBy}, fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			page, more := listing.Page(items, options, fields)
+			if more {
+				t.Error("Expected no more items")
+			}
+
+			ids := make([]uint32, 0)
+			for _, i := range page {
+				ids = append(ids, i.id)
+			}
+			if diff := cmp.Diff(expected, ids); diff != "" {
+				t.Errorf("order mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListing_PageToken(t *testing.T) {
+	req := &pb.ListRequest{PageSize: 2}
+	options, err := listing.FromRequest(req, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, more := listing.Page(items, options, fields)
+	if len(page) != 2 || !more {
+		t.Fatalf("Expected a page of 2 with more items, got %v, %t", page, more)
+	}
+
+	req.PageToken = options.NextPageToken(more)
+	options, err = listing.FromRequest(req, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, more = listing.Page(items, options, fields)
+	if len(page) != 1 || page[0].id != 3 || more {
+		t.Fatalf("Expected the last item without more items, got %v, %t", page, more)
+	}
+	if token := options.NextPageToken(more); token != "" {
+		t.Errorf("Expected no token after the last page, got %q", token)
+	}
+}
+
+func TestListing_FromRequest_Invalid(t *testing.T) {
+	tests := map[*pb.ListRequest]error{
+		{OrderBy: "missing"}:       listing.ErrInvalidOrderBy,
+		{OrderBy: "name up"}:       listing.ErrInvalidOrderBy,
+		{OrderBy: "name,"}:         listing.ErrInvalidOrderBy,
+		{PageToken: "not a token"}: listing.ErrInvalidPageToken,
+	}
+
+	for req, expected := range tests {
+		if _, err := listing.FromRequest(req, fields); !errors.Is(err, expected) {
+			t.Errorf("Expected %v for %v, got %v", expected, req, err)
+		}
+	}
+}
+
+func TestListing_PageToken_ResourcesChangeBetweenPages(t *testing.T) {
+	req := &pb.ListRequest{PageSize: 2, OrderBy: "name"}
+	options, err := listing.FromRequest(req, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, more := listing.Page(items, options, fields)
+	if diff := cmp.Diff([]item{{2, "a"}, {1, "b"}}, page, cmp.AllowUnexported(item{})); diff != "" || !more {
+		t.Fatalf("first page mismatch (-want +got):\n%s", diff)
+	}
+
+	// A listed item is deleted and another one is created, which must not make the next page skip an item
+	changed := []item{{3, "b"}, {1, "b"}, {5, "c"}}
+
+	req.PageToken = options.NextPageToken(more)
+	options, err = listing.FromRequest(req, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, more = listing.Page(changed, options, fields)
+	if diff := cmp.Diff([]item{{3, "b"}, {5, "c"}}, page, cmp.AllowUnexported(item{})); diff != "" || more {
+		t.Errorf("next page mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListing_PageToken_Forged(t *testing.T) {
+	req := &pb.ListRequest{PageSize: 1}
+	options, err := listing.FromRequest(req, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, more := listing.Page(items, options, fields)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(options.NextPageToken(more))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The token holds the id of the last listed item, which is replaced with a value of another type
+	forged := strings.Replace(string(decoded), "[1]", `["1"]`, 1)
+	if forged == string(decoded) {
+		t.Fatalf("Expected the token to hold the id of the last listed item, got %s", decoded)
+	}
+
+	req.PageToken = base64.RawURLEncoding.EncodeToString([]byte(forged))
+	if _, err := listing.FromRequest(req, fields); !errors.Is(err, listing.ErrInvalidPageToken) {
+		t.Errorf("Expected %v, got %v", listing.ErrInvalidPageToken, err)
+	}
+}
@@ -0,0 +1,109 @@
+package listing
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Fields resources can be ordered by, it has to contain "id" which is used to break ties
+type Fields[T any] map[string]Field[T]
+
+type Field[T any] struct {
+	compare func(a, b T) int
+	// Page tokens hold the values of the last listed resource, so that the next page continues after it
+	encode func(item T) json.RawMessage
+	// Fails if the encoded value is not one of the field
+	validate func(encoded json.RawMessage) error
+	// Compares the value of a resource with an encoded one, which has been validated
+	compareEncoded func(item T, encoded json.RawMessage) int
+}
+
+func (f Fields[T]) names() string {
+	return strings.Join(slices.Sorted(maps.Keys(f)), ", ")
+}
+
+// The fields a page token holds the values of, in the order they are compared
+func sortKey(orderBy []Order) []Order {
+	return append(slices.Clone(orderBy), Order{Field: "id"})
+}
+
+// Orders the already filtered items and cuts out the requested page, also tells whether there are more items after it.
+// Since a page continues after the last resource of the previous page, creating or deleting other resources does not shift it.
+func Page[T any](items []T, options *Options, fields Fields[T]) ([]T, bool) {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		for _, order := range sortKey(options.OrderBy) {
+			c := fields[order.Field].compare(a, b)
+			if order.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	if options.after != nil {
+		start := slices.IndexFunc(sorted, func(item T) bool {
+			return compareToEncoded(item, options.after, options.OrderBy, fields) > 0
+		})
+		if start < 0 {
+			return []T{}, false
+		}
+		sorted = sorted[start:]
+	}
+
+	if options.Limit == 0 || options.Limit >= len(sorted) {
+		return sorted, false
+	}
+
+	page := sorted[:options.Limit]
+	last := page[len(page)-1]
+	options.last = make([]json.RawMessage, 0)
+	for _, order := range sortKey(options.OrderBy) {
+		options.last = append(options.last, fields[order.Field].encode(last))
+	}
+
+	return page, true
+}
+
+func compareToEncoded[T any](item T, encoded []json.RawMessage, orderBy []Order, fields Fields[T]) int {
+	for i, order := range sortKey(orderBy) {
+		c := fields[order.Field].compareEncoded(item, encoded[i])
+		if order.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// Helper for building Fields
+func By[T any, V cmp.Ordered](value func(T) V) Field[T] {
+	return Field[T]{
+		compare: func(a, b T) int {
+			return cmp.Compare(value(a), value(b))
+		},
+		encode: func(item T) json.RawMessage {
+			encoded, _ := json.Marshal(value(item))
+			return encoded
+		},
+		validate: func(encoded json.RawMessage) error {
+			var decoded V
+			return json.Unmarshal(encoded, &decoded)
+		},
+		compareEncoded: func(item T, encoded json.RawMessage) int {
+			var decoded V
+			_ = json.Unmarshal(encoded, &decoded)
+			return cmp.Compare(value(item), decoded)
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/BenasB/bx2cloud/internal/api/config"
+)
+
+// Makes the configured handler the default of slog, which also makes the log package write through it
+func Setup(w io.Writer, cfg config.Log) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch cfg.Format {
+	case config.LOG_FORMAT_JSON:
+		handler = slog.NewJSONHandler(w, options)
+	case config.LOG_FORMAT_TEXT:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+	return nil
+}
+
+// Adds the request id of the context to every record logged with it
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestId(ctx); id != "" {
+		record.AddAttrs(slog.String(REQUEST_ID_ATTRIBUTE, id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,84 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor_RequestId(t *testing.T) {
+	interceptor := logging.UnaryInterceptor()
+	requestIdOf := func(md metadata.MD) string {
+		var id string
+		ctx := metadata.NewIncomingContext(t.Context(), md)
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			id = logging.RequestId(ctx)
+			return nil, nil
+		})
+		return id
+	}
+
+	if id := requestIdOf(metadata.Pairs(logging.REQUEST_ID_METADATA_KEY, "abc")); id != "abc" {
+		t.Errorf("expected the request id sent by the client, got %q", id)
+	}
+
+	generated := requestIdOf(nil)
+	if generated == "" || generated == requestIdOf(nil) {
+		t.Errorf("expected a new request id for every call, got %q", generated)
+	}
+
+	if id := requestIdOf(metadata.Pairs(logging.REQUEST_ID_METADATA_KEY, strings.Repeat("a", logging.MAX_REQUEST_ID_LENGTH+1))); len(id) > logging.MAX_REQUEST_ID_LENGTH {
+		t.Errorf("expected a too long request id to be replaced, got %q", id)
+	}
+}
+
+func TestSetup_RequestIdAttribute(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var buf bytes.Buffer
+	if err := logging.Setup(&buf, config.Log{Level: "info", Format: config.LOG_FORMAT_JSON}); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.DebugContext(logging.WithRequestId(t.Context(), "abc"), "Hidden")
+	slog.InfoContext(logging.WithRequestId(t.Context(), "abc"), "Configured network", "network_id", 1)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record[logging.REQUEST_ID_ATTRIBUTE] != "abc" || record["msg"] != "Configured network" {
+		t.Errorf("expected the record to have the request id, got %v", record)
+	}
+}
+
+func TestClientUnaryInterceptor(t *testing.T) {
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+				*trailer.TrailerAddr = metadata.Pairs(logging.REQUEST_ID_METADATA_KEY, "abc")
+			}
+		}
+		return apierrors.NotFound("could not find network with id %d", 1)
+	}
+
+	err := logging.ClientUnaryInterceptor()(t.Context(), "/bx2cloud.NetworkService/Get", nil, nil, nil, invoker)
+	s := status.Convert(err)
+	if want := "could not find network with id 1 (request id abc)"; s.Message() != want {
+		t.Errorf("expected the message %q, got %q", want, s.Message())
+	}
+	if s.Code() != codes.NotFound {
+		t.Errorf("expected the status code to be kept, got %s", s.Code())
+	}
+}
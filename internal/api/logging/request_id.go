@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata key of the request id, clients may send one and the API returns it in the trailers of every call
+const REQUEST_ID_METADATA_KEY = "x-request-id"
+
+const REQUEST_ID_ATTRIBUTE = "request_id"
+
+// Longer ids sent by clients are replaced, so that they can not flood the logs
+const MAX_REQUEST_ID_LENGTH = 128
+
+type requestIdKey struct{}
+
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// Returns "" if the context does not belong to a call
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// Should be the first interceptor, so that every log line of the call, including rejections, has the request id
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = WithRequestId(ctx, requestIdOf(ctx))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(REQUEST_ID_METADATA_KEY, RequestId(ctx)))
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := WithRequestId(stream.Context(), requestIdOf(stream.Context()))
+		stream.SetTrailer(metadata.Pairs(REQUEST_ID_METADATA_KEY, RequestId(ctx)))
+
+		start := time.Now()
+		err := handler(srv, shared.WithContext(stream, ctx))
+		logCall(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// Takes the id from the incoming metadata, or generates a new one
+func requestIdOf(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(REQUEST_ID_METADATA_KEY); len(values) > 0 && values[0] != "" && len(values[0]) <= MAX_REQUEST_ID_LENGTH {
+			return values[0]
+		}
+	}
+
+	return NewRequestId()
+}
+
+func NewRequestId() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Calls that failed because of the API itself are errors, everything else is only interesting when debugging
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelDebug
+	if code == codes.Unknown || code == codes.Internal {
+		level = slog.LevelError
+	}
+
+	attrs := []any{"method", method, "code", code.String(), "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Log(ctx, level, "Handled call", attrs...)
+}
+
+// Adds the request id that the API returned in the trailers to the errors of calls, so that they can be found in the API's logs
+func ClientUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var trailer metadata.MD
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+		return withRequestId(err, trailer)
+	}
+}
+
+func ClientStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &requestIdStream{ClientStream: stream}, nil
+	}
+}
+
+type requestIdStream struct {
+	grpc.ClientStream
+}
+
+func (s *requestIdStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+	return withRequestId(err, s.Trailer())
+}
+
+func withRequestId(err error, trailer metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+
+	values := trailer.Get(REQUEST_ID_METADATA_KEY)
+	if len(values) == 0 {
+		return err
+	}
+
+	// The status is rebuilt instead of wrapped, so that its message stays free of the "rpc error" prefix
+	s := status.Convert(err).Proto()
+	s.Message = fmt.Sprintf("%s (request id %s)", s.Message, values[0])
+	return status.FromProto(s).Err()
+}
@@ -0,0 +1,36 @@
+package metrics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := metrics.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/bx2cloud.NetworkService/Get"}
+
+	for range 2 {
+		_, _ = interceptor(t.Context(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return nil, apierrors.NotFound("could not find network with id %d", 1)
+		})
+	}
+	_, _ = interceptor(t.Context(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+
+	expected := `
+# HELP bx2cloud_grpc_requests_total gRPC calls that were handled, by full method name and status code.
+# TYPE bx2cloud_grpc_requests_total counter
+bx2cloud_grpc_requests_total{code="NotFound",method="/bx2cloud.NetworkService/Get"} 2
+bx2cloud_grpc_requests_total{code="OK",method="/bx2cloud.NetworkService/Get"} 1
+`
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected), "bx2cloud_grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const NAMESPACE = "bx2cloud"
+
+// Every metric of the API is registered here, it is separate from the default registry so that dependencies can not add metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// Time spent downloading and unpacking the layers of an image
+	ImagePullDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "image_pull_duration_seconds",
+		Help:      "Time spent downloading and unpacking the layers of an image for a container.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	})
+	ImagePullBytes = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "image_pull_bytes_total",
+		Help:      "Bytes downloaded from image registries, including manifests and configs.",
+	})
+	ExecSessions = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "exec_sessions",
+		Help:      "Number of exec sessions that are currently open.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
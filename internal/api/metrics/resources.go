@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	networksDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "", "networks"),
+		"Number of networks, by project.",
+		[]string{"project_id"}, nil,
+	)
+	subnetworksDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "", "subnetworks"),
+		"Number of subnetworks, by project.",
+		[]string{"project_id"}, nil,
+	)
+	containersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "", "containers"),
+		"Number of containers, by project and status.",
+		[]string{"project_id", "status"}, nil,
+	)
+	ipsAllocatedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "subnetwork", "ips_allocated"),
+		"Number of IPs allocated in the subnetwork.",
+		[]string{"subnetwork_id"}, nil,
+	)
+	ipsAllocatableDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "subnetwork", "ips_allocatable"),
+		"Number of IPs that can be allocated in the subnetwork in total.",
+		[]string{"subnetwork_id"}, nil,
+	)
+	containerCpuDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "container", "cpu_seconds_total"),
+		"CPU time used by the cgroup of a running container.",
+		[]string{"container_id"}, nil,
+	)
+	containerMemoryDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "container", "memory_bytes"),
+		"Memory used by the cgroup of a running container.",
+		[]string{"container_id"}, nil,
+	)
+)
+
+var _ prometheus.Collector = &resourceCollector{}
+
+// Reads the resources from the repositories on every scrape, so the values are never stale
+type resourceCollector struct {
+	networkRepository    interfaces.NetworkRepository
+	subnetworkRepository interfaces.SubnetworkRepository
+	containerRepository  interfaces.ContainerRepository
+	ipamRepository       interfaces.IpamRepository
+}
+
+func NewResourceCollector(
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+	ipamRepository interfaces.IpamRepository,
+) *resourceCollector {
+	return &resourceCollector{
+		networkRepository:    networkRepository,
+		subnetworkRepository: subnetworkRepository,
+		containerRepository:  containerRepository,
+		ipamRepository:       ipamRepository,
+	}
+}
+
+func (c *resourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		networksDesc, subnetworksDesc, containersDesc,
+		ipsAllocatedDesc, ipsAllocatableDesc,
+		containerCpuDesc, containerMemoryDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *resourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	networks, err := shared.CollectAll(c.networkRepository.GetAll(ctx))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(networksDesc, err)
+	} else {
+		perProject := make(map[uint32]int)
+		for _, network := range networks {
+			perProject[network.ProjectId]++
+		}
+		collectPerProject(ch, networksDesc, perProject)
+	}
+
+	subnetworks, err := shared.CollectAll(c.subnetworkRepository.GetAll(ctx))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(subnetworksDesc, err)
+	} else {
+		perProject := make(map[uint32]int)
+		for _, subnetwork := range subnetworks {
+			perProject[subnetwork.ProjectId]++
+			id := strconv.FormatUint(uint64(subnetwork.Id), 10)
+			ch <- prometheus.MustNewConstMetric(ipsAllocatedDesc, prometheus.GaugeValue, float64(c.ipamRepository.CountAllocations(subnetwork)), id)
+			ch <- prometheus.MustNewConstMetric(ipsAllocatableDesc, prometheus.GaugeValue, float64(c.ipamRepository.CountAllocatable(subnetwork)), id)
+		}
+		collectPerProject(ch, subnetworksDesc, perProject)
+	}
+
+	containers, err := shared.CollectAll(c.containerRepository.GetAll(ctx))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(containersDesc, err)
+		return
+	}
+
+	type projectStatus struct {
+		projectId uint32
+		status    string
+	}
+	perStatus := make(map[projectStatus]int)
+	for _, container := range containers {
+		data := container.GetData()
+		state, err := container.GetState()
+		if err != nil {
+			slog.Warn("Failed to read the state of a container for metrics", "container_id", data.Id, "error", err)
+			continue
+		}
+		perStatus[projectStatus{data.ProjectId, string(state.Status)}]++
+
+		if state.Status != runspecs.StateRunning {
+			continue
+		}
+
+		stats, err := container.GetStats()
+		if err != nil {
+			slog.Warn("Failed to read the stats of a container for metrics", "container_id", data.Id, "error", err)
+			continue
+		}
+		id := strconv.FormatUint(uint64(data.Id), 10)
+		ch <- prometheus.MustNewConstMetric(containerCpuDesc, prometheus.CounterValue, stats.CpuUsage.Seconds(), id)
+		ch <- prometheus.MustNewConstMetric(containerMemoryDesc, prometheus.GaugeValue, float64(stats.MemoryBytes), id)
+	}
+
+	for key, count := range perStatus {
+		projectId := strconv.FormatUint(uint64(key.projectId), 10)
+		ch <- prometheus.MustNewConstMetric(containersDesc, prometheus.GaugeValue, float64(count), projectId, key.status)
+	}
+}
+
+func collectPerProject(ch chan<- prometheus.Metric, desc *prometheus.Desc, perProject map[uint32]int) {
+	for projectId, count := range perProject {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), strconv.FormatUint(uint64(projectId), 10))
+	}
+}
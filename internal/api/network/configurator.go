@@ -1,10 +1,16 @@
 package network
 
-import "github.com/BenasB/bx2cloud/internal/api/interfaces"
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+)
 
 type configurator interface {
-	Configure(model *interfaces.NetworkModel) error
-	Unconfigure(model *interfaces.NetworkModel) error
+	Configure(ctx context.Context, model *interfaces.NetworkModel) error
+	Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error
+	// Returns an error describing how the host differs from the configuration of the network, if it does
+	Verify(model *interfaces.NetworkModel) error
 }
 
 var _ configurator = &mockConfigurator{}
@@ -15,10 +21,14 @@ func NewMockConfigurator() configurator {
 	return &mockConfigurator{}
 }
 
-func (m *mockConfigurator) Configure(model *interfaces.NetworkModel) error {
+func (m *mockConfigurator) Configure(ctx context.Context, model *interfaces.NetworkModel) error {
+	return nil
+}
+
+func (m *mockConfigurator) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
 	return nil
 }
 
-func (m *mockConfigurator) Unconfigure(model *interfaces.NetworkModel) error {
+func (m *mockConfigurator) Verify(model *interfaces.NetworkModel) error {
 	return nil
 }
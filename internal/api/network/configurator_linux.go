@@ -1,13 +1,19 @@
 package network
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
 
+	"github.com/BenasB/bx2cloud/internal/api/config"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"github.com/coreos/go-iptables/iptables"
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netns"
@@ -18,9 +24,16 @@ var _ configurator = &namespaceConfigurator{}
 type namespaceConfigurator struct {
 	primaryInterface netlink.Link
 	ipt              *iptables.IPTables
+	host             config.Host
+	transitRange     *net.IPNet
 }
 
-func NewNamespaceConfigurator() (*namespaceConfigurator, error) {
+func NewNamespaceConfigurator(host config.Host) (*namespaceConfigurator, error) {
+	transitRange, err := host.ParseTransitRange()
+	if err != nil {
+		return nil, err
+	}
+
 	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routes when locating the primary interface: %w", err)
@@ -51,9 +64,16 @@ func NewNamespaceConfigurator() (*namespaceConfigurator, error) {
 		return nil, fmt.Errorf("failed to create iptables instance: %w", err)
 	}
 
-	err = ipt.AppendUnique("filter", "FORWARD",
-		"-i", "bx2-r-+",
-		"-o", "bx2-r-+",
+	if err := ensureChain(ipt, "filter", "FORWARD", host.ForwardChain); err != nil {
+		return nil, err
+	}
+	if err := ensureChain(ipt, "nat", "POSTROUTING", host.NatChain); err != nil {
+		return nil, err
+	}
+
+	err = ipt.AppendUnique("filter", host.ForwardChain,
+		"-i", host.InterfacePrefix+"r-+",
+		"-o", host.InterfacePrefix+"r-+",
 		"-j", "DROP",
 	)
 
@@ -64,10 +84,35 @@ func NewNamespaceConfigurator() (*namespaceConfigurator, error) {
 	return &namespaceConfigurator{
 		primaryInterface: primaryInterface,
 		ipt:              ipt,
+		host:             host,
+		transitRange:     transitRange,
 	}, nil
 }
 
-func (n *namespaceConfigurator) Configure(model *interfaces.NetworkModel) error {
+// Custom chains are created and jumped to from the built-in chain, so that separate instances can keep their rules apart
+func ensureChain(ipt *iptables.IPTables, table string, builtin string, chain string) error {
+	if chain == builtin {
+		return nil
+	}
+
+	exists, err := ipt.ChainExists(table, chain)
+	if err != nil {
+		return fmt.Errorf("failed to check whether the %s chain %s exists: %w", table, chain, err)
+	}
+	if !exists {
+		if err := ipt.NewChain(table, chain); err != nil {
+			return fmt.Errorf("failed to create the %s chain %s: %w", table, chain, err)
+		}
+	}
+
+	if err := ipt.AppendUnique(table, builtin, "-j", chain); err != nil {
+		return fmt.Errorf("failed to jump to the %s chain %s from %s: %w", table, chain, builtin, err)
+	}
+
+	return nil
+}
+
+func (n *namespaceConfigurator) Configure(ctx context.Context, model *interfaces.NetworkModel) error {
 	nsName := n.GetNetworkNamespaceName(model.Id)
 
 	runtime.LockOSThread()
@@ -119,12 +164,12 @@ func (n *namespaceConfigurator) Configure(model *interfaces.NetworkModel) error
 		}
 	}
 
-	log.Printf("Successfully configured network with the id %d", model.Id)
+	slog.InfoContext(ctx, "Configured network", "network_id", model.Id)
 
 	return nil
 }
 
-func (n *namespaceConfigurator) Unconfigure(model *interfaces.NetworkModel) error {
+func (n *namespaceConfigurator) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
 	nsName := n.GetNetworkNamespaceName(model.Id)
 
 	runtime.LockOSThread()
@@ -154,11 +199,113 @@ func (n *namespaceConfigurator) Unconfigure(model *interfaces.NetworkModel) erro
 		}
 	}
 
-	log.Printf("Successfully unconfigured network with the id %d", model.Id)
+	slog.InfoContext(ctx, "Unconfigured network", "network_id", model.Id)
 
 	return nil
 }
 
+func (n *namespaceConfigurator) Verify(model *interfaces.NetworkModel) error {
+	nsName := n.GetNetworkNamespaceName(model.Id)
+	ns, err := netns.GetFromName(nsName)
+	if err != nil {
+		return fmt.Errorf("network namespace %q is missing", nsName)
+	}
+	defer ns.Close()
+
+	rootVethName := n.getRootVethName(model)
+	rootVeth, rootVethErr := netlink.LinkByName(rootVethName)
+	primaryRuleExists, err := n.ipt.Exists("nat", n.host.NatChain,
+		"-s", n.getNsVethAddr(model).IPNet.String(),
+		"-o", n.primaryInterface.Attrs().Name,
+		"-j", "MASQUERADE",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to check the SNAT rule on the primary interface: %w", err)
+	}
+
+	if !model.InternetAccess {
+		if rootVethErr == nil {
+			return fmt.Errorf("veth %q exists even though the network has no internet access", rootVethName)
+		}
+		if primaryRuleExists {
+			return fmt.Errorf("SNAT rule on the primary interface exists even though the network has no internet access")
+		}
+		return nil
+	}
+
+	if rootVethErr != nil {
+		return fmt.Errorf("veth %q is missing", rootVethName)
+	}
+
+	if rootVeth.Attrs().Flags&net.FlagUp == 0 {
+		return fmt.Errorf("veth %q is down", rootVethName)
+	}
+
+	if !primaryRuleExists {
+		return fmt.Errorf("SNAT rule on the primary interface is missing")
+	}
+
+	handle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return fmt.Errorf("failed to open the network's namespace: %w", err)
+	}
+	defer handle.Close()
+
+	nsVethName := n.getNsVethName(model)
+	nsVeth, err := handle.LinkByName(nsVethName)
+	if err != nil {
+		return fmt.Errorf("veth %q is missing in the network's namespace", nsVethName)
+	}
+
+	nsRuleExists, err := n.nsSnatRuleExists(ns, nsVethName)
+	if err != nil {
+		return fmt.Errorf("failed to check the SNAT rule in the network's namespace: %w", err)
+	}
+	if !nsRuleExists {
+		return fmt.Errorf("SNAT rule in the network's namespace is missing")
+	}
+
+	routes, err := handle.RouteList(nsVeth, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve routes of the network's namespace: %w", err)
+	}
+
+	rootVethAddr := n.getRootVethAddr(model)
+	for _, route := range routes {
+		if (route.Dst == nil || route.Dst.IP.Equal(net.IPv4zero)) && rootVethAddr.IP.Equal(route.Gw) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("default route is missing in the network's namespace")
+}
+
+// iptables only lists the rules of the namespace it runs in, so the thread switches to the network's namespace for the check
+func (n *namespaceConfigurator) nsSnatRuleExists(ns netns.NsHandle, nsVethName string) (bool, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origNs, err := netns.Get()
+	defer origNs.Close()
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve the original network namespace: %w", err)
+	}
+	defer func() {
+		if err := netns.Set(origNs); err != nil {
+			panic("failed to move back to the original network namespace, panicking to not change unexpected state")
+		}
+	}()
+
+	if err := netns.Set(ns); err != nil {
+		return false, fmt.Errorf("failed to switch to the network's namespace: %w", err)
+	}
+
+	return n.ipt.Exists("nat", "POSTROUTING",
+		"-o", nsVethName,
+		"-j", "MASQUERADE",
+	)
+}
+
 func (n *namespaceConfigurator) configureInternetAccess(model *interfaces.NetworkModel, origNs netns.NsHandle, ns netns.NsHandle) error {
 	rootVethName := n.getRootVethName(model)
 	nsVethName := n.getNsVethName(model)
@@ -281,6 +428,7 @@ func (n *namespaceConfigurator) configureInternetAccess(model *interfaces.Networ
 		}
 	}
 
+	// The configured chains only exist in the root namespace, the network's namespace is not shared with other instances
 	err = n.ipt.AppendUnique("nat", "POSTROUTING",
 		"-o", nsVeth.Attrs().Name,
 		"-j", "MASQUERADE",
@@ -294,7 +442,7 @@ func (n *namespaceConfigurator) configureInternetAccess(model *interfaces.Networ
 		return fmt.Errorf("failed to switch back to the root network namespace: %w", err)
 	}
 
-	err = n.ipt.AppendUnique("nat", "POSTROUTING",
+	err = n.ipt.AppendUnique("nat", n.host.NatChain,
 		"-s", nsVethAddr.IPNet.String(),
 		"-o", n.primaryInterface.Attrs().Name,
 		"-j", "MASQUERADE",
@@ -315,7 +463,7 @@ func (n *namespaceConfigurator) unconfigureInternetAccess(model *interfaces.Netw
 
 		nsVeth, err := netlink.LinkByName(n.getNsVethName(model))
 		if err == nil {
-			const networkIpStart uint32 = 0b_11000000_10100111_00000000_00000000
+			networkIpStart := n.transitStart()
 			networkIp := networkIpStart + model.Id<<2
 			rootVethIp := networkIp + 1
 
@@ -373,8 +521,18 @@ func (n *namespaceConfigurator) unconfigureInternetAccess(model *interfaces.Netw
 		}
 	}
 
+	// Also removed from the root namespace, in case it was left there without its namespace
+	err = n.ipt.DeleteIfExists("nat", n.host.NatChain,
+		"-o", n.getNsVethName(model),
+		"-j", "MASQUERADE",
+	)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove SNAT rule for subnetwork translation: %w", err)
+	}
+
 	nsVethAddr := n.getNsVethAddr(model)
-	err = n.ipt.DeleteIfExists("nat", "POSTROUTING",
+	err = n.ipt.DeleteIfExists("nat", n.host.NatChain,
 		"-s", nsVethAddr.IPNet.String(),
 		"-o", n.primaryInterface.Attrs().Name,
 		"-j", "MASQUERADE",
@@ -387,20 +545,89 @@ func (n *namespaceConfigurator) unconfigureInternetAccess(model *interfaces.Netw
 	return nil
 }
 
+// Returns ids of all networks that have any artifacts (namespace, root veth or SNAT rule) on the host
+func (n *namespaceConfigurator) ListConfigured() ([]uint32, error) {
+	ids := make(map[uint32]struct{})
+
+	nsPrefix := n.host.NamespacePrefix + "router-"
+	entries, err := os.ReadDir("/run/netns")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list named network namespaces: %w", err)
+	}
+	for _, entry := range entries {
+		if id, ok := shared.ParseIdSuffix(entry.Name(), nsPrefix); ok {
+			ids[id] = struct{}{}
+		}
+	}
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links of the root namespace: %w", err)
+	}
+	for _, link := range links {
+		if id, ok := shared.ParseIdSuffix(link.Attrs().Name, n.host.InterfacePrefix+"r-"); ok {
+			ids[id] = struct{}{}
+		}
+	}
+
+	rules, err := n.ipt.List("nat", n.host.NatChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SNAT rules: %w", err)
+	}
+	networkIpStart := n.transitStart()
+	for _, rule := range rules {
+		// The rule of the namespace veth end is found here too when it was left in the root namespace
+		if id, ok := ParseNsVethSnatRule(rule, n.host.InterfacePrefix); ok {
+			ids[id] = struct{}{}
+			continue
+		}
+
+		fields := strings.Fields(rule)
+		if !slices.Contains(fields, "MASQUERADE") || !slices.Contains(fields, n.primaryInterface.Attrs().Name) {
+			continue
+		}
+
+		i := slices.Index(fields, "-s")
+		if i < 0 || i+1 >= len(fields) {
+			continue
+		}
+
+		_, source, err := net.ParseCIDR(fields[i+1])
+		if err != nil {
+			continue
+		}
+
+		ones, _ := source.Mask.Size()
+		ip := source.IP.To4()
+		if ones != 30 || ip == nil {
+			continue
+		}
+
+		ip32 := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+		if !n.transitRange.Contains(ip) {
+			continue
+		}
+
+		ids[(ip32-networkIpStart)>>2] = struct{}{}
+	}
+
+	return slices.Sorted(maps.Keys(ids)), nil
+}
+
 func (n *namespaceConfigurator) GetNetworkNamespaceName(id uint32) string {
-	return fmt.Sprintf("bx2cloud-router-%d", id)
+	return fmt.Sprintf("%srouter-%d", n.host.NamespacePrefix, id)
 }
 
 func (n *namespaceConfigurator) getRootVethName(model *interfaces.NetworkModel) string {
-	return fmt.Sprintf("bx2-r-%d", model.Id)
+	return fmt.Sprintf("%sr-%d", n.host.InterfacePrefix, model.Id)
 }
 
 func (n *namespaceConfigurator) getNsVethName(model *interfaces.NetworkModel) string {
-	return fmt.Sprintf("bx2-r-%d-ns", model.Id)
+	return fmt.Sprintf("%sr-%d-ns", n.host.InterfacePrefix, model.Id)
 }
 
 func (n *namespaceConfigurator) getRootVethAddr(model *interfaces.NetworkModel) *netlink.Addr {
-	const networkIpStart uint32 = 0b_11000000_10100111_00000000_00000000
+	networkIpStart := n.transitStart()
 	networkIp := networkIpStart + model.Id<<2
 	vethIp := networkIp + 1
 
@@ -413,7 +640,7 @@ func (n *namespaceConfigurator) getRootVethAddr(model *interfaces.NetworkModel)
 }
 
 func (n *namespaceConfigurator) getNsVethAddr(model *interfaces.NetworkModel) *netlink.Addr {
-	const networkIpStart uint32 = 0b_11000000_10100111_00000000_00000000
+	networkIpStart := n.transitStart()
 	networkIp := networkIpStart + model.Id<<2
 	vethIp := networkIp + 2
 
@@ -424,3 +651,9 @@ func (n *namespaceConfigurator) getNsVethAddr(model *interfaces.NetworkModel) *n
 		},
 	}
 }
+
+// Every network gets a /30 of the transit range, which holds the addresses of both ends of its router's veth
+func (n *namespaceConfigurator) transitStart() uint32 {
+	ip := n.transitRange.IP.To4()
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+}
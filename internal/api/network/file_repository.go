@@ -0,0 +1,43 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BenasB/bx2cloud/internal/api/id"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/persistence"
+)
+
+// Keeps networks in memory and persists every change to a JSON file before acknowledging it
+func NewFileRepository(stateDir string) (interfaces.NetworkRepository, error) {
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the state directory: %w", err)
+	}
+
+	path := filepath.Join(stateDir, "networks.json")
+	networks, lastId, err := persistence.LoadCollection(path, func() *interfaces.NetworkModel {
+		return &interfaces.NetworkModel{}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load networks: %w", err)
+	}
+
+	for _, network := range networks {
+		lastId = max(lastId, network.Id)
+		// Networks persisted before resource versions were introduced
+		network.ResourceVersion = max(network.ResourceVersion, 1)
+	}
+	id.Restore("network", lastId)
+
+	return &memoryRepository{
+		networks: networks,
+		persist: func(networks []*interfaces.NetworkModel) error {
+			for _, network := range networks {
+				lastId = max(lastId, network.Id)
+			}
+			return persistence.SaveCollection(path, networks, lastId)
+		},
+	}, nil
+}
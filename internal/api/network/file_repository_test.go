@@ -0,0 +1,62 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNetwork_FileRepository_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	repository, err := network.NewFileRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := repository.Add(&interfaces.NetworkModel{InternetAccess: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := repository.Add(&interfaces.NetworkModel{InternetAccess: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repository.Delete(deleted.Id, nil); err != nil {
+		t.Fatal(err)
+	}
+	kept, err = repository.Update(kept.Id, nil, func(n *interfaces.NetworkModel) {
+		n.InternetAccess = false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := network.NewFileRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := restarted.Get(kept.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(kept, got, protocmp.Transform()); diff != "" {
+		t.Errorf("network mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := restarted.Get(deleted.Id); err == nil {
+		t.Error("A deleted network was loaded after a restart")
+	}
+
+	added, err := restarted.Add(&interfaces.NetworkModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Id <= deleted.Id {
+		t.Errorf("Id %d was handed out after a restart even though ids up to %d were already used", added.Id, deleted.Id)
+	}
+}
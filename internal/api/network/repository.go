@@ -3,19 +3,26 @@ package network
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
 	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var _ interfaces.NetworkRepository = &memoryRepository{}
 
-// Caution: not thread safe
+// The slice is never modified in place, so readers can keep iterating over a snapshot of it without holding the lock
 type memoryRepository struct {
+	mu       sync.RWMutex
 	networks []*interfaces.NetworkModel
+	// Called with the new state before a change is committed, the change is discarded if it fails
+	persist func(networks []*interfaces.NetworkModel) error
 }
 
 func NewMemoryRepository(networks []*interfaces.NetworkModel) interfaces.NetworkRepository {
@@ -26,30 +33,40 @@ func NewMemoryRepository(networks []*interfaces.NetworkModel) interfaces.Network
 
 	return &memoryRepository{
 		networks: sns,
+		persist: func(networks []*interfaces.NetworkModel) error {
+			return nil
+		},
 	}
 }
 
 func (r *memoryRepository) Get(id uint32) (*interfaces.NetworkModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, network := range r.networks {
 		if network.Id == id {
-			return network, nil
+			return proto.Clone(network).(*interfaces.NetworkModel), nil
 		}
 	}
 
-	return nil, fmt.Errorf("could not find network with id %d", id)
+	return nil, apierrors.NotFound("could not find network with id %d", id)
 }
 
 func (r *memoryRepository) GetAll(ctx context.Context) (<-chan *interfaces.NetworkModel, <-chan error) {
 	results := make(chan *interfaces.NetworkModel, 0)
 	errChan := make(chan error, 1)
 
+	r.mu.RLock()
+	networks := r.networks
+	r.mu.RUnlock()
+
 	go func() {
 		defer close(results)
 		defer close(errChan)
 
-		for _, network := range r.networks {
+		for _, network := range networks {
 			select {
-			case results <- network:
+			case results <- proto.Clone(network).(*interfaces.NetworkModel):
 			case <-ctx.Done():
 				errChan <- ctx.Err()
 				return
@@ -60,33 +77,141 @@ func (r *memoryRepository) GetAll(ctx context.Context) (<-chan *interfaces.Netwo
 	return results, errChan
 }
 
+func (r *memoryRepository) List(ctx context.Context, filter *interfaces.NetworkFilter, options *listing.Options) ([]*interfaces.NetworkModel, bool, error) {
+	r.mu.RLock()
+	networks := r.networks
+	r.mu.RUnlock()
+
+	matching := make([]*interfaces.NetworkModel, 0)
+	for _, network := range networks {
+		if network.ProjectId != filter.ProjectId {
+			continue
+		}
+		if options.MatchesLabels(network.Labels) {
+			matching = append(matching, network)
+		}
+	}
+
+	page, more := listing.Page(matching, options, interfaces.NetworkOrderFields)
+	for i, network := range page {
+		page[i] = proto.Clone(network).(*interfaces.NetworkModel)
+	}
+
+	return page, more, ctx.Err()
+}
+
 func (r *memoryRepository) Add(network *interfaces.NetworkModel) (*interfaces.NetworkModel, error) {
-	//newNetwork := *network
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkNameAvailable(network.ProjectId, network.Name, 0); err != nil {
+		return nil, err
+	}
+
 	newNetwork := proto.Clone(network).(*interfaces.NetworkModel)
-	newNetwork.Id = id.NextId("network")
+	// The caller may have taken the id from id.NextId already, so that it can lock the id before the network can be seen
+	if newNetwork.Id == 0 {
+		newNetwork.Id = id.NextId("network")
+	} else if slices.ContainsFunc(r.networks, func(existing *interfaces.NetworkModel) bool { return existing.Id == newNetwork.Id }) {
+		return nil, fmt.Errorf("network with id %d already exists", newNetwork.Id)
+	}
 	newNetwork.CreatedAt = timestamppb.New(time.Now())
-	r.networks = append(r.networks, newNetwork)
-	return newNetwork, nil
+	newNetwork.ResourceVersion = 1
+
+	networks := append(slices.Clone(r.networks), newNetwork)
+	if err := r.persist(networks); err != nil {
+		return nil, fmt.Errorf("failed to persist the new network: %w", err)
+	}
+
+	r.networks = networks
+	return proto.Clone(newNetwork).(*interfaces.NetworkModel), nil
 }
 
-func (r *memoryRepository) Delete(id uint32) (*interfaces.NetworkModel, error) {
+func (r *memoryRepository) Delete(id uint32, expectedVersion *uint64) (*interfaces.NetworkModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i, network := range r.networks {
-		if network.Id == id {
-			r.networks = append(r.networks[:i], r.networks[i+1:]...)
-			return network, nil
+		if network.Id != id {
+			continue
+		}
+
+		if err := interfaces.CheckResourceVersion(expectedVersion, network.ResourceVersion); err != nil {
+			return nil, err
 		}
+
+		networks := slices.Delete(slices.Clone(r.networks), i, i+1)
+		if err := r.persist(networks); err != nil {
+			return nil, fmt.Errorf("failed to persist the network deletion: %w", err)
+		}
+
+		r.networks = networks
+		return network, nil
 	}
 
-	return nil, fmt.Errorf("could not find network with id %d", id)
+	return nil, apierrors.NotFound("could not find network with id %d", id)
 }
 
-func (r *memoryRepository) Update(id uint32, updateFn func(*interfaces.NetworkModel)) (*interfaces.NetworkModel, error) {
+func (r *memoryRepository) Update(id uint32, expectedVersion *uint64, updateFn func(*interfaces.NetworkModel)) (*interfaces.NetworkModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, network := range r.networks {
+		if network.Id != id {
+			continue
+		}
+
+		if err := interfaces.CheckResourceVersion(expectedVersion, network.ResourceVersion); err != nil {
+			return nil, err
+		}
+
+		updated := proto.Clone(network).(*interfaces.NetworkModel)
+		updateFn(updated)
+		updated.Id = network.Id
+		updated.ProjectId = network.ProjectId
+		updated.CreatedBy = network.CreatedBy
+		if err := r.checkNameAvailable(updated.ProjectId, updated.Name, network.Id); err != nil {
+			return nil, err
+		}
+		updated.ResourceVersion = network.ResourceVersion + 1
+
+		networks := slices.Clone(r.networks)
+		networks[i] = updated
+		if err := r.persist(networks); err != nil {
+			return nil, fmt.Errorf("failed to persist the network update: %w", err)
+		}
+
+		r.networks = networks
+		return proto.Clone(updated).(*interfaces.NetworkModel), nil
+	}
+
+	return nil, apierrors.NotFound("could not find network with id %d", id)
+}
+
+func (r *memoryRepository) GetByName(projectId uint32, name string) (*interfaces.NetworkModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, network := range r.networks {
-		if network.Id == id {
-			updateFn(network)
-			return network, nil
+		if name != "" && network.ProjectId == projectId && network.Name == name {
+			return proto.Clone(network).(*interfaces.NetworkModel), nil
+		}
+	}
+
+	return nil, apierrors.NotFound("could not find network with name %q", name)
+}
+
+// Must be called with the lock held, the network with exceptId is allowed to keep its own name
+func (r *memoryRepository) checkNameAvailable(projectId uint32, name string, exceptId uint32) error {
+	if err := interfaces.ValidateName(name); err != nil {
+		return err
+	}
+
+	for _, network := range r.networks {
+		if name != "" && network.ProjectId == projectId && network.Name == name && network.Id != exceptId {
+			return fmt.Errorf("%w: network %d is already named %q", interfaces.ErrNameTaken, network.Id, name)
 		}
 	}
 
-	return nil, fmt.Errorf("could not find network with id %d", id)
+	return nil
 }
@@ -2,10 +2,18 @@ package network
 
 import (
 	"context"
-	"fmt"
+	"slices"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/events"
+	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -15,27 +23,41 @@ type service struct {
 	repository           interfaces.NetworkRepository
 	subnetworkRepository interfaces.SubnetworkRepository
 	configurator         configurator
+	quotas               interfaces.QuotaChecker
+	idempotency          interfaces.IdempotencyTracker
+	events               *events.Broker[*pb.Network]
 }
 
-func NewService(repository interfaces.NetworkRepository, subnetworkRepository interfaces.SubnetworkRepository, configurator configurator) *service {
+func NewService(repository interfaces.NetworkRepository, subnetworkRepository interfaces.SubnetworkRepository, configurator configurator, quotas interfaces.QuotaChecker, idempotency interfaces.IdempotencyTracker) *service {
 	return &service{
 		repository:           repository,
 		subnetworkRepository: subnetworkRepository,
 		configurator:         configurator,
+		quotas:               quotas,
+		idempotency:          idempotency,
+		events:               events.NewBroker[*pb.Network](events.DEFAULT_HISTORY_SIZE),
 	}
 }
 
 func (s *service) Get(ctx context.Context, req *pb.NetworkIdentificationRequest) (*pb.Network, error) {
-	return s.repository.Get(req.Id)
+	return s.resolve(ctx, req)
 }
 
 func (s *service) Delete(ctx context.Context, req *pb.NetworkIdentificationRequest) (*emptypb.Empty, error) {
-	subnetworks, errors := s.subnetworkRepository.GetAllByNetworkId(req.Id, ctx)
+	existing, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	id := existing.Id
+
+	defer shared.NetworkLocks.Lock(id)()
+
+	subnetworks, errors := s.subnetworkRepository.GetAllByNetworkId(id, ctx)
 	select {
 	case subnetwork, ok := <-subnetworks:
 		if ok {
 			// TODO: Move to sentinel errors
-			return nil, fmt.Errorf("subnetwork with id %d still depends on the network with id %d", subnetwork.Id, req.Id)
+			return nil, apierrors.FailedPrecondition("subnetwork with id %d still depends on the network with id %d", subnetwork.Id, id)
 		}
 	case err, ok := <-errors:
 		if ok {
@@ -43,73 +65,215 @@ func (s *service) Delete(ctx context.Context, req *pb.NetworkIdentificationReque
 		}
 	}
 
-	network, err := s.repository.Delete(req.Id)
+	network, err := s.repository.Delete(id, req.ResourceVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.configurator.Unconfigure(network); err != nil {
+	if err := s.configurator.Unconfigure(ctx, network); err != nil {
 		return nil, err
 	}
 
+	s.events.Publish(pb.EventType_DELETED, network)
+
 	return &emptypb.Empty{}, nil
 }
 
 func (s *service) Create(ctx context.Context, req *pb.NetworkCreationRequest) (*pb.Network, error) {
+	if err := labels.Validate(req.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseKey, err := s.idempotency.Claim(ctx, projectId, interfaces.IDEMPOTENCY_NETWORKS, req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseKey()
+
+	if existing, err := s.createdWithKey(ctx, projectId, req.IdempotencyKey); err != nil || existing != nil {
+		return existing, err
+	}
+
+	createdBy := auth.Identity(ctx)
+	release, err := s.quotas.Reserve(ctx, createdBy, interfaces.QUOTA_NETWORKS)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	networkId := id.NextId("network")
+
+	// Held until the network is configured, before it can be seen in the repository
+	defer shared.NetworkLocks.Lock(networkId)()
+
 	newNetwork := &interfaces.NetworkModel{
+		Id:             networkId,
 		InternetAccess: req.InternetAccess,
+		Labels:         req.Labels,
+		Name:           req.Name,
+		ProjectId:      projectId,
+		IdempotencyKey: req.IdempotencyKey,
+		CreatedBy:      createdBy,
 	}
 
-	returnedNetwork, err := s.repository.Add(newNetwork)
+	returnedNetwork, err := func() (*interfaces.NetworkModel, error) {
+		unlock, err := project.LockCaller(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		return s.repository.Add(newNetwork)
+	}()
 	if err != nil {
 		return nil, err
 	}
+	// The network is counted from the repository now
+	release()
 
 	// TODO: eventual consistency mechanism?
-	if err := s.configurator.Configure(returnedNetwork); err != nil {
+	if err := s.configurator.Configure(ctx, returnedNetwork); err != nil {
 		return nil, err
 	}
 
+	s.events.Publish(pb.EventType_ADDED, returnedNetwork)
+
 	return returnedNetwork, nil
 }
 
 func (s *service) Update(ctx context.Context, req *pb.NetworkUpdateRequest) (*pb.Network, error) {
-	network, err := s.repository.Update(req.Identification.Id, func(sn *interfaces.NetworkModel) {
+	if err := labels.Validate(req.Update.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	existing, err := s.resolve(ctx, req.Identification)
+	if err != nil {
+		return nil, err
+	}
+
+	defer shared.NetworkLocks.Lock(existing.Id)()
+
+	network, err := s.repository.Update(existing.Id, req.Identification.ResourceVersion, func(sn *interfaces.NetworkModel) {
 		sn.InternetAccess = req.Update.InternetAccess
+		sn.Labels = req.Update.Labels
+		sn.Name = req.Update.Name
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.configurator.Configure(network); err != nil {
+	if err := s.configurator.Configure(ctx, network); err != nil {
 		return nil, err
 	}
 
+	s.events.Publish(pb.EventType_MODIFIED, network)
+
 	return network, nil
 }
 
-func (s *service) List(req *emptypb.Empty, stream grpc.ServerStreamingServer[pb.Network]) error {
-	networks, errors := s.repository.GetAll(stream.Context())
-
-	for {
-		select {
-		case network, ok := <-networks:
-			if !ok {
-				select {
-				case err := <-errors:
-					return err
-				default:
-					return nil
-				}
-			}
-			if err := stream.Send(network); err != nil {
-				return err
-			}
-		case err, ok := <-errors:
-			if ok {
-				return err
+func (s *service) List(req *pb.ListRequest, stream grpc.ServerStreamingServer[pb.Network]) error {
+	if err := listing.CheckFilters(req, "networks"); err != nil {
+		return err
+	}
+
+	options, err := listing.FromRequest(req, interfaces.NetworkOrderFields)
+	if err != nil {
+		return err
+	}
+
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	filter := &interfaces.NetworkFilter{
+		ProjectId: projectId,
+	}
+
+	networks, more, err := s.repository.List(stream.Context(), filter, options)
+	if err != nil {
+		return err
+	}
+
+	for _, network := range networks {
+		if err := stream.Send(network); err != nil {
+			return err
+		}
+	}
+
+	listing.SetNextPageToken(stream, options.NextPageToken(more))
+	return nil
+}
+
+func (s *service) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.NetworkEvent]) error {
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	return events.Serve(stream.Context(), s.events, req.SinceRevision,
+		func() ([]*pb.Network, error) {
+			networks, err := shared.CollectAll(s.repository.GetAll(stream.Context()))
+			return slices.DeleteFunc(networks, func(n *pb.Network) bool { return n.ProjectId != projectId }), err
+		},
+		func(event events.Event[*pb.Network]) error {
+			if event.Object.ProjectId != projectId {
+				return nil
 			}
+			return stream.Send(&pb.NetworkEvent{
+				Type:     event.Type,
+				Revision: event.Revision,
+				Network:  event.Object,
+			})
+		},
+	)
+}
+
+// Networks can be identified either by id or by name, networks of other projects are not found
+func (s *service) resolve(ctx context.Context, req *pb.NetworkIdentificationRequest) (*interfaces.NetworkModel, error) {
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := req.Identifier.(*pb.NetworkIdentificationRequest_Name); ok {
+		return s.repository.GetByName(projectId, req.GetName())
+	}
+
+	network, err := s.repository.Get(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if network.ProjectId != projectId {
+		return nil, apierrors.NotFound("could not find network with id %d", req.GetId())
+	}
+
+	return network, nil
+}
+
+// Returns the network that an earlier request with the same idempotency key created, or nil if there is none
+func (s *service) createdWithKey(ctx context.Context, projectId uint32, key string) (*interfaces.NetworkModel, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	networks, err := shared.CollectAll(s.repository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, network := range networks {
+		if network.ProjectId == projectId && network.IdempotencyKey == key && s.idempotency.Retains(network.CreatedAt.AsTime()) {
+			return network, nil
 		}
 	}
+
+	return nil, nil
 }
@@ -6,14 +6,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
 	"github.com/BenasB/bx2cloud/internal/api/network"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
 	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
-	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -35,7 +39,7 @@ var mockConfigurator = network.NewMockConfigurator()
 func TestNetwork_Create(t *testing.T) {
 	repository := network.NewMemoryRepository(nil)
 	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
-	service := network.NewService(repository, subnetworkRepository, mockConfigurator)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
 	req := &pb.NetworkCreationRequest{
 		InternetAccess: true,
 	}
@@ -46,15 +50,63 @@ func TestNetwork_Create(t *testing.T) {
 	}
 }
 
+func TestNetwork_Create_IdempotencyKey(t *testing.T) {
+	repository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewTracker(time.Hour))
+	req := &pb.NetworkCreationRequest{
+		Name:           "retried",
+		IdempotencyKey: "0d2f6c1e",
+	}
+
+	first, err := service.Create(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fails on the taken name if it is not recognized as a repeated request
+	second, err := service.Create(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(first, second, protocmp.Transform()); diff != "" {
+		t.Errorf("Repeated request returned a different network (-first +second):\n%s", diff)
+	}
+}
+
+func TestNetwork_Create_IdempotencyKeyExpired(t *testing.T) {
+	repository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewTracker(time.Nanosecond))
+	req := &pb.NetworkCreationRequest{
+		IdempotencyKey: "0d2f6c1e",
+	}
+
+	first, err := service.Create(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := service.Create(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Id == second.Id {
+		t.Errorf("Expected a new network once the key is no longer retained, got network %d again", first.Id)
+	}
+}
+
 func TestNetwork_Delete(t *testing.T) {
 	for _, tt := range testNetworks {
 		repository := network.NewMemoryRepository(testNetworks)
 		subnetworkRepository := subnetwork.NewMemoryRepository(nil)
-		service := network.NewService(repository, subnetworkRepository, mockConfigurator)
+		service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(strconv.FormatUint(uint64(tt.Id), 10), func(t *testing.T) {
 			_, err := service.Delete(t.Context(), &pb.NetworkIdentificationRequest{
-				Id: tt.Id,
+				Identifier: &pb.NetworkIdentificationRequest_Id{Id: tt.Id},
 			})
 			if err != nil {
 				t.Error(err)
@@ -78,15 +130,18 @@ func TestNetwork_Delete_SubnetworksExist(t *testing.T) {
 	for _, tt := range testNetworks {
 		repository := network.NewMemoryRepository(testNetworks)
 		subnetworkRepository := subnetwork.NewMemoryRepository(testSubnetworks)
-		service := network.NewService(repository, subnetworkRepository, mockConfigurator)
+		service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(strconv.FormatUint(uint64(tt.Id), 10), func(t *testing.T) {
 			_, err := service.Delete(t.Context(), &pb.NetworkIdentificationRequest{
-				Id: tt.Id,
+				Identifier: &pb.NetworkIdentificationRequest_Id{Id: tt.Id},
 			})
 			if err == nil || !strings.Contains(err.Error(), "still depends") {
 				t.Error("Network was deleted even though it shouldn't have because a subnetwork depended on it")
 			}
+			if status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("Expected %v, got %v", codes.FailedPrecondition, status.Code(err))
+			}
 		})
 	}
 }
@@ -94,25 +149,28 @@ func TestNetwork_Delete_SubnetworksExist(t *testing.T) {
 func TestNetwork_Delete_NetworkDoesNotExist(t *testing.T) {
 	repository := network.NewMemoryRepository(nil)
 	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
-	service := network.NewService(repository, subnetworkRepository, mockConfigurator)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 	_, err := service.Delete(t.Context(), &pb.NetworkIdentificationRequest{
-		Id: 1,
+		Identifier: &pb.NetworkIdentificationRequest_Id{Id: 1},
 	})
 	if err == nil {
 		t.Error("There was no error returned by delete even though the network that we tried deleting does not exist")
 	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected %v, got %v", codes.NotFound, status.Code(err))
+	}
 }
 
 func TestNetwork_Get(t *testing.T) {
 	for _, tt := range testNetworks {
 		repository := network.NewMemoryRepository(testNetworks)
 		subnetworkRepository := subnetwork.NewMemoryRepository(nil)
-		service := network.NewService(repository, subnetworkRepository, mockConfigurator)
+		service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(strconv.FormatUint(uint64(tt.Id), 10), func(t *testing.T) {
 			resp, err := service.Get(t.Context(), &pb.NetworkIdentificationRequest{
-				Id: tt.Id,
+				Identifier: &pb.NetworkIdentificationRequest_Id{Id: tt.Id},
 			})
 			if err != nil {
 				t.Error(err)
@@ -129,8 +187,8 @@ func TestNetwork_List(t *testing.T) {
 
 	repository := network.NewMemoryRepository(testNetworks)
 	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
-	service := network.NewService(repository, subnetworkRepository, mockConfigurator)
-	service.List(&emptypb.Empty{}, stream)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+	service.List(&pb.ListRequest{}, stream)
 
 	if len(testNetworks) != len(stream.SentItems) {
 		t.Error("not the same amount of networks received")
@@ -142,3 +200,144 @@ func TestNetwork_List(t *testing.T) {
 		}
 	}
 }
+
+func TestNetwork_Update_BumpsResourceVersion(t *testing.T) {
+	repository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	created, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.Update(t.Context(), &pb.NetworkUpdateRequest{
+		Identification: &pb.NetworkIdentificationRequest{
+			Identifier:      &pb.NetworkIdentificationRequest_Id{Id: created.Id},
+			ResourceVersion: proto.Uint64(created.ResourceVersion),
+		},
+		Update: &pb.NetworkCreationRequest{
+			InternetAccess: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.ResourceVersion <= created.ResourceVersion {
+		t.Errorf("Resource version did not increase after an update: %d -> %d", created.ResourceVersion, updated.ResourceVersion)
+	}
+}
+
+func TestNetwork_Update_ResourceVersionMismatch(t *testing.T) {
+	repository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	created, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale := &pb.NetworkIdentificationRequest{
+		Identifier:      &pb.NetworkIdentificationRequest_Id{Id: created.Id},
+		ResourceVersion: proto.Uint64(created.ResourceVersion + 1),
+	}
+
+	_, err = service.Update(t.Context(), &pb.NetworkUpdateRequest{
+		Identification: stale,
+		Update: &pb.NetworkCreationRequest{
+			InternetAccess: true,
+		},
+	})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected an update with a stale resource version to be aborted, got %v", err)
+	}
+
+	_, err = service.Delete(t.Context(), stale)
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected a delete with a stale resource version to be aborted, got %v", err)
+	}
+
+	got, err := service.Get(t.Context(), &pb.NetworkIdentificationRequest{Identifier: &pb.NetworkIdentificationRequest_Id{Id: created.Id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(created, got, protocmp.Transform()); diff != "" {
+		t.Errorf("network was modified by an aborted request (-want +got):\n%s", diff)
+	}
+}
+
+func TestNetwork_List_LabelSelector(t *testing.T) {
+	repository := network.NewMemoryRepository([]*interfaces.NetworkModel{
+		{Id: 1, Labels: map[string]string{"env": "prod", "team": "payments"}},
+		{Id: 2, Labels: map[string]string{"env": "dev"}},
+		{Id: 3},
+	})
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	tests := map[string][]uint32{
+		"":                   {1, 2, 3},
+		"env=prod":           {1},
+		"env in (prod,dev)":  {1, 2},
+		"env!=prod":          {2, 3},
+		"!env":               {3},
+		"team,env notin (x)": {1},
+	}
+
+	for selector, expected := range tests {
+		t.Run(selector, func(t *testing.T) {
+			stream := shared.NewMockStream[*pb.Network](t.Context())
+			if err := service.List(&pb.ListRequest{LabelSelector: selector}, stream); err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]uint32, 0, len(stream.SentItems))
+			for _, network := range stream.SentItems {
+				actual = append(actual, network.Id)
+			}
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("listed networks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	err := service.List(&pb.ListRequest{LabelSelector: "env in prod"}, shared.NewMockStream[*pb.Network](t.Context()))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected %v for an invalid selector, got %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestNetwork_Name(t *testing.T) {
+	repository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	service := network.NewService(repository, subnetworkRepository, mockConfigurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	created, err := service.Create(t.Context(), &pb.NetworkCreationRequest{Name: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := service.Get(t.Context(), &pb.NetworkIdentificationRequest{
+		Identifier: &pb.NetworkIdentificationRequest_Name{Name: "backend"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != created.Id {
+		t.Errorf("Expected to find network %d by name, got %d", created.Id, found.Id)
+	}
+
+	_, err = service.Create(t.Context(), &pb.NetworkCreationRequest{Name: "backend"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected %v for a duplicate name, got %v", codes.AlreadyExists, err)
+	}
+
+	for _, name := range []string{"42", "Backend", "back_end", "-backend"} {
+		_, err = service.Create(t.Context(), &pb.NetworkCreationRequest{Name: name})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected %v for name %q, got %v", codes.InvalidArgument, name, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package network
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+)
+
+// Parses the id of the network out of a listed "-o <prefix>r-<id>-ns -j MASQUERADE" rule, which translates for the network's namespace veth end
+func ParseNsVethSnatRule(rule string, interfacePrefix string) (uint32, bool) {
+	fields := strings.Fields(rule)
+	if !slices.Contains(fields, "MASQUERADE") {
+		return 0, false
+	}
+
+	i := slices.Index(fields, "-o")
+	if i < 0 || i+1 >= len(fields) {
+		return 0, false
+	}
+
+	name, ok := strings.CutSuffix(fields[i+1], "-ns")
+	if !ok {
+		return 0, false
+	}
+
+	return shared.ParseIdSuffix(name, interfacePrefix+"r-")
+}
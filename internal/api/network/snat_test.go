@@ -0,0 +1,31 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/network"
+)
+
+func TestNetwork_ParseNsVethSnatRule(t *testing.T) {
+	tests := map[string]struct {
+		rule   string
+		wantId uint32
+		wantOk bool
+	}{
+		"namespace veth end":    {"-A POSTROUTING -o bx2-r-3-ns -j MASQUERADE", 3, true},
+		"root veth end":         {"-A POSTROUTING -o bx2-r-3 -j MASQUERADE", 0, false},
+		"primary interface":     {"-A POSTROUTING -s 10.255.0.12/30 -o eth0 -j MASQUERADE", 0, false},
+		"another prefix":        {"-A POSTROUTING -o other-r-3-ns -j MASQUERADE", 0, false},
+		"not masquerading":      {"-A POSTROUTING -o bx2-r-3-ns -j ACCEPT", 0, false},
+		"missing out-interface": {"-A POSTROUTING -j MASQUERADE -o", 0, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, ok := network.ParseNsVethSnatRule(test.rule, "bx2-")
+			if id != test.wantId || ok != test.wantOk {
+				t.Errorf("expected (%d, %t), got (%d, %t)", test.wantId, test.wantOk, id, ok)
+			}
+		})
+	}
+}
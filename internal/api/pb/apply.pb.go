@@ -0,0 +1,843 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: apply.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChangeAction int32
+
+const (
+	ChangeAction_CHANGE_ACTION_UNSPECIFIED ChangeAction = 0
+	ChangeAction_UNCHANGED                 ChangeAction = 1
+	ChangeAction_CREATE                    ChangeAction = 2
+	ChangeAction_UPDATE                    ChangeAction = 3
+	// Deleted and created again, since the changed fields can not be updated in place
+	ChangeAction_REPLACE ChangeAction = 4
+	ChangeAction_DELETE  ChangeAction = 5
+)
+
+// Enum value maps for ChangeAction.
+var (
+	ChangeAction_name = map[int32]string{
+		0: "CHANGE_ACTION_UNSPECIFIED",
+		1: "UNCHANGED",
+		2: "CREATE",
+		3: "UPDATE",
+		4: "REPLACE",
+		5: "DELETE",
+	}
+	ChangeAction_value = map[string]int32{
+		"CHANGE_ACTION_UNSPECIFIED": 0,
+		"UNCHANGED":                 1,
+		"CREATE":                    2,
+		"UPDATE":                    3,
+		"REPLACE":                   4,
+		"DELETE":                    5,
+	}
+)
+
+func (x ChangeAction) Enum() *ChangeAction {
+	p := new(ChangeAction)
+	*p = x
+	return p
+}
+
+func (x ChangeAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_apply_proto_enumTypes[0].Descriptor()
+}
+
+func (ChangeAction) Type() protoreflect.EnumType {
+	return &file_apply_proto_enumTypes[0]
+}
+
+func (x ChangeAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeAction.Descriptor instead.
+func (ChangeAction) EnumDescriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{0}
+}
+
+type ApplyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resources are identified and reference each other by name, references can also name existing resources that are not in the manifest
+	Resources []*ManifestResource `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+	// Optional, the resources of the manifest get a "bx2cloud.io/manifest" label with this name, which pruning relies on
+	Manifest string `protobuf:"bytes,2,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	// Deletes the resources that have the manifest's label but are no longer in the manifest, requires a manifest name
+	Prune bool `protobuf:"varint,3,opt,name=prune,proto3" json:"prune,omitempty"`
+	// Only plans the changes
+	DryRun        bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyRequest) Reset() {
+	*x = ApplyRequest{}
+	mi := &file_apply_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyRequest) ProtoMessage() {}
+
+func (x *ApplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyRequest.ProtoReflect.Descriptor instead.
+func (*ApplyRequest) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ApplyRequest) GetResources() []*ManifestResource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *ApplyRequest) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetPrune() bool {
+	if x != nil {
+		return x.Prune
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ManifestResource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Resource:
+	//
+	//	*ManifestResource_Network
+	//	*ManifestResource_Subnetwork
+	//	*ManifestResource_Container
+	Resource      isManifestResource_Resource `protobuf_oneof:"resource"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestResource) Reset() {
+	*x = ManifestResource{}
+	mi := &file_apply_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestResource) ProtoMessage() {}
+
+func (x *ManifestResource) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestResource.ProtoReflect.Descriptor instead.
+func (*ManifestResource) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ManifestResource) GetResource() isManifestResource_Resource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
+}
+
+func (x *ManifestResource) GetNetwork() *ManifestNetwork {
+	if x != nil {
+		if x, ok := x.Resource.(*ManifestResource_Network); ok {
+			return x.Network
+		}
+	}
+	return nil
+}
+
+func (x *ManifestResource) GetSubnetwork() *ManifestSubnetwork {
+	if x != nil {
+		if x, ok := x.Resource.(*ManifestResource_Subnetwork); ok {
+			return x.Subnetwork
+		}
+	}
+	return nil
+}
+
+func (x *ManifestResource) GetContainer() *ManifestContainer {
+	if x != nil {
+		if x, ok := x.Resource.(*ManifestResource_Container); ok {
+			return x.Container
+		}
+	}
+	return nil
+}
+
+type isManifestResource_Resource interface {
+	isManifestResource_Resource()
+}
+
+type ManifestResource_Network struct {
+	Network *ManifestNetwork `protobuf:"bytes,1,opt,name=network,proto3,oneof"`
+}
+
+type ManifestResource_Subnetwork struct {
+	Subnetwork *ManifestSubnetwork `protobuf:"bytes,2,opt,name=subnetwork,proto3,oneof"`
+}
+
+type ManifestResource_Container struct {
+	Container *ManifestContainer `protobuf:"bytes,3,opt,name=container,proto3,oneof"`
+}
+
+func (*ManifestResource_Network) isManifestResource_Resource() {}
+
+func (*ManifestResource_Subnetwork) isManifestResource_Resource() {}
+
+func (*ManifestResource_Container) isManifestResource_Resource() {}
+
+type ManifestNetwork struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required
+	Name           string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	InternetAccess bool              `protobuf:"varint,2,opt,name=internet_access,json=internetAccess,proto3" json:"internet_access,omitempty"`
+	Labels         map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ManifestNetwork) Reset() {
+	*x = ManifestNetwork{}
+	mi := &file_apply_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestNetwork) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestNetwork) ProtoMessage() {}
+
+func (x *ManifestNetwork) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestNetwork.ProtoReflect.Descriptor instead.
+func (*ManifestNetwork) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ManifestNetwork) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ManifestNetwork) GetInternetAccess() bool {
+	if x != nil {
+		return x.InternetAccess
+	}
+	return false
+}
+
+func (x *ManifestNetwork) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ManifestSubnetwork struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Name of the network the subnetwork belongs to
+	Network       string            `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	Address       uint32            `protobuf:"fixed32,3,opt,name=address,proto3" json:"address,omitempty"`
+	PrefixLength  uint32            `protobuf:"fixed32,4,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestSubnetwork) Reset() {
+	*x = ManifestSubnetwork{}
+	mi := &file_apply_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestSubnetwork) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestSubnetwork) ProtoMessage() {}
+
+func (x *ManifestSubnetwork) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestSubnetwork.ProtoReflect.Descriptor instead.
+func (*ManifestSubnetwork) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ManifestSubnetwork) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ManifestSubnetwork) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ManifestSubnetwork) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ManifestSubnetwork) GetPrefixLength() uint32 {
+	if x != nil {
+		return x.PrefixLength
+	}
+	return 0
+}
+
+func (x *ManifestSubnetwork) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ManifestContainer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Name of the subnetwork the container joins
+	Subnetwork    string            `protobuf:"bytes,2,opt,name=subnetwork,proto3" json:"subnetwork,omitempty"`
+	Image         string            `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Entrypoint    []string          `protobuf:"bytes,4,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd           []string          `protobuf:"bytes,5,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	Env           []string          `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestContainer) Reset() {
+	*x = ManifestContainer{}
+	mi := &file_apply_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestContainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestContainer) ProtoMessage() {}
+
+func (x *ManifestContainer) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestContainer.ProtoReflect.Descriptor instead.
+func (*ManifestContainer) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ManifestContainer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ManifestContainer) GetSubnetwork() string {
+	if x != nil {
+		return x.Subnetwork
+	}
+	return ""
+}
+
+func (x *ManifestContainer) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ManifestContainer) GetEntrypoint() []string {
+	if x != nil {
+		return x.Entrypoint
+	}
+	return nil
+}
+
+func (x *ManifestContainer) GetCmd() []string {
+	if x != nil {
+		return x.Cmd
+	}
+	return nil
+}
+
+func (x *ManifestContainer) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ManifestContainer) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type Change struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Action ChangeAction           `protobuf:"varint,1,opt,name=action,proto3,enum=bx2cloud.ChangeAction" json:"action,omitempty"`
+	// One of: network, subnetwork, container
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Id of the resource, for created and replaced resources the new id once applied, 0 in a dry run
+	Id     uint32         `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`
+	Fields []*FieldChange `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Why the resource is replaced or deleted, if it is not because of its own fields
+	Reason        string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_apply_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Change) GetAction() ChangeAction {
+	if x != nil {
+		return x.Action
+	}
+	return ChangeAction_CHANGE_ACTION_UNSPECIFIED
+}
+
+func (x *Change) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Change) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Change) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Change) GetFields() []*FieldChange {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *Change) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type FieldChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. "labels" or "cidr"
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Empty for created resources
+	Current string `protobuf:"bytes,2,opt,name=current,proto3" json:"current,omitempty"`
+	// Empty for deleted resources
+	Desired       string `protobuf:"bytes,3,opt,name=desired,proto3" json:"desired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_apply_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetCurrent() string {
+	if x != nil {
+		return x.Current
+	}
+	return ""
+}
+
+func (x *FieldChange) GetDesired() string {
+	if x != nil {
+		return x.Desired
+	}
+	return ""
+}
+
+type ApplyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deletions first, then networks, subnetworks and containers in the order of the manifest
+	Changes []*Change `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// False for dry runs
+	Applied       bool `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyResponse) Reset() {
+	*x = ApplyResponse{}
+	mi := &file_apply_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyResponse) ProtoMessage() {}
+
+func (x *ApplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_apply_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyResponse.ProtoReflect.Descriptor instead.
+func (*ApplyResponse) Descriptor() ([]byte, []int) {
+	return file_apply_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ApplyResponse) GetChanges() []*Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ApplyResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+var File_apply_proto protoreflect.FileDescriptor
+
+const file_apply_proto_rawDesc = "" +
+	"\n" +
+	"\vapply.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\"\x93\x01\n" +
+	"\fApplyRequest\x128\n" +
+	"\tresources\x18\x01 \x03(\v2\x1a.bx2cloud.ManifestResourceR\tresources\x12\x1a\n" +
+	"\bmanifest\x18\x02 \x01(\tR\bmanifest\x12\x14\n" +
+	"\x05prune\x18\x03 \x01(\bR\x05prune\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\xd2\x01\n" +
+	"\x10ManifestResource\x125\n" +
+	"\anetwork\x18\x01 \x01(\v2\x19.bx2cloud.ManifestNetworkH\x00R\anetwork\x12>\n" +
+	"\n" +
+	"subnetwork\x18\x02 \x01(\v2\x1c.bx2cloud.ManifestSubnetworkH\x00R\n" +
+	"subnetwork\x12;\n" +
+	"\tcontainer\x18\x03 \x01(\v2\x1b.bx2cloud.ManifestContainerH\x00R\tcontainerB\n" +
+	"\n" +
+	"\bresource\"\xc8\x01\n" +
+	"\x0fManifestNetwork\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0finternet_access\x18\x02 \x01(\bR\x0einternetAccess\x12=\n" +
+	"\x06labels\x18\x03 \x03(\v2%.bx2cloud.ManifestNetwork.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfe\x01\n" +
+	"\x12ManifestSubnetwork\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\anetwork\x18\x02 \x01(\tR\anetwork\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\aR\aaddress\x12#\n" +
+	"\rprefix_length\x18\x04 \x01(\aR\fprefixLength\x12@\n" +
+	"\x06labels\x18\x05 \x03(\v2(.bx2cloud.ManifestSubnetwork.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9d\x02\n" +
+	"\x11ManifestContainer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"subnetwork\x18\x02 \x01(\tR\n" +
+	"subnetwork\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1e\n" +
+	"\n" +
+	"entrypoint\x18\x04 \x03(\tR\n" +
+	"entrypoint\x12\x10\n" +
+	"\x03cmd\x18\x05 \x03(\tR\x03cmd\x12\x10\n" +
+	"\x03env\x18\x06 \x03(\tR\x03env\x12?\n" +
+	"\x06labels\x18\a \x03(\v2'.bx2cloud.ManifestContainer.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb7\x01\n" +
+	"\x06Change\x12.\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x16.bx2cloud.ChangeActionR\x06action\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\rR\x02id\x12-\n" +
+	"\x06fields\x18\x05 \x03(\v2\x15.bx2cloud.FieldChangeR\x06fields\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"W\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\acurrent\x18\x02 \x01(\tR\acurrent\x12\x18\n" +
+	"\adesired\x18\x03 \x01(\tR\adesired\"U\n" +
+	"\rApplyResponse\x12*\n" +
+	"\achanges\x18\x01 \x03(\v2\x10.bx2cloud.ChangeR\achanges\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied*m\n" +
+	"\fChangeAction\x12\x1d\n" +
+	"\x19CHANGE_ACTION_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tUNCHANGED\x10\x01\x12\n" +
+	"\n" +
+	"\x06CREATE\x10\x02\x12\n" +
+	"\n" +
+	"\x06UPDATE\x10\x03\x12\v\n" +
+	"\aREPLACE\x10\x04\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x052^\n" +
+	"\fApplyService\x12N\n" +
+	"\x05Apply\x12\x16.bx2cloud.ApplyRequest\x1a\x17.bx2cloud.ApplyResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1:applyB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+
+var (
+	file_apply_proto_rawDescOnce sync.Once
+	file_apply_proto_rawDescData []byte
+)
+
+func file_apply_proto_rawDescGZIP() []byte {
+	file_apply_proto_rawDescOnce.Do(func() {
+		file_apply_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apply_proto_rawDesc), len(file_apply_proto_rawDesc)))
+	})
+	return file_apply_proto_rawDescData
+}
+
+var file_apply_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_apply_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_apply_proto_goTypes = []any{
+	(ChangeAction)(0),          // 0: bx2cloud.ChangeAction
+	(*ApplyRequest)(nil),       // 1: bx2cloud.ApplyRequest
+	(*ManifestResource)(nil),   // 2: bx2cloud.ManifestResource
+	(*ManifestNetwork)(nil),    // 3: bx2cloud.ManifestNetwork
+	(*ManifestSubnetwork)(nil), // 4: bx2cloud.ManifestSubnetwork
+	(*ManifestContainer)(nil),  // 5: bx2cloud.ManifestContainer
+	(*Change)(nil),             // 6: bx2cloud.Change
+	(*FieldChange)(nil),        // 7: bx2cloud.FieldChange
+	(*ApplyResponse)(nil),      // 8: bx2cloud.ApplyResponse
+	nil,                        // 9: bx2cloud.ManifestNetwork.LabelsEntry
+	nil,                        // 10: bx2cloud.ManifestSubnetwork.LabelsEntry
+	nil,                        // 11: bx2cloud.ManifestContainer.LabelsEntry
+}
+var file_apply_proto_depIdxs = []int32{
+	2,  // 0: bx2cloud.ApplyRequest.resources:type_name -> bx2cloud.ManifestResource
+	3,  // 1: bx2cloud.ManifestResource.network:type_name -> bx2cloud.ManifestNetwork
+	4,  // 2: bx2cloud.ManifestResource.subnetwork:type_name -> bx2cloud.ManifestSubnetwork
+	5,  // 3: bx2cloud.ManifestResource.container:type_name -> bx2cloud.ManifestContainer
+	9,  // 4: bx2cloud.ManifestNetwork.labels:type_name -> bx2cloud.ManifestNetwork.LabelsEntry
+	10, // 5: bx2cloud.ManifestSubnetwork.labels:type_name -> bx2cloud.ManifestSubnetwork.LabelsEntry
+	11, // 6: bx2cloud.ManifestContainer.labels:type_name -> bx2cloud.ManifestContainer.LabelsEntry
+	0,  // 7: bx2cloud.Change.action:type_name -> bx2cloud.ChangeAction
+	7,  // 8: bx2cloud.Change.fields:type_name -> bx2cloud.FieldChange
+	6,  // 9: bx2cloud.ApplyResponse.changes:type_name -> bx2cloud.Change
+	1,  // 10: bx2cloud.ApplyService.Apply:input_type -> bx2cloud.ApplyRequest
+	8,  // 11: bx2cloud.ApplyService.Apply:output_type -> bx2cloud.ApplyResponse
+	11, // [11:12] is the sub-list for method output_type
+	10, // [10:11] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_apply_proto_init() }
+func file_apply_proto_init() {
+	if File_apply_proto != nil {
+		return
+	}
+	file_apply_proto_msgTypes[1].OneofWrappers = []any{
+		(*ManifestResource_Network)(nil),
+		(*ManifestResource_Subnetwork)(nil),
+		(*ManifestResource_Container)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apply_proto_rawDesc), len(file_apply_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_apply_proto_goTypes,
+		DependencyIndexes: file_apply_proto_depIdxs,
+		EnumInfos:         file_apply_proto_enumTypes,
+		MessageInfos:      file_apply_proto_msgTypes,
+	}.Build()
+	File_apply_proto = out.File
+	file_apply_proto_goTypes = nil
+	file_apply_proto_depIdxs = nil
+}
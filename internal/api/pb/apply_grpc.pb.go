@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.30.2
+// source: apply.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ApplyService_Apply_FullMethodName = "/bx2cloud.ApplyService/Apply"
+)
+
+// ApplyServiceClient is the client API for ApplyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ApplyServiceClient interface {
+	// Plans the changes that bring the caller's project in line with the manifest and, unless it is a dry run, applies them in dependency order.
+	// Applying is not atomic, it stops at the first failed change and the changes before it stay applied, the error details then have an ApplyResponse with those changes.
+	Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+}
+
+type applyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewApplyServiceClient(cc grpc.ClientConnInterface) ApplyServiceClient {
+	return &applyServiceClient{cc}
+}
+
+func (c *applyServiceClient) Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyResponse)
+	err := c.cc.Invoke(ctx, ApplyService_Apply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApplyServiceServer is the server API for ApplyService service.
+// All implementations must embed UnimplementedApplyServiceServer
+// for forward compatibility.
+type ApplyServiceServer interface {
+	// Plans the changes that bring the caller's project in line with the manifest and, unless it is a dry run, applies them in dependency order.
+	// Applying is not atomic, it stops at the first failed change and the changes before it stay applied, the error details then have an ApplyResponse with those changes.
+	Apply(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	mustEmbedUnimplementedApplyServiceServer()
+}
+
+// UnimplementedApplyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedApplyServiceServer struct{}
+
+func (UnimplementedApplyServiceServer) Apply(context.Context, *ApplyRequest) (*ApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
+}
+func (UnimplementedApplyServiceServer) mustEmbedUnimplementedApplyServiceServer() {}
+func (UnimplementedApplyServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeApplyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ApplyServiceServer will
+// result in compilation errors.
+type UnsafeApplyServiceServer interface {
+	mustEmbedUnimplementedApplyServiceServer()
+}
+
+func RegisterApplyServiceServer(s grpc.ServiceRegistrar, srv ApplyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedApplyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ApplyService_ServiceDesc, srv)
+}
+
+func _ApplyService_Apply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplyServiceServer).Apply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ApplyService_Apply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplyServiceServer).Apply(ctx, req.(*ApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ApplyService_ServiceDesc is the grpc.ServiceDesc for ApplyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ApplyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bx2cloud.ApplyService",
+	HandlerType: (*ApplyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Apply",
+			Handler:    _ApplyService_Apply_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "apply.proto",
+}
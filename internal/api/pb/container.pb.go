@@ -7,6 +7,7 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -24,10 +25,16 @@ const (
 )
 
 type ContainerIdentificationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*ContainerIdentificationRequest_Id
+	//	*ContainerIdentificationRequest_Name
+	Identifier isContainerIdentificationRequest_Identifier `protobuf_oneof:"identifier"`
+	// Expected resource version, Delete, Start and Stop fail with ABORTED if it does not match
+	ResourceVersion *uint64 `protobuf:"varint,2,opt,name=resource_version,json=resourceVersion,proto3,oneof" json:"resource_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ContainerIdentificationRequest) Reset() {
@@ -60,22 +67,69 @@ func (*ContainerIdentificationRequest) Descriptor() ([]byte, []int) {
 	return file_container_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *ContainerIdentificationRequest) GetIdentifier() isContainerIdentificationRequest_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
 func (x *ContainerIdentificationRequest) GetId() uint32 {
 	if x != nil {
-		return x.Id
+		if x, ok := x.Identifier.(*ContainerIdentificationRequest_Id); ok {
+			return x.Id
+		}
 	}
 	return 0
 }
 
+func (x *ContainerIdentificationRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*ContainerIdentificationRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *ContainerIdentificationRequest) GetResourceVersion() uint64 {
+	if x != nil && x.ResourceVersion != nil {
+		return *x.ResourceVersion
+	}
+	return 0
+}
+
+type isContainerIdentificationRequest_Identifier interface {
+	isContainerIdentificationRequest_Identifier()
+}
+
+type ContainerIdentificationRequest_Id struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type ContainerIdentificationRequest_Name struct {
+	Name string `protobuf:"bytes,3,opt,name=name,proto3,oneof"`
+}
+
+func (*ContainerIdentificationRequest_Id) isContainerIdentificationRequest_Identifier() {}
+
+func (*ContainerIdentificationRequest_Name) isContainerIdentificationRequest_Identifier() {}
+
 type ContainerCreationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SubnetworkId  uint32                 `protobuf:"varint,1,opt,name=subnetwork_id,json=subnetworkId,proto3" json:"subnetwork_id,omitempty"`
-	Image         string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
-	Entrypoint    []string               `protobuf:"bytes,3,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
-	Cmd           []string               `protobuf:"bytes,4,rep,name=cmd,proto3" json:"cmd,omitempty"`
-	Env           []string               `protobuf:"bytes,5,rep,name=env,proto3" json:"env,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SubnetworkId uint32                 `protobuf:"varint,1,opt,name=subnetwork_id,json=subnetworkId,proto3" json:"subnetwork_id,omitempty"`
+	Image        string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Entrypoint   []string               `protobuf:"bytes,3,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd          []string               `protobuf:"bytes,4,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	Env          []string               `protobuf:"bytes,5,rep,name=env,proto3" json:"env,omitempty"`
+	Labels       map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Optional, unique among containers. Also used as the container's hostname.
+	Name string `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional, a repeated creation request with the same key returns the container created by the first request instead of creating another one.
+	// Keys are unique within a project and are kept for the retention window configured on the API.
+	IdempotencyKey string `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ContainerCreationRequest) Reset() {
@@ -143,19 +197,50 @@ func (x *ContainerCreationRequest) GetEnv() []string {
 	return nil
 }
 
+func (x *ContainerCreationRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ContainerCreationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerCreationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type Container struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Address       uint32                 `protobuf:"fixed32,2,opt,name=address,proto3" json:"address,omitempty"`
-	PrefixLength  uint32                 `protobuf:"fixed32,3,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	Image         string                 `protobuf:"bytes,5,opt,name=image,proto3" json:"image,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
-	SubnetworkId  uint32                 `protobuf:"varint,8,opt,name=subnetwork_id,json=subnetworkId,proto3" json:"subnetwork_id,omitempty"`
-	Entrypoint    []string               `protobuf:"bytes,9,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
-	Cmd           []string               `protobuf:"bytes,10,rep,name=cmd,proto3" json:"cmd,omitempty"`
-	Env           []string               `protobuf:"bytes,11,rep,name=env,proto3" json:"env,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address      uint32                 `protobuf:"fixed32,2,opt,name=address,proto3" json:"address,omitempty"`
+	PrefixLength uint32                 `protobuf:"fixed32,3,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
+	Status       string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Image        string                 `protobuf:"bytes,5,opt,name=image,proto3" json:"image,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	StartedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	SubnetworkId uint32                 `protobuf:"varint,8,opt,name=subnetwork_id,json=subnetworkId,proto3" json:"subnetwork_id,omitempty"`
+	Entrypoint   []string               `protobuf:"bytes,9,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd          []string               `protobuf:"bytes,10,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	Env          []string               `protobuf:"bytes,11,rep,name=env,proto3" json:"env,omitempty"`
+	// Increases with every change to the container's specification, including starting and stopping it
+	ResourceVersion uint64            `protobuf:"varint,12,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	Labels          map[string]string `protobuf:"bytes,13,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Name            string            `protobuf:"bytes,14,opt,name=name,proto3" json:"name,omitempty"`
+	// Project the container belongs to, 0 is the default project
+	ProjectId uint32 `protobuf:"varint,15,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// Key of the request that created the container, if it had one
+	IdempotencyKey string `protobuf:"bytes,16,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Identity of the caller that created the container, empty for anonymous callers
+	CreatedBy     string `protobuf:"bytes,17,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -267,6 +352,48 @@ func (x *Container) GetEnv() []string {
 	return nil
 }
 
+func (x *Container) GetResourceVersion() uint64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+func (x *Container) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Container) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Container) GetProjectId() uint32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *Container) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Container) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
 type ContainerExecRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Input:
@@ -603,13 +730,80 @@ func (x *ContainerLogsResponse) GetContent() []byte {
 	return nil
 }
 
+type ContainerEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=bx2cloud.EventType" json:"type,omitempty"`
+	// Position of the event in the stream of container events, used to resume a watch
+	Revision      uint64     `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Container     *Container `protobuf:"bytes,3,opt,name=container,proto3" json:"container,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerEvent) Reset() {
+	*x = ContainerEvent{}
+	mi := &file_container_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerEvent) ProtoMessage() {}
+
+func (x *ContainerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_container_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerEvent.ProtoReflect.Descriptor instead.
+func (*ContainerEvent) Descriptor() ([]byte, []int) {
+	return file_container_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ContainerEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ContainerEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ContainerEvent) GetContainer() *Container {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
 var File_container_proto protoreflect.FileDescriptor
 
 const file_container_proto_rawDesc = "" +
 	"\n" +
-	"\x0fcontainer.proto\x12\bbx2cloud\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"0\n" +
-	"\x1eContainerIdentificationRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"\x99\x01\n" +
+	"\x0fcontainer.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"list.proto\x1a\vwatch.proto\"\x9b\x01\n" +
+	"\x1eContainerIdentificationRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\rH\x00R\x02id\x12\x14\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x12.\n" +
+	"\x10resource_version\x18\x02 \x01(\x04H\x01R\x0fresourceVersion\x88\x01\x01B\f\n" +
+	"\n" +
+	"identifierB\x13\n" +
+	"\x11_resource_version\"\xd9\x02\n" +
 	"\x18ContainerCreationRequest\x12#\n" +
 	"\rsubnetwork_id\x18\x01 \x01(\rR\fsubnetworkId\x12\x14\n" +
 	"\x05image\x18\x02 \x01(\tR\x05image\x12\x1e\n" +
@@ -617,7 +811,13 @@ const file_container_proto_rawDesc = "" +
 	"entrypoint\x18\x03 \x03(\tR\n" +
 	"entrypoint\x12\x10\n" +
 	"\x03cmd\x18\x04 \x03(\tR\x03cmd\x12\x10\n" +
-	"\x03env\x18\x05 \x03(\tR\x03env\"\xe5\x02\n" +
+	"\x03env\x18\x05 \x03(\tR\x03env\x12F\n" +
+	"\x06labels\x18\x06 \x03(\v2..bx2cloud.ContainerCreationRequest.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\a \x01(\tR\x04name\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xff\x04\n" +
 	"\tContainer\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\aR\aaddress\x12#\n" +
@@ -632,7 +832,18 @@ const file_container_proto_rawDesc = "" +
 	"entrypoint\x12\x10\n" +
 	"\x03cmd\x18\n" +
 	" \x03(\tR\x03cmd\x12\x10\n" +
-	"\x03env\x18\v \x03(\tR\x03env\"\x8f\x01\n" +
+	"\x03env\x18\v \x03(\tR\x03env\x12)\n" +
+	"\x10resource_version\x18\f \x01(\x04R\x0fresourceVersion\x127\n" +
+	"\x06labels\x18\r \x03(\v2\x1f.bx2cloud.Container.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\x0e \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x0f \x01(\rR\tprojectId\x12'\n" +
+	"\x0fidempotency_key\x18\x10 \x01(\tR\x0eidempotencyKey\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x11 \x01(\tR\tcreatedBy\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8f\x01\n" +
 	"\x14ContainerExecRequest\x12V\n" +
 	"\x0einitialization\x18\x01 \x01(\v2,.bx2cloud.ContainerExecInitializationRequestH\x00R\x0einitialization\x12\x16\n" +
 	"\x05stdin\x18\x02 \x01(\fH\x00R\x05stdinB\a\n" +
@@ -652,16 +863,21 @@ const file_container_proto_rawDesc = "" +
 	"\x0eidentification\x18\x01 \x01(\v2(.bx2cloud.ContainerIdentificationRequestR\x0eidentification\x12\x16\n" +
 	"\x06follow\x18\x02 \x01(\bR\x06follow\"1\n" +
 	"\x15ContainerLogsResponse\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\fR\acontent2\xc5\x04\n" +
-	"\x10ContainerService\x12D\n" +
-	"\x03Get\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\x125\n" +
-	"\x04List\x12\x16.google.protobuf.Empty\x1a\x13.bx2cloud.Container0\x01\x12A\n" +
-	"\x06Create\x12\".bx2cloud.ContainerCreationRequest\x1a\x13.bx2cloud.Container\x12J\n" +
-	"\x06Delete\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x16.google.protobuf.Empty\x12K\n" +
-	"\x04Exec\x12\x1e.bx2cloud.ContainerExecRequest\x1a\x1f.bx2cloud.ContainerExecResponse(\x010\x01\x12F\n" +
-	"\x05Start\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\x12E\n" +
-	"\x04Stop\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\x12I\n" +
-	"\x04Logs\x12\x1e.bx2cloud.ContainerLogsRequest\x1a\x1f.bx2cloud.ContainerLogsResponse0\x01B,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+	"\acontent\x18\x01 \x01(\fR\acontent\"\x88\x01\n" +
+	"\x0eContainerEvent\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.bx2cloud.EventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x121\n" +
+	"\tcontainer\x18\x03 \x01(\v2\x13.bx2cloud.ContainerR\tcontainer2\xcb\b\n" +
+	"\x10ContainerService\x12\x82\x01\n" +
+	"\x03Get\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\"<\x82\xd3\xe4\x93\x026Z\x1f\x12\x1d/v1/containers/by-name/{name}\x12\x13/v1/containers/{id}\x12L\n" +
+	"\x04List\x12\x15.bx2cloud.ListRequest\x1a\x13.bx2cloud.Container\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/containers0\x01\x12Y\n" +
+	"\x05Watch\x12\x16.bx2cloud.WatchRequest\x1a\x18.bx2cloud.ContainerEvent\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/containers:watch0\x01\x12\\\n" +
+	"\x06Create\x12\".bx2cloud.ContainerCreationRequest\x1a\x13.bx2cloud.Container\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/containers\x12\x88\x01\n" +
+	"\x06Delete\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x16.google.protobuf.Empty\"<\x82\xd3\xe4\x93\x026Z\x1f*\x1d/v1/containers/by-name/{name}*\x13/v1/containers/{id}\x12K\n" +
+	"\x04Exec\x12\x1e.bx2cloud.ContainerExecRequest\x1a\x1f.bx2cloud.ContainerExecResponse(\x010\x01\x12\x90\x01\n" +
+	"\x05Start\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\"H\x82\xd3\xe4\x93\x02BZ%\"#/v1/containers/by-name/{name}:start\"\x19/v1/containers/{id}:start\x12\x8d\x01\n" +
+	"\x04Stop\x12(.bx2cloud.ContainerIdentificationRequest\x1a\x13.bx2cloud.Container\"F\x82\xd3\xe4\x93\x02@Z$\"\"/v1/containers/by-name/{name}:stop\"\x18/v1/containers/{id}:stop\x12\xaf\x01\n" +
+	"\x04Logs\x12\x1e.bx2cloud.ContainerLogsRequest\x1a\x1f.bx2cloud.ContainerLogsResponse\"d\x82\xd3\xe4\x93\x02^Z3\x121/v1/containers/by-name/{identification.name}/logs\x12'/v1/containers/{identification.id}/logs0\x01B,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
 
 var (
 	file_container_proto_rawDescOnce sync.Once
@@ -675,7 +891,7 @@ func file_container_proto_rawDescGZIP() []byte {
 	return file_container_proto_rawDescData
 }
 
-var file_container_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_container_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_container_proto_goTypes = []any{
 	(*ContainerIdentificationRequest)(nil),     // 0: bx2cloud.ContainerIdentificationRequest
 	(*ContainerCreationRequest)(nil),           // 1: bx2cloud.ContainerCreationRequest
@@ -685,36 +901,48 @@ var file_container_proto_goTypes = []any{
 	(*ContainerExecResponse)(nil),              // 5: bx2cloud.ContainerExecResponse
 	(*ContainerLogsRequest)(nil),               // 6: bx2cloud.ContainerLogsRequest
 	(*ContainerLogsResponse)(nil),              // 7: bx2cloud.ContainerLogsResponse
-	(*timestamppb.Timestamp)(nil),              // 8: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                      // 9: google.protobuf.Empty
+	(*ContainerEvent)(nil),                     // 8: bx2cloud.ContainerEvent
+	nil,                                        // 9: bx2cloud.ContainerCreationRequest.LabelsEntry
+	nil,                                        // 10: bx2cloud.Container.LabelsEntry
+	(*timestamppb.Timestamp)(nil),              // 11: google.protobuf.Timestamp
+	(EventType)(0),                             // 12: bx2cloud.EventType
+	(*ListRequest)(nil),                        // 13: bx2cloud.ListRequest
+	(*WatchRequest)(nil),                       // 14: bx2cloud.WatchRequest
+	(*emptypb.Empty)(nil),                      // 15: google.protobuf.Empty
 }
 var file_container_proto_depIdxs = []int32{
-	8,  // 0: bx2cloud.Container.createdAt:type_name -> google.protobuf.Timestamp
-	8,  // 1: bx2cloud.Container.startedAt:type_name -> google.protobuf.Timestamp
-	4,  // 2: bx2cloud.ContainerExecRequest.initialization:type_name -> bx2cloud.ContainerExecInitializationRequest
-	0,  // 3: bx2cloud.ContainerExecInitializationRequest.identification:type_name -> bx2cloud.ContainerIdentificationRequest
-	0,  // 4: bx2cloud.ContainerLogsRequest.identification:type_name -> bx2cloud.ContainerIdentificationRequest
-	0,  // 5: bx2cloud.ContainerService.Get:input_type -> bx2cloud.ContainerIdentificationRequest
-	9,  // 6: bx2cloud.ContainerService.List:input_type -> google.protobuf.Empty
-	1,  // 7: bx2cloud.ContainerService.Create:input_type -> bx2cloud.ContainerCreationRequest
-	0,  // 8: bx2cloud.ContainerService.Delete:input_type -> bx2cloud.ContainerIdentificationRequest
-	3,  // 9: bx2cloud.ContainerService.Exec:input_type -> bx2cloud.ContainerExecRequest
-	0,  // 10: bx2cloud.ContainerService.Start:input_type -> bx2cloud.ContainerIdentificationRequest
-	0,  // 11: bx2cloud.ContainerService.Stop:input_type -> bx2cloud.ContainerIdentificationRequest
-	6,  // 12: bx2cloud.ContainerService.Logs:input_type -> bx2cloud.ContainerLogsRequest
-	2,  // 13: bx2cloud.ContainerService.Get:output_type -> bx2cloud.Container
-	2,  // 14: bx2cloud.ContainerService.List:output_type -> bx2cloud.Container
-	2,  // 15: bx2cloud.ContainerService.Create:output_type -> bx2cloud.Container
-	9,  // 16: bx2cloud.ContainerService.Delete:output_type -> google.protobuf.Empty
-	5,  // 17: bx2cloud.ContainerService.Exec:output_type -> bx2cloud.ContainerExecResponse
-	2,  // 18: bx2cloud.ContainerService.Start:output_type -> bx2cloud.Container
-	2,  // 19: bx2cloud.ContainerService.Stop:output_type -> bx2cloud.Container
-	7,  // 20: bx2cloud.ContainerService.Logs:output_type -> bx2cloud.ContainerLogsResponse
-	13, // [13:21] is the sub-list for method output_type
-	5,  // [5:13] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	9,  // 0: bx2cloud.ContainerCreationRequest.labels:type_name -> bx2cloud.ContainerCreationRequest.LabelsEntry
+	11, // 1: bx2cloud.Container.createdAt:type_name -> google.protobuf.Timestamp
+	11, // 2: bx2cloud.Container.startedAt:type_name -> google.protobuf.Timestamp
+	10, // 3: bx2cloud.Container.labels:type_name -> bx2cloud.Container.LabelsEntry
+	4,  // 4: bx2cloud.ContainerExecRequest.initialization:type_name -> bx2cloud.ContainerExecInitializationRequest
+	0,  // 5: bx2cloud.ContainerExecInitializationRequest.identification:type_name -> bx2cloud.ContainerIdentificationRequest
+	0,  // 6: bx2cloud.ContainerLogsRequest.identification:type_name -> bx2cloud.ContainerIdentificationRequest
+	12, // 7: bx2cloud.ContainerEvent.type:type_name -> bx2cloud.EventType
+	2,  // 8: bx2cloud.ContainerEvent.container:type_name -> bx2cloud.Container
+	0,  // 9: bx2cloud.ContainerService.Get:input_type -> bx2cloud.ContainerIdentificationRequest
+	13, // 10: bx2cloud.ContainerService.List:input_type -> bx2cloud.ListRequest
+	14, // 11: bx2cloud.ContainerService.Watch:input_type -> bx2cloud.WatchRequest
+	1,  // 12: bx2cloud.ContainerService.Create:input_type -> bx2cloud.ContainerCreationRequest
+	0,  // 13: bx2cloud.ContainerService.Delete:input_type -> bx2cloud.ContainerIdentificationRequest
+	3,  // 14: bx2cloud.ContainerService.Exec:input_type -> bx2cloud.ContainerExecRequest
+	0,  // 15: bx2cloud.ContainerService.Start:input_type -> bx2cloud.ContainerIdentificationRequest
+	0,  // 16: bx2cloud.ContainerService.Stop:input_type -> bx2cloud.ContainerIdentificationRequest
+	6,  // 17: bx2cloud.ContainerService.Logs:input_type -> bx2cloud.ContainerLogsRequest
+	2,  // 18: bx2cloud.ContainerService.Get:output_type -> bx2cloud.Container
+	2,  // 19: bx2cloud.ContainerService.List:output_type -> bx2cloud.Container
+	8,  // 20: bx2cloud.ContainerService.Watch:output_type -> bx2cloud.ContainerEvent
+	2,  // 21: bx2cloud.ContainerService.Create:output_type -> bx2cloud.Container
+	15, // 22: bx2cloud.ContainerService.Delete:output_type -> google.protobuf.Empty
+	5,  // 23: bx2cloud.ContainerService.Exec:output_type -> bx2cloud.ContainerExecResponse
+	2,  // 24: bx2cloud.ContainerService.Start:output_type -> bx2cloud.Container
+	2,  // 25: bx2cloud.ContainerService.Stop:output_type -> bx2cloud.Container
+	7,  // 26: bx2cloud.ContainerService.Logs:output_type -> bx2cloud.ContainerLogsResponse
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_container_proto_init() }
@@ -722,6 +950,12 @@ func file_container_proto_init() {
 	if File_container_proto != nil {
 		return
 	}
+	file_list_proto_init()
+	file_watch_proto_init()
+	file_container_proto_msgTypes[0].OneofWrappers = []any{
+		(*ContainerIdentificationRequest_Id)(nil),
+		(*ContainerIdentificationRequest_Name)(nil),
+	}
 	file_container_proto_msgTypes[3].OneofWrappers = []any{
 		(*ContainerExecRequest_Initialization)(nil),
 		(*ContainerExecRequest_Stdin)(nil),
@@ -737,7 +971,7 @@ func file_container_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_container_proto_rawDesc), len(file_container_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
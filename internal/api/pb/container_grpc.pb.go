@@ -22,6 +22,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	ContainerService_Get_FullMethodName    = "/bx2cloud.ContainerService/Get"
 	ContainerService_List_FullMethodName   = "/bx2cloud.ContainerService/List"
+	ContainerService_Watch_FullMethodName  = "/bx2cloud.ContainerService/Watch"
 	ContainerService_Create_FullMethodName = "/bx2cloud.ContainerService/Create"
 	ContainerService_Delete_FullMethodName = "/bx2cloud.ContainerService/Delete"
 	ContainerService_Exec_FullMethodName   = "/bx2cloud.ContainerService/Exec"
@@ -35,9 +36,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ContainerServiceClient interface {
 	Get(ctx context.Context, in *ContainerIdentificationRequest, opts ...grpc.CallOption) (*Container, error)
-	List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Container], error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Container], error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerEvent], error)
 	Create(ctx context.Context, in *ContainerCreationRequest, opts ...grpc.CallOption) (*Container, error)
 	Delete(ctx context.Context, in *ContainerIdentificationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Not available over the HTTP gateway
 	Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ContainerExecRequest, ContainerExecResponse], error)
 	Start(ctx context.Context, in *ContainerIdentificationRequest, opts ...grpc.CallOption) (*Container, error)
 	Stop(ctx context.Context, in *ContainerIdentificationRequest, opts ...grpc.CallOption) (*Container, error)
@@ -62,13 +65,13 @@ func (c *containerServiceClient) Get(ctx context.Context, in *ContainerIdentific
 	return out, nil
 }
 
-func (c *containerServiceClient) List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Container], error) {
+func (c *containerServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Container], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[0], ContainerService_List_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[emptypb.Empty, Container]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ListRequest, Container]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -81,6 +84,25 @@ func (c *containerServiceClient) List(ctx context.Context, in *emptypb.Empty, op
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ContainerService_ListClient = grpc.ServerStreamingClient[Container]
 
+func (c *containerServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[1], ContainerService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, ContainerEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerService_WatchClient = grpc.ServerStreamingClient[ContainerEvent]
+
 func (c *containerServiceClient) Create(ctx context.Context, in *ContainerCreationRequest, opts ...grpc.CallOption) (*Container, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Container)
@@ -103,7 +125,7 @@ func (c *containerServiceClient) Delete(ctx context.Context, in *ContainerIdenti
 
 func (c *containerServiceClient) Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ContainerExecRequest, ContainerExecResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[1], ContainerService_Exec_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[2], ContainerService_Exec_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +158,7 @@ func (c *containerServiceClient) Stop(ctx context.Context, in *ContainerIdentifi
 
 func (c *containerServiceClient) Logs(ctx context.Context, in *ContainerLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerLogsResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[2], ContainerService_Logs_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerService_ServiceDesc.Streams[3], ContainerService_Logs_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -158,9 +180,11 @@ type ContainerService_LogsClient = grpc.ServerStreamingClient[ContainerLogsRespo
 // for forward compatibility.
 type ContainerServiceServer interface {
 	Get(context.Context, *ContainerIdentificationRequest) (*Container, error)
-	List(*emptypb.Empty, grpc.ServerStreamingServer[Container]) error
+	List(*ListRequest, grpc.ServerStreamingServer[Container]) error
+	Watch(*WatchRequest, grpc.ServerStreamingServer[ContainerEvent]) error
 	Create(context.Context, *ContainerCreationRequest) (*Container, error)
 	Delete(context.Context, *ContainerIdentificationRequest) (*emptypb.Empty, error)
+	// Not available over the HTTP gateway
 	Exec(grpc.BidiStreamingServer[ContainerExecRequest, ContainerExecResponse]) error
 	Start(context.Context, *ContainerIdentificationRequest) (*Container, error)
 	Stop(context.Context, *ContainerIdentificationRequest) (*Container, error)
@@ -178,9 +202,12 @@ type UnimplementedContainerServiceServer struct{}
 func (UnimplementedContainerServiceServer) Get(context.Context, *ContainerIdentificationRequest) (*Container, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (UnimplementedContainerServiceServer) List(*emptypb.Empty, grpc.ServerStreamingServer[Container]) error {
+func (UnimplementedContainerServiceServer) List(*ListRequest, grpc.ServerStreamingServer[Container]) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedContainerServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[ContainerEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedContainerServiceServer) Create(context.Context, *ContainerCreationRequest) (*Container, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
@@ -239,16 +266,27 @@ func _ContainerService_Get_Handler(srv interface{}, ctx context.Context, dec fun
 }
 
 func _ContainerService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(emptypb.Empty)
+	m := new(ListRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ContainerServiceServer).List(m, &grpc.GenericServerStream[emptypb.Empty, Container]{ServerStream: stream})
+	return srv.(ContainerServiceServer).List(m, &grpc.GenericServerStream[ListRequest, Container]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ContainerService_ListServer = grpc.ServerStreamingServer[Container]
 
+func _ContainerService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainerServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, ContainerEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerService_WatchServer = grpc.ServerStreamingServer[ContainerEvent]
+
 func _ContainerService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ContainerCreationRequest)
 	if err := dec(in); err != nil {
@@ -373,6 +411,11 @@ var ContainerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ContainerService_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _ContainerService_Watch_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Exec",
 			Handler:       _ContainerService_Exec_Handler,
@@ -7,8 +7,10 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
@@ -23,8 +25,19 @@ const (
 )
 
 type IntrospectionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Container IP allocations that could not be restored when the API started
+	IpamIssues []*IpamIssue `protobuf:"bytes,2,rep,name=ipam_issues,json=ipamIssues,proto3" json:"ipam_issues,omitempty"`
+	Build      *BuildInfo   `protobuf:"bytes,3,opt,name=build,proto3" json:"build,omitempty"`
+	// How long the API has been running
+	Uptime *durationpb.Duration `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Host   *HostInfo            `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
+	// Binaries and kernel features that the API relies on
+	Capabilities []*Capability   `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Resources    *ResourceCounts `protobuf:"bytes,7,opt,name=resources,proto3" json:"resources,omitempty"`
+	// Optional features turned on in the API's configuration, e.g. "tls" or "metrics"
+	Features      []string `protobuf:"bytes,8,rep,name=features,proto3" json:"features,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,15 +79,561 @@ func (x *IntrospectionResponse) GetVersion() string {
 	return ""
 }
 
+func (x *IntrospectionResponse) GetIpamIssues() []*IpamIssue {
+	if x != nil {
+		return x.IpamIssues
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetBuild() *BuildInfo {
+	if x != nil {
+		return x.Build
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetHost() *HostInfo {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetCapabilities() []*Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetResources() *ResourceCounts {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *IntrospectionResponse) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type IpamIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContainerId   uint32                 `protobuf:"varint,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	SubnetworkId  uint32                 `protobuf:"varint,2,opt,name=subnetwork_id,json=subnetworkId,proto3" json:"subnetwork_id,omitempty"`
+	Address       uint32                 `protobuf:"fixed32,3,opt,name=address,proto3" json:"address,omitempty"`
+	PrefixLength  uint32                 `protobuf:"fixed32,4,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IpamIssue) Reset() {
+	*x = IpamIssue{}
+	mi := &file_introspection_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IpamIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IpamIssue) ProtoMessage() {}
+
+func (x *IpamIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IpamIssue.ProtoReflect.Descriptor instead.
+func (*IpamIssue) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IpamIssue) GetContainerId() uint32 {
+	if x != nil {
+		return x.ContainerId
+	}
+	return 0
+}
+
+func (x *IpamIssue) GetSubnetworkId() uint32 {
+	if x != nil {
+		return x.SubnetworkId
+	}
+	return 0
+}
+
+func (x *IpamIssue) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *IpamIssue) GetPrefixLength() uint32 {
+	if x != nil {
+		return x.PrefixLength
+	}
+	return 0
+}
+
+func (x *IpamIssue) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type BuildInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// VCS revision the API was built from, empty if unknown
+	Commit string `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	// RFC 3339 time of the commit or the build, empty if unknown
+	Date          string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	GoVersion     string `protobuf:"bytes,3,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildInfo) Reset() {
+	*x = BuildInfo{}
+	mi := &file_introspection_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildInfo) ProtoMessage() {}
+
+func (x *BuildInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildInfo.ProtoReflect.Descriptor instead.
+func (*BuildInfo) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BuildInfo) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+type HostInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KernelVersion string                 `protobuf:"bytes,1,opt,name=kernel_version,json=kernelVersion,proto3" json:"kernel_version,omitempty"`
+	// 1 or 2, 0 if it could not be determined
+	CgroupVersion uint32 `protobuf:"varint,2,opt,name=cgroup_version,json=cgroupVersion,proto3" json:"cgroup_version,omitempty"`
+	// "nf_tables" or "legacy", empty if iptables could not be run
+	IptablesBackend      string `protobuf:"bytes,3,opt,name=iptables_backend,json=iptablesBackend,proto3" json:"iptables_backend,omitempty"`
+	Cpus                 uint32 `protobuf:"varint,4,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	MemoryTotalBytes     uint64 `protobuf:"varint,5,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	MemoryAvailableBytes uint64 `protobuf:"varint,6,opt,name=memory_available_bytes,json=memoryAvailableBytes,proto3" json:"memory_available_bytes,omitempty"`
+	// Capacity of the file systems that the API keeps its data on
+	DataDirectories []*DataDirectory `protobuf:"bytes,7,rep,name=data_directories,json=dataDirectories,proto3" json:"data_directories,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HostInfo) Reset() {
+	*x = HostInfo{}
+	mi := &file_introspection_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfo) ProtoMessage() {}
+
+func (x *HostInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
+func (*HostInfo) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HostInfo) GetKernelVersion() string {
+	if x != nil {
+		return x.KernelVersion
+	}
+	return ""
+}
+
+func (x *HostInfo) GetCgroupVersion() uint32 {
+	if x != nil {
+		return x.CgroupVersion
+	}
+	return 0
+}
+
+func (x *HostInfo) GetIptablesBackend() string {
+	if x != nil {
+		return x.IptablesBackend
+	}
+	return ""
+}
+
+func (x *HostInfo) GetCpus() uint32 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *HostInfo) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *HostInfo) GetMemoryAvailableBytes() uint64 {
+	if x != nil {
+		return x.MemoryAvailableBytes
+	}
+	return 0
+}
+
+func (x *HostInfo) GetDataDirectories() []*DataDirectory {
+	if x != nil {
+		return x.DataDirectories
+	}
+	return nil
+}
+
+type DataDirectory struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "state", "containers", "images" or "logs"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	TotalBytes    uint64 `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	FreeBytes     uint64 `protobuf:"varint,4,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataDirectory) Reset() {
+	*x = DataDirectory{}
+	mi := &file_introspection_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataDirectory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataDirectory) ProtoMessage() {}
+
+func (x *DataDirectory) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataDirectory.ProtoReflect.Descriptor instead.
+func (*DataDirectory) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DataDirectory) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DataDirectory) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DataDirectory) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *DataDirectory) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+type Capability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. "binary:iptables" or "kernel:bridge"
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Available bool   `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	// The API does not work correctly without required capabilities
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	// How the capability was found, or why it is missing
+	Detail        string `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Capability) Reset() {
+	*x = Capability{}
+	mi := &file_introspection_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capability) ProtoMessage() {}
+
+func (x *Capability) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capability.ProtoReflect.Descriptor instead.
+func (*Capability) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Capability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Capability) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *Capability) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *Capability) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type ResourceCounts struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Projects          uint32                 `protobuf:"varint,1,opt,name=projects,proto3" json:"projects,omitempty"`
+	Networks          uint32                 `protobuf:"varint,2,opt,name=networks,proto3" json:"networks,omitempty"`
+	Subnetworks       uint32                 `protobuf:"varint,3,opt,name=subnetworks,proto3" json:"subnetworks,omitempty"`
+	Containers        uint32                 `protobuf:"varint,4,opt,name=containers,proto3" json:"containers,omitempty"`
+	RunningContainers uint32                 `protobuf:"varint,5,opt,name=running_containers,json=runningContainers,proto3" json:"running_containers,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ResourceCounts) Reset() {
+	*x = ResourceCounts{}
+	mi := &file_introspection_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceCounts) ProtoMessage() {}
+
+func (x *ResourceCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_introspection_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceCounts.ProtoReflect.Descriptor instead.
+func (*ResourceCounts) Descriptor() ([]byte, []int) {
+	return file_introspection_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResourceCounts) GetProjects() uint32 {
+	if x != nil {
+		return x.Projects
+	}
+	return 0
+}
+
+func (x *ResourceCounts) GetNetworks() uint32 {
+	if x != nil {
+		return x.Networks
+	}
+	return 0
+}
+
+func (x *ResourceCounts) GetSubnetworks() uint32 {
+	if x != nil {
+		return x.Subnetworks
+	}
+	return 0
+}
+
+func (x *ResourceCounts) GetContainers() uint32 {
+	if x != nil {
+		return x.Containers
+	}
+	return 0
+}
+
+func (x *ResourceCounts) GetRunningContainers() uint32 {
+	if x != nil {
+		return x.RunningContainers
+	}
+	return 0
+}
+
 var File_introspection_proto protoreflect.FileDescriptor
 
 const file_introspection_proto_rawDesc = "" +
 	"\n" +
-	"\x13introspection.proto\x12\bbx2cloud\x1a\x1bgoogle/protobuf/empty.proto\"1\n" +
+	"\x13introspection.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xfb\x02\n" +
 	"\x15IntrospectionResponse\x12\x18\n" +
-	"\aversion\x18\x01 \x01(\tR\aversion2V\n" +
-	"\x14IntrospectionService\x12>\n" +
-	"\x03Get\x12\x16.google.protobuf.Empty\x1a\x1f.bx2cloud.IntrospectionResponseB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+	"\aversion\x18\x01 \x01(\tR\aversion\x124\n" +
+	"\vipam_issues\x18\x02 \x03(\v2\x13.bx2cloud.IpamIssueR\n" +
+	"ipamIssues\x12)\n" +
+	"\x05build\x18\x03 \x01(\v2\x13.bx2cloud.BuildInfoR\x05build\x121\n" +
+	"\x06uptime\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x06uptime\x12&\n" +
+	"\x04host\x18\x05 \x01(\v2\x12.bx2cloud.HostInfoR\x04host\x128\n" +
+	"\fcapabilities\x18\x06 \x03(\v2\x14.bx2cloud.CapabilityR\fcapabilities\x126\n" +
+	"\tresources\x18\a \x01(\v2\x18.bx2cloud.ResourceCountsR\tresources\x12\x1a\n" +
+	"\bfeatures\x18\b \x03(\tR\bfeatures\"\xb4\x01\n" +
+	"\tIpamIssue\x12!\n" +
+	"\fcontainer_id\x18\x01 \x01(\rR\vcontainerId\x12#\n" +
+	"\rsubnetwork_id\x18\x02 \x01(\rR\fsubnetworkId\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\aR\aaddress\x12#\n" +
+	"\rprefix_length\x18\x04 \x01(\aR\fprefixLength\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"V\n" +
+	"\tBuildInfo\x12\x16\n" +
+	"\x06commit\x18\x01 \x01(\tR\x06commit\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x03 \x01(\tR\tgoVersion\"\xbf\x02\n" +
+	"\bHostInfo\x12%\n" +
+	"\x0ekernel_version\x18\x01 \x01(\tR\rkernelVersion\x12%\n" +
+	"\x0ecgroup_version\x18\x02 \x01(\rR\rcgroupVersion\x12)\n" +
+	"\x10iptables_backend\x18\x03 \x01(\tR\x0fiptablesBackend\x12\x12\n" +
+	"\x04cpus\x18\x04 \x01(\rR\x04cpus\x12,\n" +
+	"\x12memory_total_bytes\x18\x05 \x01(\x04R\x10memoryTotalBytes\x124\n" +
+	"\x16memory_available_bytes\x18\x06 \x01(\x04R\x14memoryAvailableBytes\x12B\n" +
+	"\x10data_directories\x18\a \x03(\v2\x17.bx2cloud.DataDirectoryR\x0fdataDirectories\"w\n" +
+	"\rDataDirectory\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1f\n" +
+	"\vtotal_bytes\x18\x03 \x01(\x04R\n" +
+	"totalBytes\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x04 \x01(\x04R\tfreeBytes\"r\n" +
+	"\n" +
+	"Capability\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\bR\tavailable\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\"\xb9\x01\n" +
+	"\x0eResourceCounts\x12\x1a\n" +
+	"\bprojects\x18\x01 \x01(\rR\bprojects\x12\x1a\n" +
+	"\bnetworks\x18\x02 \x01(\rR\bnetworks\x12 \n" +
+	"\vsubnetworks\x18\x03 \x01(\rR\vsubnetworks\x12\x1e\n" +
+	"\n" +
+	"containers\x18\x04 \x01(\rR\n" +
+	"containers\x12-\n" +
+	"\x12running_containers\x18\x05 \x01(\rR\x11runningContainers2q\n" +
+	"\x14IntrospectionService\x12Y\n" +
+	"\x03Get\x12\x16.google.protobuf.Empty\x1a\x1f.bx2cloud.IntrospectionResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/introspectionB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
 
 var (
 	file_introspection_proto_rawDescOnce sync.Once
@@ -88,19 +647,33 @@ func file_introspection_proto_rawDescGZIP() []byte {
 	return file_introspection_proto_rawDescData
 }
 
-var file_introspection_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_introspection_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_introspection_proto_goTypes = []any{
 	(*IntrospectionResponse)(nil), // 0: bx2cloud.IntrospectionResponse
-	(*emptypb.Empty)(nil),         // 1: google.protobuf.Empty
+	(*IpamIssue)(nil),             // 1: bx2cloud.IpamIssue
+	(*BuildInfo)(nil),             // 2: bx2cloud.BuildInfo
+	(*HostInfo)(nil),              // 3: bx2cloud.HostInfo
+	(*DataDirectory)(nil),         // 4: bx2cloud.DataDirectory
+	(*Capability)(nil),            // 5: bx2cloud.Capability
+	(*ResourceCounts)(nil),        // 6: bx2cloud.ResourceCounts
+	(*durationpb.Duration)(nil),   // 7: google.protobuf.Duration
+	(*emptypb.Empty)(nil),         // 8: google.protobuf.Empty
 }
 var file_introspection_proto_depIdxs = []int32{
-	1, // 0: bx2cloud.IntrospectionService.Get:input_type -> google.protobuf.Empty
-	0, // 1: bx2cloud.IntrospectionService.Get:output_type -> bx2cloud.IntrospectionResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: bx2cloud.IntrospectionResponse.ipam_issues:type_name -> bx2cloud.IpamIssue
+	2, // 1: bx2cloud.IntrospectionResponse.build:type_name -> bx2cloud.BuildInfo
+	7, // 2: bx2cloud.IntrospectionResponse.uptime:type_name -> google.protobuf.Duration
+	3, // 3: bx2cloud.IntrospectionResponse.host:type_name -> bx2cloud.HostInfo
+	5, // 4: bx2cloud.IntrospectionResponse.capabilities:type_name -> bx2cloud.Capability
+	6, // 5: bx2cloud.IntrospectionResponse.resources:type_name -> bx2cloud.ResourceCounts
+	4, // 6: bx2cloud.HostInfo.data_directories:type_name -> bx2cloud.DataDirectory
+	8, // 7: bx2cloud.IntrospectionService.Get:input_type -> google.protobuf.Empty
+	0, // 8: bx2cloud.IntrospectionService.Get:output_type -> bx2cloud.IntrospectionResponse
+	8, // [8:9] is the sub-list for method output_type
+	7, // [7:8] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_introspection_proto_init() }
@@ -114,7 +687,7 @@ func file_introspection_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_introspection_proto_rawDesc), len(file_introspection_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
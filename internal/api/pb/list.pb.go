@@ -0,0 +1,200 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: list.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The token of the next page is returned in the "next-page-token" trailer, it is empty after the last page
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only resources whose labels match are listed, e.g. "env=prod,team in (payments,billing),!deprecated"
+	LabelSelector string `protobuf:"bytes,1,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// Maximum number of resources to list, 0 lists all of them
+	PageSize uint32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Continues a previous List with the same filters and ordering after the last resource it listed, so resources created or deleted in between do not shift the page
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Comma separated fields with an optional "desc" suffix, e.g. "name, created_at desc". Defaults to "id"
+	OrderBy string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Only applies to subnetworks and containers
+	NetworkId *uint32 `protobuf:"varint,5,opt,name=network_id,json=networkId,proto3,oneof" json:"network_id,omitempty"`
+	// Only applies to containers
+	SubnetworkId *uint32 `protobuf:"varint,6,opt,name=subnetwork_id,json=subnetworkId,proto3,oneof" json:"subnetwork_id,omitempty"`
+	// Only applies to containers, e.g. "running" or "stopped"
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// Only applies to containers, e.g. "nginx:latest"
+	Image         string `protobuf:"bytes,8,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_list_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_list_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_list_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *ListRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListRequest) GetNetworkId() uint32 {
+	if x != nil && x.NetworkId != nil {
+		return *x.NetworkId
+	}
+	return 0
+}
+
+func (x *ListRequest) GetSubnetworkId() uint32 {
+	if x != nil && x.SubnetworkId != nil {
+		return *x.SubnetworkId
+	}
+	return 0
+}
+
+func (x *ListRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+var File_list_proto protoreflect.FileDescriptor
+
+const file_list_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"list.proto\x12\bbx2cloud\"\xa8\x02\n" +
+	"\vListRequest\x12%\n" +
+	"\x0elabel_selector\x18\x01 \x01(\tR\rlabelSelector\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12\"\n" +
+	"\n" +
+	"network_id\x18\x05 \x01(\rH\x00R\tnetworkId\x88\x01\x01\x12(\n" +
+	"\rsubnetwork_id\x18\x06 \x01(\rH\x01R\fsubnetworkId\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x14\n" +
+	"\x05image\x18\b \x01(\tR\x05imageB\r\n" +
+	"\v_network_idB\x10\n" +
+	"\x0e_subnetwork_idB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+
+var (
+	file_list_proto_rawDescOnce sync.Once
+	file_list_proto_rawDescData []byte
+)
+
+func file_list_proto_rawDescGZIP() []byte {
+	file_list_proto_rawDescOnce.Do(func() {
+		file_list_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_list_proto_rawDesc), len(file_list_proto_rawDesc)))
+	})
+	return file_list_proto_rawDescData
+}
+
+var file_list_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_list_proto_goTypes = []any{
+	(*ListRequest)(nil), // 0: bx2cloud.ListRequest
+}
+var file_list_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_list_proto_init() }
+func file_list_proto_init() {
+	if File_list_proto != nil {
+		return
+	}
+	file_list_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_list_proto_rawDesc), len(file_list_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_list_proto_goTypes,
+		DependencyIndexes: file_list_proto_depIdxs,
+		MessageInfos:      file_list_proto_msgTypes,
+	}.Build()
+	File_list_proto = out.File
+	file_list_proto_goTypes = nil
+	file_list_proto_depIdxs = nil
+}
@@ -7,6 +7,7 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -24,10 +25,16 @@ const (
 )
 
 type NetworkIdentificationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*NetworkIdentificationRequest_Id
+	//	*NetworkIdentificationRequest_Name
+	Identifier isNetworkIdentificationRequest_Identifier `protobuf_oneof:"identifier"`
+	// Expected resource version, Update and Delete fail with ABORTED if it does not match
+	ResourceVersion *uint64 `protobuf:"varint,2,opt,name=resource_version,json=resourceVersion,proto3,oneof" json:"resource_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *NetworkIdentificationRequest) Reset() {
@@ -60,16 +67,63 @@ func (*NetworkIdentificationRequest) Descriptor() ([]byte, []int) {
 	return file_network_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *NetworkIdentificationRequest) GetIdentifier() isNetworkIdentificationRequest_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
 func (x *NetworkIdentificationRequest) GetId() uint32 {
 	if x != nil {
-		return x.Id
+		if x, ok := x.Identifier.(*NetworkIdentificationRequest_Id); ok {
+			return x.Id
+		}
 	}
 	return 0
 }
 
+func (x *NetworkIdentificationRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*NetworkIdentificationRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *NetworkIdentificationRequest) GetResourceVersion() uint64 {
+	if x != nil && x.ResourceVersion != nil {
+		return *x.ResourceVersion
+	}
+	return 0
+}
+
+type isNetworkIdentificationRequest_Identifier interface {
+	isNetworkIdentificationRequest_Identifier()
+}
+
+type NetworkIdentificationRequest_Id struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type NetworkIdentificationRequest_Name struct {
+	Name string `protobuf:"bytes,3,opt,name=name,proto3,oneof"`
+}
+
+func (*NetworkIdentificationRequest_Id) isNetworkIdentificationRequest_Identifier() {}
+
+func (*NetworkIdentificationRequest_Name) isNetworkIdentificationRequest_Identifier() {}
+
 type NetworkCreationRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	InternetAccess bool                   `protobuf:"varint,1,opt,name=internet_access,json=internetAccess,proto3" json:"internet_access,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Optional, unique among networks
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional, a repeated creation request with the same key returns the network created by the first request instead of creating another one.
+	// Keys are unique within a project and are kept for the retention window configured on the API.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -111,12 +165,34 @@ func (x *NetworkCreationRequest) GetInternetAccess() bool {
 	return false
 }
 
+func (x *NetworkCreationRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *NetworkCreationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkCreationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type NetworkUpdateRequest struct {
 	state          protoimpl.MessageState        `protogen:"open.v1"`
 	Identification *NetworkIdentificationRequest `protobuf:"bytes,1,opt,name=identification,proto3" json:"identification,omitempty"`
-	Update         *NetworkCreationRequest       `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Replaces every field, so a left out name or labels are cleared
+	Update        *NetworkCreationRequest `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *NetworkUpdateRequest) Reset() {
@@ -168,8 +244,18 @@ type Network struct {
 	Id             uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	InternetAccess bool                   `protobuf:"varint,2,opt,name=internet_access,json=internetAccess,proto3" json:"internet_access,omitempty"`
 	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Increases with every change to the network
+	ResourceVersion uint64            `protobuf:"varint,5,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	Labels          map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Name            string            `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	// Project the network belongs to, 0 is the default project
+	ProjectId uint32 `protobuf:"varint,8,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// Key of the request that created the network, if it had one
+	IdempotencyKey string `protobuf:"bytes,9,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Identity of the caller that created the network, empty for anonymous callers
+	CreatedBy     string `protobuf:"bytes,10,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Network) Reset() {
@@ -223,28 +309,160 @@ func (x *Network) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Network) GetResourceVersion() uint64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+func (x *Network) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Network) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Network) GetProjectId() uint32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *Network) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Network) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type NetworkEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=bx2cloud.EventType" json:"type,omitempty"`
+	// Position of the event in the stream of network events, used to resume a watch
+	Revision      uint64   `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Network       *Network `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEvent) Reset() {
+	*x = NetworkEvent{}
+	mi := &file_network_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEvent) ProtoMessage() {}
+
+func (x *NetworkEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_network_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEvent.ProtoReflect.Descriptor instead.
+func (*NetworkEvent) Descriptor() ([]byte, []int) {
+	return file_network_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NetworkEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *NetworkEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *NetworkEvent) GetNetwork() *Network {
+	if x != nil {
+		return x.Network
+	}
+	return nil
+}
+
 var File_network_proto protoreflect.FileDescriptor
 
 const file_network_proto_rawDesc = "" +
 	"\n" +
-	"\rnetwork.proto\x12\bbx2cloud\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\".\n" +
-	"\x1cNetworkIdentificationRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"A\n" +
+	"\rnetwork.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"list.proto\x1a\vwatch.proto\"\x99\x01\n" +
+	"\x1cNetworkIdentificationRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\rH\x00R\x02id\x12\x14\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x12.\n" +
+	"\x10resource_version\x18\x02 \x01(\x04H\x01R\x0fresourceVersion\x88\x01\x01B\f\n" +
+	"\n" +
+	"identifierB\x13\n" +
+	"\x11_resource_version\"\xff\x01\n" +
 	"\x16NetworkCreationRequest\x12'\n" +
-	"\x0finternet_access\x18\x01 \x01(\bR\x0einternetAccess\"\xa0\x01\n" +
+	"\x0finternet_access\x18\x01 \x01(\bR\x0einternetAccess\x12D\n" +
+	"\x06labels\x18\x02 \x03(\v2,.bx2cloud.NetworkCreationRequest.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa0\x01\n" +
 	"\x14NetworkUpdateRequest\x12N\n" +
 	"\x0eidentification\x18\x01 \x01(\v2&.bx2cloud.NetworkIdentificationRequestR\x0eidentification\x128\n" +
-	"\x06update\x18\x02 \x01(\v2 .bx2cloud.NetworkCreationRequestR\x06update\"|\n" +
+	"\x06update\x18\x02 \x01(\v2 .bx2cloud.NetworkCreationRequestR\x06update\"\x94\x03\n" +
 	"\aNetwork\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12'\n" +
 	"\x0finternet_access\x18\x02 \x01(\bR\x0einternetAccess\x128\n" +
-	"\tcreatedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xcd\x02\n" +
-	"\x0eNetworkService\x12@\n" +
-	"\x03Get\x12&.bx2cloud.NetworkIdentificationRequest\x1a\x11.bx2cloud.Network\x123\n" +
-	"\x04List\x12\x16.google.protobuf.Empty\x1a\x11.bx2cloud.Network0\x01\x12=\n" +
-	"\x06Create\x12 .bx2cloud.NetworkCreationRequest\x1a\x11.bx2cloud.Network\x12;\n" +
-	"\x06Update\x12\x1e.bx2cloud.NetworkUpdateRequest\x1a\x11.bx2cloud.Network\x12H\n" +
-	"\x06Delete\x12&.bx2cloud.NetworkIdentificationRequest\x1a\x16.google.protobuf.EmptyB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+	"\tcreatedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12)\n" +
+	"\x10resource_version\x18\x05 \x01(\x04R\x0fresourceVersion\x125\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1d.bx2cloud.Network.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\a \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\b \x01(\rR\tprojectId\x12'\n" +
+	"\x0fidempotency_key\x18\t \x01(\tR\x0eidempotencyKey\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\n" +
+	" \x01(\tR\tcreatedBy\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x01\n" +
+	"\fNetworkEvent\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.bx2cloud.EventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12+\n" +
+	"\anetwork\x18\x03 \x01(\v2\x11.bx2cloud.NetworkR\anetwork2\xb0\x05\n" +
+	"\x0eNetworkService\x12z\n" +
+	"\x03Get\x12&.bx2cloud.NetworkIdentificationRequest\x1a\x11.bx2cloud.Network\"8\x82\xd3\xe4\x93\x022Z\x1d\x12\x1b/v1/networks/by-name/{name}\x12\x11/v1/networks/{id}\x12H\n" +
+	"\x04List\x12\x15.bx2cloud.ListRequest\x1a\x11.bx2cloud.Network\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/networks0\x01\x12U\n" +
+	"\x05Watch\x12\x16.bx2cloud.WatchRequest\x1a\x16.bx2cloud.NetworkEvent\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/networks:watch0\x01\x12V\n" +
+	"\x06Create\x12 .bx2cloud.NetworkCreationRequest\x1a\x11.bx2cloud.Network\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/networks\x12\xa3\x01\n" +
+	"\x06Update\x12\x1e.bx2cloud.NetworkUpdateRequest\x1a\x11.bx2cloud.Network\"f\x82\xd3\xe4\x93\x02`:\x06updateZ4:\x06update\x1a*/v1/networks/by-name/{identification.name}\x1a /v1/networks/{identification.id}\x12\x82\x01\n" +
+	"\x06Delete\x12&.bx2cloud.NetworkIdentificationRequest\x1a\x16.google.protobuf.Empty\"8\x82\xd3\xe4\x93\x022Z\x1d*\x1b/v1/networks/by-name/{name}*\x11/v1/networks/{id}B,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
 
 var (
 	file_network_proto_rawDescOnce sync.Once
@@ -258,34 +476,46 @@ func file_network_proto_rawDescGZIP() []byte {
 	return file_network_proto_rawDescData
 }
 
-var file_network_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_network_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_network_proto_goTypes = []any{
 	(*NetworkIdentificationRequest)(nil), // 0: bx2cloud.NetworkIdentificationRequest
 	(*NetworkCreationRequest)(nil),       // 1: bx2cloud.NetworkCreationRequest
 	(*NetworkUpdateRequest)(nil),         // 2: bx2cloud.NetworkUpdateRequest
 	(*Network)(nil),                      // 3: bx2cloud.Network
-	(*timestamppb.Timestamp)(nil),        // 4: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                // 5: google.protobuf.Empty
+	(*NetworkEvent)(nil),                 // 4: bx2cloud.NetworkEvent
+	nil,                                  // 5: bx2cloud.NetworkCreationRequest.LabelsEntry
+	nil,                                  // 6: bx2cloud.Network.LabelsEntry
+	(*timestamppb.Timestamp)(nil),        // 7: google.protobuf.Timestamp
+	(EventType)(0),                       // 8: bx2cloud.EventType
+	(*ListRequest)(nil),                  // 9: bx2cloud.ListRequest
+	(*WatchRequest)(nil),                 // 10: bx2cloud.WatchRequest
+	(*emptypb.Empty)(nil),                // 11: google.protobuf.Empty
 }
 var file_network_proto_depIdxs = []int32{
-	0, // 0: bx2cloud.NetworkUpdateRequest.identification:type_name -> bx2cloud.NetworkIdentificationRequest
-	1, // 1: bx2cloud.NetworkUpdateRequest.update:type_name -> bx2cloud.NetworkCreationRequest
-	4, // 2: bx2cloud.Network.createdAt:type_name -> google.protobuf.Timestamp
-	0, // 3: bx2cloud.NetworkService.Get:input_type -> bx2cloud.NetworkIdentificationRequest
-	5, // 4: bx2cloud.NetworkService.List:input_type -> google.protobuf.Empty
-	1, // 5: bx2cloud.NetworkService.Create:input_type -> bx2cloud.NetworkCreationRequest
-	2, // 6: bx2cloud.NetworkService.Update:input_type -> bx2cloud.NetworkUpdateRequest
-	0, // 7: bx2cloud.NetworkService.Delete:input_type -> bx2cloud.NetworkIdentificationRequest
-	3, // 8: bx2cloud.NetworkService.Get:output_type -> bx2cloud.Network
-	3, // 9: bx2cloud.NetworkService.List:output_type -> bx2cloud.Network
-	3, // 10: bx2cloud.NetworkService.Create:output_type -> bx2cloud.Network
-	3, // 11: bx2cloud.NetworkService.Update:output_type -> bx2cloud.Network
-	5, // 12: bx2cloud.NetworkService.Delete:output_type -> google.protobuf.Empty
-	8, // [8:13] is the sub-list for method output_type
-	3, // [3:8] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5,  // 0: bx2cloud.NetworkCreationRequest.labels:type_name -> bx2cloud.NetworkCreationRequest.LabelsEntry
+	0,  // 1: bx2cloud.NetworkUpdateRequest.identification:type_name -> bx2cloud.NetworkIdentificationRequest
+	1,  // 2: bx2cloud.NetworkUpdateRequest.update:type_name -> bx2cloud.NetworkCreationRequest
+	7,  // 3: bx2cloud.Network.createdAt:type_name -> google.protobuf.Timestamp
+	6,  // 4: bx2cloud.Network.labels:type_name -> bx2cloud.Network.LabelsEntry
+	8,  // 5: bx2cloud.NetworkEvent.type:type_name -> bx2cloud.EventType
+	3,  // 6: bx2cloud.NetworkEvent.network:type_name -> bx2cloud.Network
+	0,  // 7: bx2cloud.NetworkService.Get:input_type -> bx2cloud.NetworkIdentificationRequest
+	9,  // 8: bx2cloud.NetworkService.List:input_type -> bx2cloud.ListRequest
+	10, // 9: bx2cloud.NetworkService.Watch:input_type -> bx2cloud.WatchRequest
+	1,  // 10: bx2cloud.NetworkService.Create:input_type -> bx2cloud.NetworkCreationRequest
+	2,  // 11: bx2cloud.NetworkService.Update:input_type -> bx2cloud.NetworkUpdateRequest
+	0,  // 12: bx2cloud.NetworkService.Delete:input_type -> bx2cloud.NetworkIdentificationRequest
+	3,  // 13: bx2cloud.NetworkService.Get:output_type -> bx2cloud.Network
+	3,  // 14: bx2cloud.NetworkService.List:output_type -> bx2cloud.Network
+	4,  // 15: bx2cloud.NetworkService.Watch:output_type -> bx2cloud.NetworkEvent
+	3,  // 16: bx2cloud.NetworkService.Create:output_type -> bx2cloud.Network
+	3,  // 17: bx2cloud.NetworkService.Update:output_type -> bx2cloud.Network
+	11, // 18: bx2cloud.NetworkService.Delete:output_type -> google.protobuf.Empty
+	13, // [13:19] is the sub-list for method output_type
+	7,  // [7:13] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_network_proto_init() }
@@ -293,13 +523,19 @@ func file_network_proto_init() {
 	if File_network_proto != nil {
 		return
 	}
+	file_list_proto_init()
+	file_watch_proto_init()
+	file_network_proto_msgTypes[0].OneofWrappers = []any{
+		(*NetworkIdentificationRequest_Id)(nil),
+		(*NetworkIdentificationRequest_Name)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_network_proto_rawDesc), len(file_network_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	NetworkService_Get_FullMethodName    = "/bx2cloud.NetworkService/Get"
 	NetworkService_List_FullMethodName   = "/bx2cloud.NetworkService/List"
+	NetworkService_Watch_FullMethodName  = "/bx2cloud.NetworkService/Watch"
 	NetworkService_Create_FullMethodName = "/bx2cloud.NetworkService/Create"
 	NetworkService_Update_FullMethodName = "/bx2cloud.NetworkService/Update"
 	NetworkService_Delete_FullMethodName = "/bx2cloud.NetworkService/Delete"
@@ -32,7 +33,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type NetworkServiceClient interface {
 	Get(ctx context.Context, in *NetworkIdentificationRequest, opts ...grpc.CallOption) (*Network, error)
-	List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Network], error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Network], error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NetworkEvent], error)
 	Create(ctx context.Context, in *NetworkCreationRequest, opts ...grpc.CallOption) (*Network, error)
 	Update(ctx context.Context, in *NetworkUpdateRequest, opts ...grpc.CallOption) (*Network, error)
 	Delete(ctx context.Context, in *NetworkIdentificationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -56,13 +58,13 @@ func (c *networkServiceClient) Get(ctx context.Context, in *NetworkIdentificatio
 	return out, nil
 }
 
-func (c *networkServiceClient) List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Network], error) {
+func (c *networkServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Network], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &NetworkService_ServiceDesc.Streams[0], NetworkService_List_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[emptypb.Empty, Network]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ListRequest, Network]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -75,6 +77,25 @@ func (c *networkServiceClient) List(ctx context.Context, in *emptypb.Empty, opts
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NetworkService_ListClient = grpc.ServerStreamingClient[Network]
 
+func (c *networkServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NetworkEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NetworkService_ServiceDesc.Streams[1], NetworkService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, NetworkEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetworkService_WatchClient = grpc.ServerStreamingClient[NetworkEvent]
+
 func (c *networkServiceClient) Create(ctx context.Context, in *NetworkCreationRequest, opts ...grpc.CallOption) (*Network, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Network)
@@ -110,7 +131,8 @@ func (c *networkServiceClient) Delete(ctx context.Context, in *NetworkIdentifica
 // for forward compatibility.
 type NetworkServiceServer interface {
 	Get(context.Context, *NetworkIdentificationRequest) (*Network, error)
-	List(*emptypb.Empty, grpc.ServerStreamingServer[Network]) error
+	List(*ListRequest, grpc.ServerStreamingServer[Network]) error
+	Watch(*WatchRequest, grpc.ServerStreamingServer[NetworkEvent]) error
 	Create(context.Context, *NetworkCreationRequest) (*Network, error)
 	Update(context.Context, *NetworkUpdateRequest) (*Network, error)
 	Delete(context.Context, *NetworkIdentificationRequest) (*emptypb.Empty, error)
@@ -127,9 +149,12 @@ type UnimplementedNetworkServiceServer struct{}
 func (UnimplementedNetworkServiceServer) Get(context.Context, *NetworkIdentificationRequest) (*Network, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (UnimplementedNetworkServiceServer) List(*emptypb.Empty, grpc.ServerStreamingServer[Network]) error {
+func (UnimplementedNetworkServiceServer) List(*ListRequest, grpc.ServerStreamingServer[Network]) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedNetworkServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[NetworkEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedNetworkServiceServer) Create(context.Context, *NetworkCreationRequest) (*Network, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
@@ -179,16 +204,27 @@ func _NetworkService_Get_Handler(srv interface{}, ctx context.Context, dec func(
 }
 
 func _NetworkService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(emptypb.Empty)
+	m := new(ListRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(NetworkServiceServer).List(m, &grpc.GenericServerStream[emptypb.Empty, Network]{ServerStream: stream})
+	return srv.(NetworkServiceServer).List(m, &grpc.GenericServerStream[ListRequest, Network]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NetworkService_ListServer = grpc.ServerStreamingServer[Network]
 
+func _NetworkService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetworkServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, NetworkEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetworkService_WatchServer = grpc.ServerStreamingServer[NetworkEvent]
+
 func _NetworkService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NetworkCreationRequest)
 	if err := dec(in); err != nil {
@@ -273,6 +309,11 @@ var NetworkService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _NetworkService_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _NetworkService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "network.proto",
 }
@@ -0,0 +1,350 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: project.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProjectIdentificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*ProjectIdentificationRequest_Id
+	//	*ProjectIdentificationRequest_Name
+	Identifier isProjectIdentificationRequest_Identifier `protobuf_oneof:"identifier"`
+	// Expected resource version, Delete fails with ABORTED if it does not match
+	ResourceVersion *uint64 `protobuf:"varint,2,opt,name=resource_version,json=resourceVersion,proto3,oneof" json:"resource_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProjectIdentificationRequest) Reset() {
+	*x = ProjectIdentificationRequest{}
+	mi := &file_project_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectIdentificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectIdentificationRequest) ProtoMessage() {}
+
+func (x *ProjectIdentificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectIdentificationRequest.ProtoReflect.Descriptor instead.
+func (*ProjectIdentificationRequest) Descriptor() ([]byte, []int) {
+	return file_project_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProjectIdentificationRequest) GetIdentifier() isProjectIdentificationRequest_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *ProjectIdentificationRequest) GetId() uint32 {
+	if x != nil {
+		if x, ok := x.Identifier.(*ProjectIdentificationRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *ProjectIdentificationRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*ProjectIdentificationRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *ProjectIdentificationRequest) GetResourceVersion() uint64 {
+	if x != nil && x.ResourceVersion != nil {
+		return *x.ResourceVersion
+	}
+	return 0
+}
+
+type isProjectIdentificationRequest_Identifier interface {
+	isProjectIdentificationRequest_Identifier()
+}
+
+type ProjectIdentificationRequest_Id struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type ProjectIdentificationRequest_Name struct {
+	Name string `protobuf:"bytes,3,opt,name=name,proto3,oneof"`
+}
+
+func (*ProjectIdentificationRequest_Id) isProjectIdentificationRequest_Identifier() {}
+
+func (*ProjectIdentificationRequest_Name) isProjectIdentificationRequest_Identifier() {}
+
+type ProjectCreationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required, unique among projects
+	Name          string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectCreationRequest) Reset() {
+	*x = ProjectCreationRequest{}
+	mi := &file_project_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectCreationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectCreationRequest) ProtoMessage() {}
+
+func (x *ProjectCreationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectCreationRequest.ProtoReflect.Descriptor instead.
+func (*ProjectCreationRequest) Descriptor() ([]byte, []int) {
+	return file_project_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProjectCreationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProjectCreationRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type Project struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	ResourceVersion uint64                 `protobuf:"varint,4,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	Labels          map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Project) Reset() {
+	*x = Project{}
+	mi := &file_project_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Project) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Project) ProtoMessage() {}
+
+func (x *Project) ProtoReflect() protoreflect.Message {
+	mi := &file_project_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Project.ProtoReflect.Descriptor instead.
+func (*Project) Descriptor() ([]byte, []int) {
+	return file_project_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Project) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Project) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Project) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Project) GetResourceVersion() uint64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+func (x *Project) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+var File_project_proto protoreflect.FileDescriptor
+
+const file_project_proto_rawDesc = "" +
+	"\n" +
+	"\rproject.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"list.proto\"\x99\x01\n" +
+	"\x1cProjectIdentificationRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\rH\x00R\x02id\x12\x14\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x12.\n" +
+	"\x10resource_version\x18\x02 \x01(\x04H\x01R\x0fresourceVersion\x88\x01\x01B\f\n" +
+	"\n" +
+	"identifierB\x13\n" +
+	"\x11_resource_version\"\xad\x01\n" +
+	"\x16ProjectCreationRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12D\n" +
+	"\x06labels\x18\x02 \x03(\v2,.bx2cloud.ProjectCreationRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x84\x02\n" +
+	"\aProject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x128\n" +
+	"\tcreatedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12)\n" +
+	"\x10resource_version\x18\x04 \x01(\x04R\x0fresourceVersion\x125\n" +
+	"\x06labels\x18\x05 \x03(\v2\x1d.bx2cloud.Project.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xb3\x03\n" +
+	"\x0eProjectService\x12z\n" +
+	"\x03Get\x12&.bx2cloud.ProjectIdentificationRequest\x1a\x11.bx2cloud.Project\"8\x82\xd3\xe4\x93\x022Z\x1d\x12\x1b/v1/projects/by-name/{name}\x12\x11/v1/projects/{id}\x12H\n" +
+	"\x04List\x12\x15.bx2cloud.ListRequest\x1a\x11.bx2cloud.Project\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/projects0\x01\x12V\n" +
+	"\x06Create\x12 .bx2cloud.ProjectCreationRequest\x1a\x11.bx2cloud.Project\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/projects\x12\x82\x01\n" +
+	"\x06Delete\x12&.bx2cloud.ProjectIdentificationRequest\x1a\x16.google.protobuf.Empty\"8\x82\xd3\xe4\x93\x022Z\x1d*\x1b/v1/projects/by-name/{name}*\x11/v1/projects/{id}B,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+
+var (
+	file_project_proto_rawDescOnce sync.Once
+	file_project_proto_rawDescData []byte
+)
+
+func file_project_proto_rawDescGZIP() []byte {
+	file_project_proto_rawDescOnce.Do(func() {
+		file_project_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_project_proto_rawDesc), len(file_project_proto_rawDesc)))
+	})
+	return file_project_proto_rawDescData
+}
+
+var file_project_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_project_proto_goTypes = []any{
+	(*ProjectIdentificationRequest)(nil), // 0: bx2cloud.ProjectIdentificationRequest
+	(*ProjectCreationRequest)(nil),       // 1: bx2cloud.ProjectCreationRequest
+	(*Project)(nil),                      // 2: bx2cloud.Project
+	nil,                                  // 3: bx2cloud.ProjectCreationRequest.LabelsEntry
+	nil,                                  // 4: bx2cloud.Project.LabelsEntry
+	(*timestamppb.Timestamp)(nil),        // 5: google.protobuf.Timestamp
+	(*ListRequest)(nil),                  // 6: bx2cloud.ListRequest
+	(*emptypb.Empty)(nil),                // 7: google.protobuf.Empty
+}
+var file_project_proto_depIdxs = []int32{
+	3, // 0: bx2cloud.ProjectCreationRequest.labels:type_name -> bx2cloud.ProjectCreationRequest.LabelsEntry
+	5, // 1: bx2cloud.Project.createdAt:type_name -> google.protobuf.Timestamp
+	4, // 2: bx2cloud.Project.labels:type_name -> bx2cloud.Project.LabelsEntry
+	0, // 3: bx2cloud.ProjectService.Get:input_type -> bx2cloud.ProjectIdentificationRequest
+	6, // 4: bx2cloud.ProjectService.List:input_type -> bx2cloud.ListRequest
+	1, // 5: bx2cloud.ProjectService.Create:input_type -> bx2cloud.ProjectCreationRequest
+	0, // 6: bx2cloud.ProjectService.Delete:input_type -> bx2cloud.ProjectIdentificationRequest
+	2, // 7: bx2cloud.ProjectService.Get:output_type -> bx2cloud.Project
+	2, // 8: bx2cloud.ProjectService.List:output_type -> bx2cloud.Project
+	2, // 9: bx2cloud.ProjectService.Create:output_type -> bx2cloud.Project
+	7, // 10: bx2cloud.ProjectService.Delete:output_type -> google.protobuf.Empty
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_project_proto_init() }
+func file_project_proto_init() {
+	if File_project_proto != nil {
+		return
+	}
+	file_list_proto_init()
+	file_project_proto_msgTypes[0].OneofWrappers = []any{
+		(*ProjectIdentificationRequest_Id)(nil),
+		(*ProjectIdentificationRequest_Name)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_project_proto_rawDesc), len(file_project_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_project_proto_goTypes,
+		DependencyIndexes: file_project_proto_depIdxs,
+		MessageInfos:      file_project_proto_msgTypes,
+	}.Build()
+	File_project_proto = out.File
+	file_project_proto_goTypes = nil
+	file_project_proto_depIdxs = nil
+}
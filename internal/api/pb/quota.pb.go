@@ -0,0 +1,208 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: quota.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuotaUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty for anonymous callers
+	Identity      string       `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Items         []*QuotaItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuotaUsage) Reset() {
+	*x = QuotaUsage{}
+	mi := &file_quota_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuotaUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaUsage) ProtoMessage() {}
+
+func (x *QuotaUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_quota_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaUsage.ProtoReflect.Descriptor instead.
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return file_quota_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuotaUsage) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetItems() []*QuotaItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type QuotaItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of "networks", "subnetworks", "containers", "running_containers" and "ips"
+	Resource string `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Used     uint32 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	// 0 means unlimited
+	Limit         uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuotaItem) Reset() {
+	*x = QuotaItem{}
+	mi := &file_quota_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuotaItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaItem) ProtoMessage() {}
+
+func (x *QuotaItem) ProtoReflect() protoreflect.Message {
+	mi := &file_quota_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaItem.ProtoReflect.Descriptor instead.
+func (*QuotaItem) Descriptor() ([]byte, []int) {
+	return file_quota_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QuotaItem) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *QuotaItem) GetUsed() uint32 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *QuotaItem) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+var File_quota_proto protoreflect.FileDescriptor
+
+const file_quota_proto_rawDesc = "" +
+	"\n" +
+	"\vquota.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\"S\n" +
+	"\n" +
+	"QuotaUsage\x12\x1a\n" +
+	"\bidentity\x18\x01 \x01(\tR\bidentity\x12)\n" +
+	"\x05items\x18\x02 \x03(\v2\x13.bx2cloud.QuotaItemR\x05items\"Q\n" +
+	"\tQuotaItem\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12\x12\n" +
+	"\x04used\x18\x02 \x01(\rR\x04used\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit2a\n" +
+	"\fQuotaService\x12Q\n" +
+	"\bGetUsage\x12\x16.google.protobuf.Empty\x1a\x14.bx2cloud.QuotaUsage\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/quota/usageB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+
+var (
+	file_quota_proto_rawDescOnce sync.Once
+	file_quota_proto_rawDescData []byte
+)
+
+func file_quota_proto_rawDescGZIP() []byte {
+	file_quota_proto_rawDescOnce.Do(func() {
+		file_quota_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_quota_proto_rawDesc), len(file_quota_proto_rawDesc)))
+	})
+	return file_quota_proto_rawDescData
+}
+
+var file_quota_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_quota_proto_goTypes = []any{
+	(*QuotaUsage)(nil),    // 0: bx2cloud.QuotaUsage
+	(*QuotaItem)(nil),     // 1: bx2cloud.QuotaItem
+	(*emptypb.Empty)(nil), // 2: google.protobuf.Empty
+}
+var file_quota_proto_depIdxs = []int32{
+	1, // 0: bx2cloud.QuotaUsage.items:type_name -> bx2cloud.QuotaItem
+	2, // 1: bx2cloud.QuotaService.GetUsage:input_type -> google.protobuf.Empty
+	0, // 2: bx2cloud.QuotaService.GetUsage:output_type -> bx2cloud.QuotaUsage
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_quota_proto_init() }
+func file_quota_proto_init() {
+	if File_quota_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_quota_proto_rawDesc), len(file_quota_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_quota_proto_goTypes,
+		DependencyIndexes: file_quota_proto_depIdxs,
+		MessageInfos:      file_quota_proto_msgTypes,
+	}.Build()
+	File_quota_proto = out.File
+	file_quota_proto_goTypes = nil
+	file_quota_proto_depIdxs = nil
+}
@@ -7,6 +7,7 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -24,10 +25,16 @@ const (
 )
 
 type SubnetworkIdentificationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*SubnetworkIdentificationRequest_Id
+	//	*SubnetworkIdentificationRequest_Name
+	Identifier isSubnetworkIdentificationRequest_Identifier `protobuf_oneof:"identifier"`
+	// Expected resource version, Update and Delete fail with ABORTED if it does not match
+	ResourceVersion *uint64 `protobuf:"varint,2,opt,name=resource_version,json=resourceVersion,proto3,oneof" json:"resource_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SubnetworkIdentificationRequest) Reset() {
@@ -60,20 +67,67 @@ func (*SubnetworkIdentificationRequest) Descriptor() ([]byte, []int) {
 	return file_subnetwork_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *SubnetworkIdentificationRequest) GetIdentifier() isSubnetworkIdentificationRequest_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
 func (x *SubnetworkIdentificationRequest) GetId() uint32 {
 	if x != nil {
-		return x.Id
+		if x, ok := x.Identifier.(*SubnetworkIdentificationRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *SubnetworkIdentificationRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*SubnetworkIdentificationRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *SubnetworkIdentificationRequest) GetResourceVersion() uint64 {
+	if x != nil && x.ResourceVersion != nil {
+		return *x.ResourceVersion
 	}
 	return 0
 }
 
+type isSubnetworkIdentificationRequest_Identifier interface {
+	isSubnetworkIdentificationRequest_Identifier()
+}
+
+type SubnetworkIdentificationRequest_Id struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type SubnetworkIdentificationRequest_Name struct {
+	Name string `protobuf:"bytes,3,opt,name=name,proto3,oneof"`
+}
+
+func (*SubnetworkIdentificationRequest_Id) isSubnetworkIdentificationRequest_Identifier() {}
+
+func (*SubnetworkIdentificationRequest_Name) isSubnetworkIdentificationRequest_Identifier() {}
+
 type SubnetworkCreationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	NetworkId     uint32                 `protobuf:"varint,1,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
-	Address       uint32                 `protobuf:"fixed32,2,opt,name=address,proto3" json:"address,omitempty"`
-	PrefixLength  uint32                 `protobuf:"fixed32,3,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	NetworkId    uint32                 `protobuf:"varint,1,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	Address      uint32                 `protobuf:"fixed32,2,opt,name=address,proto3" json:"address,omitempty"`
+	PrefixLength uint32                 `protobuf:"fixed32,3,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
+	Labels       map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Optional, unique among subnetworks
+	Name string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional, a repeated creation request with the same key returns the subnetwork created by the first request instead of creating another one.
+	// Keys are unique within a project and are kept for the retention window configured on the API.
+	IdempotencyKey string `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *SubnetworkCreationRequest) Reset() {
@@ -127,10 +181,32 @@ func (x *SubnetworkCreationRequest) GetPrefixLength() uint32 {
 	return 0
 }
 
+func (x *SubnetworkCreationRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SubnetworkCreationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubnetworkCreationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type SubnetworkUpdateRequest struct {
 	state          protoimpl.MessageState           `protogen:"open.v1"`
 	Identification *SubnetworkIdentificationRequest `protobuf:"bytes,1,opt,name=identification,proto3" json:"identification,omitempty"`
 	// TODO: different message for update body, disallow passing different networkId
+	// Replaces every field, so a left out name or labels are cleared
 	Update        *SubnetworkCreationRequest `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -181,12 +257,22 @@ func (x *SubnetworkUpdateRequest) GetUpdate() *SubnetworkCreationRequest {
 }
 
 type Subnetwork struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	NetworkId     uint32                 `protobuf:"varint,2,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
-	Address       uint32                 `protobuf:"fixed32,3,opt,name=address,proto3" json:"address,omitempty"`
-	PrefixLength  uint32                 `protobuf:"fixed32,4,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NetworkId    uint32                 `protobuf:"varint,2,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	Address      uint32                 `protobuf:"fixed32,3,opt,name=address,proto3" json:"address,omitempty"`
+	PrefixLength uint32                 `protobuf:"fixed32,4,opt,name=prefix_length,json=prefixLength,proto3" json:"prefix_length,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	// Increases with every change to the subnetwork
+	ResourceVersion uint64            `protobuf:"varint,6,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	Labels          map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Name            string            `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
+	// Project the subnetwork belongs to, 0 is the default project
+	ProjectId uint32 `protobuf:"varint,9,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// Key of the request that created the subnetwork, if it had one
+	IdempotencyKey string `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Identity of the caller that created the subnetwork, empty for anonymous callers
+	CreatedBy     string `protobuf:"bytes,11,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -256,21 +342,136 @@ func (x *Subnetwork) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Subnetwork) GetResourceVersion() uint64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+func (x *Subnetwork) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Subnetwork) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Subnetwork) GetProjectId() uint32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *Subnetwork) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Subnetwork) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type SubnetworkEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=bx2cloud.EventType" json:"type,omitempty"`
+	// Position of the event in the stream of subnetwork events, used to resume a watch
+	Revision      uint64      `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Subnetwork    *Subnetwork `protobuf:"bytes,3,opt,name=subnetwork,proto3" json:"subnetwork,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubnetworkEvent) Reset() {
+	*x = SubnetworkEvent{}
+	mi := &file_subnetwork_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubnetworkEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubnetworkEvent) ProtoMessage() {}
+
+func (x *SubnetworkEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_subnetwork_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubnetworkEvent.ProtoReflect.Descriptor instead.
+func (*SubnetworkEvent) Descriptor() ([]byte, []int) {
+	return file_subnetwork_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubnetworkEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *SubnetworkEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *SubnetworkEvent) GetSubnetwork() *Subnetwork {
+	if x != nil {
+		return x.Subnetwork
+	}
+	return nil
+}
+
 var File_subnetwork_proto protoreflect.FileDescriptor
 
 const file_subnetwork_proto_rawDesc = "" +
 	"\n" +
-	"\x10subnetwork.proto\x12\bbx2cloud\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"1\n" +
-	"\x1fSubnetworkIdentificationRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"y\n" +
+	"\x10subnetwork.proto\x12\bbx2cloud\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"list.proto\x1a\vwatch.proto\"\x9c\x01\n" +
+	"\x1fSubnetworkIdentificationRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\rH\x00R\x02id\x12\x14\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x12.\n" +
+	"\x10resource_version\x18\x02 \x01(\x04H\x01R\x0fresourceVersion\x88\x01\x01B\f\n" +
+	"\n" +
+	"identifierB\x13\n" +
+	"\x11_resource_version\"\xba\x02\n" +
 	"\x19SubnetworkCreationRequest\x12\x1d\n" +
 	"\n" +
 	"network_id\x18\x01 \x01(\rR\tnetworkId\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\aR\aaddress\x12#\n" +
-	"\rprefix_length\x18\x03 \x01(\aR\fprefixLength\"\xa9\x01\n" +
+	"\rprefix_length\x18\x03 \x01(\aR\fprefixLength\x12G\n" +
+	"\x06labels\x18\x04 \x03(\v2/.bx2cloud.SubnetworkCreationRequest.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa9\x01\n" +
 	"\x17SubnetworkUpdateRequest\x12Q\n" +
 	"\x0eidentification\x18\x01 \x01(\v2).bx2cloud.SubnetworkIdentificationRequestR\x0eidentification\x12;\n" +
-	"\x06update\x18\x02 \x01(\v2#.bx2cloud.SubnetworkCreationRequestR\x06update\"\xb4\x01\n" +
+	"\x06update\x18\x02 \x01(\v2#.bx2cloud.SubnetworkCreationRequestR\x06update\"\xcf\x03\n" +
 	"\n" +
 	"Subnetwork\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1d\n" +
@@ -278,13 +479,32 @@ const file_subnetwork_proto_rawDesc = "" +
 	"network_id\x18\x02 \x01(\rR\tnetworkId\x12\x18\n" +
 	"\aaddress\x18\x03 \x01(\aR\aaddress\x12#\n" +
 	"\rprefix_length\x18\x04 \x01(\aR\fprefixLength\x128\n" +
-	"\tcreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xe8\x02\n" +
-	"\x11SubnetworkService\x12F\n" +
-	"\x03Get\x12).bx2cloud.SubnetworkIdentificationRequest\x1a\x14.bx2cloud.Subnetwork\x126\n" +
-	"\x04List\x12\x16.google.protobuf.Empty\x1a\x14.bx2cloud.Subnetwork0\x01\x12C\n" +
-	"\x06Create\x12#.bx2cloud.SubnetworkCreationRequest\x1a\x14.bx2cloud.Subnetwork\x12A\n" +
-	"\x06Update\x12!.bx2cloud.SubnetworkUpdateRequest\x1a\x14.bx2cloud.Subnetwork\x12K\n" +
-	"\x06Delete\x12).bx2cloud.SubnetworkIdentificationRequest\x1a\x16.google.protobuf.EmptyB,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
+	"\tcreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12)\n" +
+	"\x10resource_version\x18\x06 \x01(\x04R\x0fresourceVersion\x128\n" +
+	"\x06labels\x18\a \x03(\v2 .bx2cloud.Subnetwork.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04name\x18\b \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\t \x01(\rR\tprojectId\x12'\n" +
+	"\x0fidempotency_key\x18\n" +
+	" \x01(\tR\x0eidempotencyKey\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\v \x01(\tR\tcreatedBy\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8c\x01\n" +
+	"\x0fSubnetworkEvent\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.bx2cloud.EventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x124\n" +
+	"\n" +
+	"subnetwork\x18\x03 \x01(\v2\x14.bx2cloud.SubnetworkR\n" +
+	"subnetwork2\xea\x05\n" +
+	"\x11SubnetworkService\x12\x86\x01\n" +
+	"\x03Get\x12).bx2cloud.SubnetworkIdentificationRequest\x1a\x14.bx2cloud.Subnetwork\">\x82\xd3\xe4\x93\x028Z \x12\x1e/v1/subnetworks/by-name/{name}\x12\x14/v1/subnetworks/{id}\x12N\n" +
+	"\x04List\x12\x15.bx2cloud.ListRequest\x1a\x14.bx2cloud.Subnetwork\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/subnetworks0\x01\x12[\n" +
+	"\x05Watch\x12\x16.bx2cloud.WatchRequest\x1a\x19.bx2cloud.SubnetworkEvent\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/subnetworks:watch0\x01\x12_\n" +
+	"\x06Create\x12#.bx2cloud.SubnetworkCreationRequest\x1a\x14.bx2cloud.Subnetwork\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/subnetworks\x12\xaf\x01\n" +
+	"\x06Update\x12!.bx2cloud.SubnetworkUpdateRequest\x1a\x14.bx2cloud.Subnetwork\"l\x82\xd3\xe4\x93\x02f:\x06updateZ7:\x06update\x1a-/v1/subnetworks/by-name/{identification.name}\x1a#/v1/subnetworks/{identification.id}\x12\x8b\x01\n" +
+	"\x06Delete\x12).bx2cloud.SubnetworkIdentificationRequest\x1a\x16.google.protobuf.Empty\">\x82\xd3\xe4\x93\x028Z *\x1e/v1/subnetworks/by-name/{name}*\x14/v1/subnetworks/{id}B,Z*github.com/BenasB/bx2cloud/internal/api/pbb\x06proto3"
 
 var (
 	file_subnetwork_proto_rawDescOnce sync.Once
@@ -298,34 +518,46 @@ func file_subnetwork_proto_rawDescGZIP() []byte {
 	return file_subnetwork_proto_rawDescData
 }
 
-var file_subnetwork_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_subnetwork_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_subnetwork_proto_goTypes = []any{
 	(*SubnetworkIdentificationRequest)(nil), // 0: bx2cloud.SubnetworkIdentificationRequest
 	(*SubnetworkCreationRequest)(nil),       // 1: bx2cloud.SubnetworkCreationRequest
 	(*SubnetworkUpdateRequest)(nil),         // 2: bx2cloud.SubnetworkUpdateRequest
 	(*Subnetwork)(nil),                      // 3: bx2cloud.Subnetwork
-	(*timestamppb.Timestamp)(nil),           // 4: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                   // 5: google.protobuf.Empty
+	(*SubnetworkEvent)(nil),                 // 4: bx2cloud.SubnetworkEvent
+	nil,                                     // 5: bx2cloud.SubnetworkCreationRequest.LabelsEntry
+	nil,                                     // 6: bx2cloud.Subnetwork.LabelsEntry
+	(*timestamppb.Timestamp)(nil),           // 7: google.protobuf.Timestamp
+	(EventType)(0),                          // 8: bx2cloud.EventType
+	(*ListRequest)(nil),                     // 9: bx2cloud.ListRequest
+	(*WatchRequest)(nil),                    // 10: bx2cloud.WatchRequest
+	(*emptypb.Empty)(nil),                   // 11: google.protobuf.Empty
 }
 var file_subnetwork_proto_depIdxs = []int32{
-	0, // 0: bx2cloud.SubnetworkUpdateRequest.identification:type_name -> bx2cloud.SubnetworkIdentificationRequest
-	1, // 1: bx2cloud.SubnetworkUpdateRequest.update:type_name -> bx2cloud.SubnetworkCreationRequest
-	4, // 2: bx2cloud.Subnetwork.createdAt:type_name -> google.protobuf.Timestamp
-	0, // 3: bx2cloud.SubnetworkService.Get:input_type -> bx2cloud.SubnetworkIdentificationRequest
-	5, // 4: bx2cloud.SubnetworkService.List:input_type -> google.protobuf.Empty
-	1, // 5: bx2cloud.SubnetworkService.Create:input_type -> bx2cloud.SubnetworkCreationRequest
-	2, // 6: bx2cloud.SubnetworkService.Update:input_type -> bx2cloud.SubnetworkUpdateRequest
-	0, // 7: bx2cloud.SubnetworkService.Delete:input_type -> bx2cloud.SubnetworkIdentificationRequest
-	3, // 8: bx2cloud.SubnetworkService.Get:output_type -> bx2cloud.Subnetwork
-	3, // 9: bx2cloud.SubnetworkService.List:output_type -> bx2cloud.Subnetwork
-	3, // 10: bx2cloud.SubnetworkService.Create:output_type -> bx2cloud.Subnetwork
-	3, // 11: bx2cloud.SubnetworkService.Update:output_type -> bx2cloud.Subnetwork
-	5, // 12: bx2cloud.SubnetworkService.Delete:output_type -> google.protobuf.Empty
-	8, // [8:13] is the sub-list for method output_type
-	3, // [3:8] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5,  // 0: bx2cloud.SubnetworkCreationRequest.labels:type_name -> bx2cloud.SubnetworkCreationRequest.LabelsEntry
+	0,  // 1: bx2cloud.SubnetworkUpdateRequest.identification:type_name -> bx2cloud.SubnetworkIdentificationRequest
+	1,  // 2: bx2cloud.SubnetworkUpdateRequest.update:type_name -> bx2cloud.SubnetworkCreationRequest
+	7,  // 3: bx2cloud.Subnetwork.createdAt:type_name -> google.protobuf.Timestamp
+	6,  // 4: bx2cloud.Subnetwork.labels:type_name -> bx2cloud.Subnetwork.LabelsEntry
+	8,  // 5: bx2cloud.SubnetworkEvent.type:type_name -> bx2cloud.EventType
+	3,  // 6: bx2cloud.SubnetworkEvent.subnetwork:type_name -> bx2cloud.Subnetwork
+	0,  // 7: bx2cloud.SubnetworkService.Get:input_type -> bx2cloud.SubnetworkIdentificationRequest
+	9,  // 8: bx2cloud.SubnetworkService.List:input_type -> bx2cloud.ListRequest
+	10, // 9: bx2cloud.SubnetworkService.Watch:input_type -> bx2cloud.WatchRequest
+	1,  // 10: bx2cloud.SubnetworkService.Create:input_type -> bx2cloud.SubnetworkCreationRequest
+	2,  // 11: bx2cloud.SubnetworkService.Update:input_type -> bx2cloud.SubnetworkUpdateRequest
+	0,  // 12: bx2cloud.SubnetworkService.Delete:input_type -> bx2cloud.SubnetworkIdentificationRequest
+	3,  // 13: bx2cloud.SubnetworkService.Get:output_type -> bx2cloud.Subnetwork
+	3,  // 14: bx2cloud.SubnetworkService.List:output_type -> bx2cloud.Subnetwork
+	4,  // 15: bx2cloud.SubnetworkService.Watch:output_type -> bx2cloud.SubnetworkEvent
+	3,  // 16: bx2cloud.SubnetworkService.Create:output_type -> bx2cloud.Subnetwork
+	3,  // 17: bx2cloud.SubnetworkService.Update:output_type -> bx2cloud.Subnetwork
+	11, // 18: bx2cloud.SubnetworkService.Delete:output_type -> google.protobuf.Empty
+	13, // [13:19] is the sub-list for method output_type
+	7,  // [7:13] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_subnetwork_proto_init() }
@@ -333,13 +565,19 @@ func file_subnetwork_proto_init() {
 	if File_subnetwork_proto != nil {
 		return
 	}
+	file_list_proto_init()
+	file_watch_proto_init()
+	file_subnetwork_proto_msgTypes[0].OneofWrappers = []any{
+		(*SubnetworkIdentificationRequest_Id)(nil),
+		(*SubnetworkIdentificationRequest_Name)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subnetwork_proto_rawDesc), len(file_subnetwork_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
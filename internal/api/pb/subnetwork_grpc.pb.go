@@ -22,6 +22,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	SubnetworkService_Get_FullMethodName    = "/bx2cloud.SubnetworkService/Get"
 	SubnetworkService_List_FullMethodName   = "/bx2cloud.SubnetworkService/List"
+	SubnetworkService_Watch_FullMethodName  = "/bx2cloud.SubnetworkService/Watch"
 	SubnetworkService_Create_FullMethodName = "/bx2cloud.SubnetworkService/Create"
 	SubnetworkService_Update_FullMethodName = "/bx2cloud.SubnetworkService/Update"
 	SubnetworkService_Delete_FullMethodName = "/bx2cloud.SubnetworkService/Delete"
@@ -32,7 +33,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SubnetworkServiceClient interface {
 	Get(ctx context.Context, in *SubnetworkIdentificationRequest, opts ...grpc.CallOption) (*Subnetwork, error)
-	List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Subnetwork], error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Subnetwork], error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubnetworkEvent], error)
 	Create(ctx context.Context, in *SubnetworkCreationRequest, opts ...grpc.CallOption) (*Subnetwork, error)
 	Update(ctx context.Context, in *SubnetworkUpdateRequest, opts ...grpc.CallOption) (*Subnetwork, error)
 	Delete(ctx context.Context, in *SubnetworkIdentificationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -56,13 +58,13 @@ func (c *subnetworkServiceClient) Get(ctx context.Context, in *SubnetworkIdentif
 	return out, nil
 }
 
-func (c *subnetworkServiceClient) List(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Subnetwork], error) {
+func (c *subnetworkServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Subnetwork], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &SubnetworkService_ServiceDesc.Streams[0], SubnetworkService_List_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[emptypb.Empty, Subnetwork]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ListRequest, Subnetwork]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -75,6 +77,25 @@ func (c *subnetworkServiceClient) List(ctx context.Context, in *emptypb.Empty, o
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SubnetworkService_ListClient = grpc.ServerStreamingClient[Subnetwork]
 
+func (c *subnetworkServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubnetworkEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SubnetworkService_ServiceDesc.Streams[1], SubnetworkService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, SubnetworkEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SubnetworkService_WatchClient = grpc.ServerStreamingClient[SubnetworkEvent]
+
 func (c *subnetworkServiceClient) Create(ctx context.Context, in *SubnetworkCreationRequest, opts ...grpc.CallOption) (*Subnetwork, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Subnetwork)
@@ -110,7 +131,8 @@ func (c *subnetworkServiceClient) Delete(ctx context.Context, in *SubnetworkIden
 // for forward compatibility.
 type SubnetworkServiceServer interface {
 	Get(context.Context, *SubnetworkIdentificationRequest) (*Subnetwork, error)
-	List(*emptypb.Empty, grpc.ServerStreamingServer[Subnetwork]) error
+	List(*ListRequest, grpc.ServerStreamingServer[Subnetwork]) error
+	Watch(*WatchRequest, grpc.ServerStreamingServer[SubnetworkEvent]) error
 	Create(context.Context, *SubnetworkCreationRequest) (*Subnetwork, error)
 	Update(context.Context, *SubnetworkUpdateRequest) (*Subnetwork, error)
 	Delete(context.Context, *SubnetworkIdentificationRequest) (*emptypb.Empty, error)
@@ -127,9 +149,12 @@ type UnimplementedSubnetworkServiceServer struct{}
 func (UnimplementedSubnetworkServiceServer) Get(context.Context, *SubnetworkIdentificationRequest) (*Subnetwork, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (UnimplementedSubnetworkServiceServer) List(*emptypb.Empty, grpc.ServerStreamingServer[Subnetwork]) error {
+func (UnimplementedSubnetworkServiceServer) List(*ListRequest, grpc.ServerStreamingServer[Subnetwork]) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedSubnetworkServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[SubnetworkEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedSubnetworkServiceServer) Create(context.Context, *SubnetworkCreationRequest) (*Subnetwork, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
@@ -179,16 +204,27 @@ func _SubnetworkService_Get_Handler(srv interface{}, ctx context.Context, dec fu
 }
 
 func _SubnetworkService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(emptypb.Empty)
+	m := new(ListRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(SubnetworkServiceServer).List(m, &grpc.GenericServerStream[emptypb.Empty, Subnetwork]{ServerStream: stream})
+	return srv.(SubnetworkServiceServer).List(m, &grpc.GenericServerStream[ListRequest, Subnetwork]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SubnetworkService_ListServer = grpc.ServerStreamingServer[Subnetwork]
 
+func _SubnetworkService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubnetworkServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, SubnetworkEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SubnetworkService_WatchServer = grpc.ServerStreamingServer[SubnetworkEvent]
+
 func _SubnetworkService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SubnetworkCreationRequest)
 	if err := dec(in); err != nil {
@@ -273,6 +309,11 @@ var SubnetworkService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _SubnetworkService_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _SubnetworkService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "subnetwork.proto",
 }
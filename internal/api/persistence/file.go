@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// On-disk representation of a collection of resources of a single type
+type collection struct {
+	LastId uint32            `json:"lastId"`
+	Items  []json.RawMessage `json:"items"`
+}
+
+// Loads a collection previously stored with SaveCollection.
+// A missing file is treated as an empty collection.
+func LoadCollection[T proto.Message](path string, newItem func() T) ([]T, uint32, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make([]T, 0), 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	var c collection
+	if err := json.Unmarshal(bytes, &c); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode %q: %w", path, err)
+	}
+
+	items := make([]T, 0, len(c.Items))
+	for _, raw := range c.Items {
+		item := newItem()
+		if err := protojson.Unmarshal(raw, item); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode an item in %q: %w", path, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, c.LastId, nil
+}
+
+// Atomically replaces the collection stored at path
+func SaveCollection[T proto.Message](path string, items []T, lastId uint32) error {
+	c := collection{
+		LastId: lastId,
+		Items:  make([]json.RawMessage, 0, len(items)),
+	}
+
+	for _, item := range items {
+		raw, err := protojson.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode an item: %w", err)
+		}
+		c.Items = append(c.Items, raw)
+	}
+
+	bytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the collection: %w", err)
+	}
+
+	return WriteFileAtomic(path, bytes, 0o600)
+}
+
+// Writes data to a temporary file in the same directory, flushes it to disk and renames it over path,
+// so that a crash at any point leaves either the old or the new contents, never a partial write
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write the temporary file: %w", err)
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions of the temporary file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to flush the temporary file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close the temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace %q: %w", path, err)
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open the directory to flush the rename: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to flush the directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BenasB/bx2cloud/internal/api/id"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/persistence"
+)
+
+// Keeps projects in memory and persists every change to a JSON file before acknowledging it
+func NewFileRepository(stateDir string) (interfaces.ProjectRepository, error) {
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the state directory: %w", err)
+	}
+
+	path := filepath.Join(stateDir, "projects.json")
+	projects, lastId, err := persistence.LoadCollection(path, func() *interfaces.ProjectModel {
+		return &interfaces.ProjectModel{}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	for _, project := range projects {
+		lastId = max(lastId, project.Id)
+	}
+	id.Restore("project", lastId)
+
+	return &memoryRepository{
+		projects: withDefaultProject(projects),
+		persist: func(projects []*interfaces.ProjectModel) error {
+			for _, project := range projects {
+				lastId = max(lastId, project.Id)
+			}
+			return persistence.SaveCollection(path, projects, lastId)
+		},
+	}, nil
+}
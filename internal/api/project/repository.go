@@ -0,0 +1,168 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/id"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ interfaces.ProjectRepository = &memoryRepository{}
+
+// The slice is never modified in place, so readers can keep iterating over a snapshot of it without holding the lock
+type memoryRepository struct {
+	mu       sync.RWMutex
+	projects []*interfaces.ProjectModel
+	// Called with the new state before a change is committed, the change is discarded if it fails
+	persist func(projects []*interfaces.ProjectModel) error
+}
+
+// The default project is added if projects do not have it
+func NewMemoryRepository(projects []*interfaces.ProjectModel) interfaces.ProjectRepository {
+	ps := make([]*interfaces.ProjectModel, len(projects))
+	for i, project := range projects {
+		ps[i] = proto.Clone(project).(*interfaces.ProjectModel)
+	}
+
+	return &memoryRepository{
+		projects: withDefaultProject(ps),
+		persist: func(projects []*interfaces.ProjectModel) error {
+			return nil
+		},
+	}
+}
+
+func withDefaultProject(projects []*interfaces.ProjectModel) []*interfaces.ProjectModel {
+	for _, project := range projects {
+		if project.Id == DEFAULT_PROJECT_ID {
+			return projects
+		}
+	}
+
+	return append([]*interfaces.ProjectModel{{
+		Id:              DEFAULT_PROJECT_ID,
+		Name:            DEFAULT_PROJECT_NAME,
+		CreatedAt:       timestamppb.New(time.Now()),
+		ResourceVersion: 1,
+	}}, projects...)
+}
+
+func (r *memoryRepository) Get(id uint32) (*interfaces.ProjectModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, project := range r.projects {
+		if project.Id == id {
+			return proto.Clone(project).(*interfaces.ProjectModel), nil
+		}
+	}
+
+	return nil, apierrors.NotFound("could not find project with id %d", id)
+}
+
+func (r *memoryRepository) GetByName(name string) (*interfaces.ProjectModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, project := range r.projects {
+		if name != "" && project.Name == name {
+			return proto.Clone(project).(*interfaces.ProjectModel), nil
+		}
+	}
+
+	return nil, apierrors.NotFound("could not find project with name %q", name)
+}
+
+func (r *memoryRepository) List(ctx context.Context, options *listing.Options) ([]*interfaces.ProjectModel, bool, error) {
+	r.mu.RLock()
+	projects := r.projects
+	r.mu.RUnlock()
+
+	matching := make([]*interfaces.ProjectModel, 0)
+	for _, project := range projects {
+		if options.MatchesLabels(project.Labels) {
+			matching = append(matching, project)
+		}
+	}
+
+	page, more := listing.Page(matching, options, interfaces.ProjectOrderFields)
+	for i, project := range page {
+		page[i] = proto.Clone(project).(*interfaces.ProjectModel)
+	}
+
+	return page, more, ctx.Err()
+}
+
+func (r *memoryRepository) Add(project *interfaces.ProjectModel) (*interfaces.ProjectModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkNameAvailable(project.Name); err != nil {
+		return nil, err
+	}
+
+	newProject := proto.Clone(project).(*interfaces.ProjectModel)
+	newProject.Id = id.NextId("project")
+	newProject.CreatedAt = timestamppb.New(time.Now())
+	newProject.ResourceVersion = 1
+
+	projects := append(slices.Clone(r.projects), newProject)
+	if err := r.persist(projects); err != nil {
+		return nil, fmt.Errorf("failed to persist the new project: %w", err)
+	}
+
+	r.projects = projects
+	return proto.Clone(newProject).(*interfaces.ProjectModel), nil
+}
+
+func (r *memoryRepository) Delete(id uint32, expectedVersion *uint64) (*interfaces.ProjectModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, project := range r.projects {
+		if project.Id != id {
+			continue
+		}
+
+		if err := interfaces.CheckResourceVersion(expectedVersion, project.ResourceVersion); err != nil {
+			return nil, err
+		}
+
+		projects := slices.Delete(slices.Clone(r.projects), i, i+1)
+		if err := r.persist(projects); err != nil {
+			return nil, fmt.Errorf("failed to persist the project deletion: %w", err)
+		}
+
+		r.projects = projects
+		return project, nil
+	}
+
+	return nil, apierrors.NotFound("could not find project with id %d", id)
+}
+
+// Must be called with the lock held. Unlike other resources, projects always have a name, since callers select them by it.
+func (r *memoryRepository) checkNameAvailable(name string) error {
+	if name == "" {
+		return apierrors.InvalidArgument("name", fmt.Errorf("%w: projects must have a name", interfaces.ErrInvalidName))
+	}
+
+	if err := interfaces.ValidateName(name); err != nil {
+		return err
+	}
+
+	for _, project := range r.projects {
+		if project.Name == name {
+			return fmt.Errorf("%w: project %d is already named %q", interfaces.ErrNameTaken, project.Id, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Resources created before projects were introduced have no project id, so they belong to the default project
+const (
+	DEFAULT_PROJECT_ID   uint32 = 0
+	DEFAULT_PROJECT_NAME        = "default"
+)
+
+var ErrProjectNotAllowed = apierrors.New(codes.PermissionDenied, "the token does not allow access to the project")
+
+type scopeKey struct{}
+
+// Serializes deleting a project with adding networks to it, subnetworks and containers can only be added to networks of their project
+var locks = shared.NewKeyedMutex()
+
+type scope struct {
+	projectId uint32
+	name      string
+	// Services fail with it when they need the project, so that calls that do not need one still work
+	err error
+	// Whether the caller's token is bound to the project
+	bound bool
+	// Where the project was found, nil for the default project
+	repository interfaces.ProjectRepository
+}
+
+// Determines the caller's project of every call, which services then read with CallerId
+type resolver struct {
+	repository interfaces.ProjectRepository
+}
+
+func NewResolver(repository interfaces.ProjectRepository) *resolver {
+	return &resolver{
+		repository: repository,
+	}
+}
+
+func (r *resolver) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(r.resolve(ctx), req)
+	}
+}
+
+func (r *resolver) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, shared.WithContext(stream, r.resolve(stream.Context())))
+	}
+}
+
+// A token bound to a project takes precedence over the metadata, which may then only name the same project
+func (r *resolver) resolve(ctx context.Context) context.Context {
+	requested := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(auth.PROJECT_METADATA_KEY); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	bound := auth.BoundProject(ctx)
+	s := &scope{bound: bound != ""}
+	name := requested
+	if s.bound {
+		if requested != "" && requested != bound {
+			s.err = fmt.Errorf("%w %q", ErrProjectNotAllowed, requested)
+			return withScope(ctx, s)
+		}
+		name = bound
+	}
+
+	if name == "" {
+		s.projectId = DEFAULT_PROJECT_ID
+		s.name = DEFAULT_PROJECT_NAME
+		return withScope(ctx, s)
+	}
+
+	project, err := r.repository.GetByName(name)
+	if err != nil {
+		s.err = apierrors.FailedPrecondition("the selected project %q does not exist", name)
+		return withScope(ctx, s)
+	}
+
+	s.projectId = project.Id
+	s.name = project.Name
+	s.repository = r.repository
+	return withScope(ctx, s)
+}
+
+func withScope(ctx context.Context, s *scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// Returns the id of the caller's project, the default project if the call did not go through the resolver
+func CallerId(ctx context.Context) (uint32, error) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return DEFAULT_PROJECT_ID, nil
+	}
+
+	return s.projectId, s.err
+}
+
+// Returns the name of the caller's project, "" if the project could not be determined
+func CallerName(ctx context.Context) string {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return DEFAULT_PROJECT_NAME
+	}
+
+	return s.name
+}
+
+// Locks the caller's project until unlock is called, so that the project is not deleted in between,
+// and fails if the project was deleted since the call was resolved
+func LockCaller(ctx context.Context) (unlock func(), err error) {
+	projectId, err := CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock = locks.Lock(projectId)
+
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok && s.repository != nil {
+		if _, err := s.repository.Get(projectId); err != nil {
+			unlock()
+			return nil, apierrors.FailedPrecondition("the selected project %q does not exist", s.name)
+		}
+	}
+
+	return unlock, nil
+}
+
+// Callers whose token is bound to a project can only see that project and can not manage projects
+func isBound(ctx context.Context) bool {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	return ok && s.bound
+}
+
+// Used by tests and internal callers to act as a caller of a specific project
+func WithCallerId(ctx context.Context, projectId uint32) context.Context {
+	return withScope(ctx, &scope{projectId: projectId})
+}
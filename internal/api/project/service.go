@@ -0,0 +1,154 @@
+package project
+
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var ErrManagementNotAllowed = apierrors.New(codes.PermissionDenied, "tokens bound to a project can not create or delete projects")
+
+type service struct {
+	pb.UnimplementedProjectServiceServer
+	repository        interfaces.ProjectRepository
+	networkRepository interfaces.NetworkRepository
+}
+
+func NewService(repository interfaces.ProjectRepository, networkRepository interfaces.NetworkRepository) *service {
+	return &service{
+		repository:        repository,
+		networkRepository: networkRepository,
+	}
+}
+
+func (s *service) Get(ctx context.Context, req *pb.ProjectIdentificationRequest) (*pb.Project, error) {
+	return s.get(ctx, req)
+}
+
+func (s *service) Create(ctx context.Context, req *pb.ProjectCreationRequest) (*pb.Project, error) {
+	if isBound(ctx) {
+		return nil, ErrManagementNotAllowed
+	}
+
+	if err := labels.Validate(req.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	return s.repository.Add(&interfaces.ProjectModel{
+		Name:   req.Name,
+		Labels: req.Labels,
+	})
+}
+
+func (s *service) Delete(ctx context.Context, req *pb.ProjectIdentificationRequest) (*emptypb.Empty, error) {
+	if isBound(ctx) {
+		return nil, ErrManagementNotAllowed
+	}
+
+	project, err := s.get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.Id == DEFAULT_PROJECT_ID {
+		return nil, apierrors.FailedPrecondition("the default project can not be deleted")
+	}
+
+	// Networks are added under the same lock, so none can be added between the check and the deletion
+	defer locks.Lock(project.Id)()
+
+	// Subnetworks and containers can only be in a network of their own project, so checking networks is enough
+	networks, _, err := s.networkRepository.List(ctx, &interfaces.NetworkFilter{ProjectId: project.Id}, &listing.Options{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(networks) > 0 {
+		return nil, apierrors.FailedPrecondition("network with id %d still belongs to the project with id %d", networks[0].Id, project.Id)
+	}
+
+	if _, err := s.repository.Delete(project.Id, req.ResourceVersion); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) List(req *pb.ListRequest, stream grpc.ServerStreamingServer[pb.Project]) error {
+	if err := listing.CheckFilters(req, "projects"); err != nil {
+		return err
+	}
+
+	options, err := listing.FromRequest(req, interfaces.ProjectOrderFields)
+	if err != nil {
+		return err
+	}
+
+	var projects []*interfaces.ProjectModel
+	more := false
+	if isBound(stream.Context()) {
+		// Other projects are not visible to callers that are bound to a project
+		callerId, err := CallerId(stream.Context())
+		if err != nil {
+			return err
+		}
+		project, err := s.repository.Get(callerId)
+		if err != nil {
+			return err
+		}
+		visible := make([]*interfaces.ProjectModel, 0)
+		if options.MatchesLabels(project.Labels) {
+			visible = append(visible, project)
+		}
+		projects, more = listing.Page(visible, options, interfaces.ProjectOrderFields)
+	} else {
+		projects, more, err = s.repository.List(stream.Context(), options)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, project := range projects {
+		if err := stream.Send(project); err != nil {
+			return err
+		}
+	}
+
+	listing.SetNextPageToken(stream, options.NextPageToken(more))
+	return nil
+}
+
+// Projects can be identified either by id or by name, callers that are bound to a project can only get their own
+func (s *service) get(ctx context.Context, req *pb.ProjectIdentificationRequest) (*pb.Project, error) {
+	var project *interfaces.ProjectModel
+	var err error
+	if _, ok := req.Identifier.(*pb.ProjectIdentificationRequest_Name); ok {
+		project, err = s.repository.GetByName(req.GetName())
+	} else {
+		project, err = s.repository.Get(req.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isBound(ctx) {
+		callerId, err := CallerId(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if project.Id != callerId {
+			if req.GetName() != "" {
+				return nil, apierrors.NotFound("could not find project with name %q", req.GetName())
+			}
+			return nil, apierrors.NotFound("could not find project with id %d", req.GetId())
+		}
+	}
+
+	return project, nil
+}
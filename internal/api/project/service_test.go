@@ -0,0 +1,156 @@
+package project_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestProject_Scoping(t *testing.T) {
+	projectRepository := project.NewMemoryRepository(nil)
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	projectService := project.NewService(projectRepository, networkRepository)
+	networkService := network.NewService(networkRepository, subnetworkRepository, network.NewMockConfigurator(), quota.NewMockChecker(), idempotency.NewMockTracker())
+	subnetworkService := subnetwork.NewService(subnetworkRepository, networkRepository, subnetwork.NewMockConfigurator(), nil, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	team, err := projectService.Create(t.Context(), &pb.ProjectCreationRequest{Name: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	teamCtx := project.WithCallerId(t.Context(), team.Id)
+
+	teamNetwork, err := networkService.Create(teamCtx, &pb.NetworkCreationRequest{Name: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teamNetwork.ProjectId != team.Id {
+		t.Errorf("expected the network to belong to project %d, got %d", team.Id, teamNetwork.ProjectId)
+	}
+
+	// Names are only unique within a project
+	if _, err := networkService.Create(t.Context(), &pb.NetworkCreationRequest{Name: "backend"}); err != nil {
+		t.Errorf("expected the name to be available in the default project, got %v", err)
+	}
+
+	_, err = networkService.Get(t.Context(), &pb.NetworkIdentificationRequest{
+		Identifier: &pb.NetworkIdentificationRequest_Id{Id: teamNetwork.Id},
+	})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("expected a network of another project to not be found, got %v", err)
+	}
+
+	_, err = subnetworkService.Create(t.Context(), &pb.SubnetworkCreationRequest{
+		NetworkId:    teamNetwork.Id,
+		Address:      0x0a000000,
+		PrefixLength: 24,
+	})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("expected a subnetwork in a network of another project to be rejected, got %v", err)
+	}
+
+	stream := shared.NewMockStream[*pb.Network](teamCtx)
+	if err := networkService.List(&pb.ListRequest{}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.SentItems) != 1 || stream.SentItems[0].Id != teamNetwork.Id {
+		t.Errorf("expected only the network of the project to be listed, got %v", stream.SentItems)
+	}
+
+	_, err = projectService.Delete(t.Context(), &pb.ProjectIdentificationRequest{
+		Identifier: &pb.ProjectIdentificationRequest_Name{Name: "team"},
+	})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Errorf("expected a project with networks to not be deleted, got %v", err)
+	}
+}
+
+func TestProject_NetworkNotAddedToDeletedProject(t *testing.T) {
+	projectRepository := project.NewMemoryRepository([]*interfaces.ProjectModel{{Id: 7, Name: "team"}})
+	networkRepository := network.NewMemoryRepository(nil)
+	projectService := project.NewService(projectRepository, networkRepository)
+	networkService := network.NewService(networkRepository, subnetwork.NewMemoryRepository(nil), network.NewMockConfigurator(), quota.NewMockChecker(), idempotency.NewMockTracker())
+	interceptor := project.NewResolver(projectRepository).UnaryInterceptor()
+
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(auth.PROJECT_METADATA_KEY, "team"))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		// The project is deleted after the call was resolved to it
+		if _, err := projectService.Delete(t.Context(), &pb.ProjectIdentificationRequest{
+			Identifier: &pb.ProjectIdentificationRequest_Id{Id: 7},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		return networkService.Create(ctx, &pb.NetworkCreationRequest{Name: "backend"})
+	})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Errorf("expected the network to not be added to the deleted project, got %v", err)
+	}
+
+	networks, err := shared.CollectAll(networkRepository.GetAll(t.Context()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 0 {
+		t.Errorf("expected no networks, got %v", networks)
+	}
+}
+
+func TestProject_DefaultCanNotBeDeleted(t *testing.T) {
+	service := project.NewService(project.NewMemoryRepository(nil), network.NewMemoryRepository(nil))
+
+	_, err := service.Delete(t.Context(), &pb.ProjectIdentificationRequest{
+		Identifier: &pb.ProjectIdentificationRequest_Name{Name: project.DEFAULT_PROJECT_NAME},
+	})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Errorf("expected %s, got %v", codes.FailedPrecondition, err)
+	}
+}
+
+func TestProject_Resolver(t *testing.T) {
+	repository := project.NewMemoryRepository([]*interfaces.ProjectModel{{Id: 7, Name: "team"}})
+	interceptor := project.NewResolver(repository).UnaryInterceptor()
+
+	tests := map[string]struct {
+		requested string
+		wantId    uint32
+		wantCode  codes.Code
+	}{
+		"no project":       {"", project.DEFAULT_PROJECT_ID, codes.OK},
+		"existing project": {"team", 7, codes.OK},
+		"missing project":  {"other", 0, codes.FailedPrecondition},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if test.requested != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(auth.PROJECT_METADATA_KEY, test.requested))
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				id, err := project.CallerId(ctx)
+				if err == nil && id != test.wantId {
+					t.Errorf("expected project %d, got %d", test.wantId, id)
+				}
+				return nil, err
+			})
+			if code := status.Code(err); code != test.wantCode {
+				t.Errorf("expected %s, got %v", test.wantCode, err)
+			}
+		})
+	}
+}
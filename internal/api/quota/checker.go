@@ -0,0 +1,235 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ interfaces.QuotaChecker = &checker{}
+
+// Counts the resources that an identity created when it creates more, so nothing has to be kept in sync with the repositories
+type checker struct {
+	quotas               config.Quotas
+	networkRepository    interfaces.NetworkRepository
+	subnetworkRepository interfaces.SubnetworkRepository
+	containerRepository  interfaces.ContainerRepository
+	// Guards locks and pending
+	mu sync.Mutex
+	// Make counting and reserving atomic per identity, so that counting the resources of one identity does not hold up the others
+	locks map[string]*sync.Mutex
+	// Resources that are being created and might not be in the repositories yet
+	pending map[string]map[interfaces.QuotaResource]int
+}
+
+func NewChecker(
+	quotas config.Quotas,
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+) *checker {
+	return &checker{
+		quotas:               quotas,
+		networkRepository:    networkRepository,
+		subnetworkRepository: subnetworkRepository,
+		containerRepository:  containerRepository,
+		locks:                make(map[string]*sync.Mutex),
+		pending:              make(map[string]map[interfaces.QuotaResource]int),
+	}
+}
+
+func (c *checker) Reserve(ctx context.Context, identity string, resources ...interfaces.QuotaResource) (func(...interfaces.QuotaResource), error) {
+	unlock := c.lock(identity)
+	defer unlock()
+
+	limits := c.limits(identity)
+	for _, resource := range resources {
+		limit := limits[resource]
+		if limit == 0 {
+			continue
+		}
+
+		used, err := c.count(ctx, identity, resource)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		pending := c.pending[identity][resource]
+		c.mu.Unlock()
+
+		if used+pending >= limit {
+			return nil, apierrors.QuotaExceeded(
+				fmt.Sprintf("identity:%s/%s", identity, resource),
+				"the %s quota of %s is exceeded, it allows at most %d", resource, describe(identity), limit,
+			)
+		}
+	}
+
+	c.mu.Lock()
+	if c.pending[identity] == nil {
+		c.pending[identity] = make(map[interfaces.QuotaResource]int)
+	}
+	for _, resource := range resources {
+		c.pending[identity][resource]++
+	}
+	c.mu.Unlock()
+
+	left := slices.Clone(resources)
+	var leftMu sync.Mutex
+	return func(released ...interfaces.QuotaResource) {
+		leftMu.Lock()
+		defer leftMu.Unlock()
+
+		if len(released) == 0 {
+			released = slices.Clone(left)
+		}
+
+		// Releasing waits for the identity's reservations that are counting, so that a resource that has just been added
+		// to its repository is not missed by both the count and the pending reservations
+		unlock := c.lock(identity)
+		defer unlock()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for _, resource := range released {
+			i := slices.Index(left, resource)
+			if i < 0 {
+				continue
+			}
+			left = slices.Delete(left, i, i+1)
+			c.pending[identity][resource]--
+		}
+	}, nil
+}
+
+// Resources that are still being created are not included
+func (c *checker) Usage(ctx context.Context, identity string) (*pb.QuotaUsage, error) {
+	limits := c.limits(identity)
+
+	usage := &pb.QuotaUsage{
+		Identity: identity,
+		Items:    make([]*pb.QuotaItem, 0, len(interfaces.QuotaResources)),
+	}
+	for _, resource := range interfaces.QuotaResources {
+		used, err := c.count(ctx, identity, resource)
+		if err != nil {
+			return nil, err
+		}
+		usage.Items = append(usage.Items, &pb.QuotaItem{
+			Resource: string(resource),
+			Used:     uint32(used),
+			Limit:    uint32(limits[resource]),
+		})
+	}
+
+	return usage, nil
+}
+
+func (c *checker) lock(identity string) func() {
+	c.mu.Lock()
+	l, ok := c.locks[identity]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[identity] = l
+	}
+	c.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func (c *checker) limits(identity string) map[interfaces.QuotaResource]int {
+	limits := c.quotas.For(identity)
+	return map[interfaces.QuotaResource]int{
+		interfaces.QUOTA_NETWORKS:           limits.Networks,
+		interfaces.QUOTA_SUBNETWORKS:        limits.Subnetworks,
+		interfaces.QUOTA_CONTAINERS:         limits.Containers,
+		interfaces.QUOTA_RUNNING_CONTAINERS: limits.RunningContainers,
+		interfaces.QUOTA_IPS:                limits.Ips,
+	}
+}
+
+// Only the state of the identity's own containers is read
+func (c *checker) count(ctx context.Context, identity string, resource interfaces.QuotaResource) (int, error) {
+	switch resource {
+	case interfaces.QUOTA_NETWORKS:
+		networks, err := shared.CollectAll(c.networkRepository.GetAll(ctx))
+		if err != nil {
+			return 0, err
+		}
+		return countFunc(networks, func(n *interfaces.NetworkModel) bool { return n.CreatedBy == identity }), nil
+	case interfaces.QUOTA_SUBNETWORKS:
+		subnetworks, err := shared.CollectAll(c.subnetworkRepository.GetAll(ctx))
+		if err != nil {
+			return 0, err
+		}
+		return countFunc(subnetworks, func(s *interfaces.SubnetworkModel) bool { return s.CreatedBy == identity }), nil
+	case interfaces.QUOTA_CONTAINERS, interfaces.QUOTA_RUNNING_CONTAINERS, interfaces.QUOTA_IPS:
+		all, err := shared.CollectAll(c.containerRepository.GetAll(ctx))
+		if err != nil {
+			return 0, err
+		}
+		containers := slices.DeleteFunc(all, func(container interfaces.ContainerModel) bool { return container.GetData().CreatedBy != identity })
+
+		switch resource {
+		case interfaces.QUOTA_IPS:
+			return countFunc(containers, func(container interfaces.ContainerModel) bool { return container.GetData().Ip != nil }), nil
+		case interfaces.QUOTA_RUNNING_CONTAINERS:
+			running := 0
+			for _, container := range containers {
+				state, err := container.GetState()
+				if err != nil {
+					return 0, err
+				}
+				if state.Status == runspecs.StateRunning {
+					running++
+				}
+			}
+			return running, nil
+		default:
+			return len(containers), nil
+		}
+	default:
+		return 0, fmt.Errorf("unknown quota resource %q", resource)
+	}
+}
+
+func countFunc[T any](items []T, f func(T) bool) int {
+	count := 0
+	for _, item := range items {
+		if f(item) {
+			count++
+		}
+	}
+	return count
+}
+
+func describe(identity string) string {
+	if identity == "" {
+		return "anonymous callers"
+	}
+	return fmt.Sprintf("identity %q", identity)
+}
+
+var _ interfaces.QuotaChecker = &mockChecker{}
+
+type mockChecker struct{}
+
+// Allows everything
+func NewMockChecker() interfaces.QuotaChecker {
+	return &mockChecker{}
+}
+
+func (m *mockChecker) Reserve(ctx context.Context, identity string, resources ...interfaces.QuotaResource) (func(...interfaces.QuotaResource), error) {
+	return func(...interfaces.QuotaResource) {}, nil
+}
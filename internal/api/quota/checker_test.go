@@ -0,0 +1,192 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/config"
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A container repository without containers
+type noContainers struct {
+	interfaces.ContainerRepository
+}
+
+func (noContainers) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	containers := make(chan interfaces.ContainerModel)
+	errs := make(chan error)
+	close(containers)
+	close(errs)
+	return containers, errs
+}
+
+// Blocks network configuration until unblocked, so that a network can be in the repository while it is still being created
+type blockingConfigurator struct {
+	configuring chan struct{}
+	unblock     chan struct{}
+}
+
+func (c *blockingConfigurator) Configure(ctx context.Context, model *interfaces.NetworkModel) error {
+	c.configuring <- struct{}{}
+	<-c.unblock
+	return nil
+}
+
+func (c *blockingConfigurator) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
+	return nil
+}
+
+func (c *blockingConfigurator) Verify(model *interfaces.NetworkModel) error {
+	return nil
+}
+
+func TestChecker_Networks(t *testing.T) {
+	two := 2
+	quotas := config.Quotas{
+		Default: config.Limits{Networks: 1},
+		Identities: map[string]config.LimitOverrides{
+			"team": {Networks: &two},
+		},
+	}
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	checker := quota.NewChecker(quotas, networkRepository, subnetworkRepository, noContainers{})
+	service := network.NewService(networkRepository, subnetworkRepository, network.NewMockConfigurator(), checker, idempotency.NewMockTracker())
+
+	if _, err := service.Create(t.Context(), &pb.NetworkCreationRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("expected anonymous callers to be limited to 1 network, got %v", err)
+	}
+
+	// The anonymous caller's network does not count towards the quota of another identity, even in the same project
+	teamCtx := auth.WithCertificateName(t.Context(), "team")
+	for range two {
+		if _, err := service.Create(teamCtx, &pb.NetworkCreationRequest{}); err != nil {
+			t.Errorf("expected the identity override to allow %d networks, got %v", two, err)
+		}
+	}
+	if _, err := service.Create(teamCtx, &pb.NetworkCreationRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected the identity to be limited to %d networks, got %v", two, err)
+	}
+
+	// Quotas apply across projects
+	otherProjectCtx := project.WithCallerId(teamCtx, 7)
+	if _, err := service.Create(otherProjectCtx, &pb.NetworkCreationRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected the identity's networks in other projects to count towards its quota, got %v", err)
+	}
+
+	usage, err := checker.Usage(teamCtx, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range usage.Items {
+		if item.Resource == string(interfaces.QUOTA_NETWORKS) && (item.Used != 2 || item.Limit != 2) {
+			t.Errorf("expected the identity to use 2 of 2 networks, got %d of %d", item.Used, item.Limit)
+		}
+	}
+}
+
+func TestChecker_PendingReservations(t *testing.T) {
+	quotas := config.Quotas{Default: config.Limits{Subnetworks: 1}}
+	checker := quota.NewChecker(quotas, nil, subnetwork.NewMemoryRepository(nil), nil)
+
+	release, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_SUBNETWORKS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_SUBNETWORKS); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected a subnetwork that is being created to count towards the quota, got %v", err)
+	}
+
+	if _, err := checker.Reserve(t.Context(), "other", interfaces.QUOTA_SUBNETWORKS); err != nil {
+		t.Errorf("expected the reservation of one identity not to count towards another, got %v", err)
+	}
+
+	release()
+	release()
+
+	if _, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_SUBNETWORKS); err != nil {
+		t.Errorf("expected the released reservation to free the quota, got %v", err)
+	}
+}
+
+func TestChecker_PartialRelease(t *testing.T) {
+	quotas := config.Quotas{Default: config.Limits{Containers: 1, Ips: 1}}
+	checker := quota.NewChecker(quotas, nil, nil, noContainers{})
+
+	release, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_CONTAINERS, interfaces.QUOTA_IPS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release(interfaces.QUOTA_CONTAINERS)
+	release(interfaces.QUOTA_CONTAINERS)
+
+	if _, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_CONTAINERS); err != nil {
+		t.Errorf("expected the released resource to free its quota, got %v", err)
+	}
+	if _, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_IPS); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected the resource that is left to still count towards the quota, got %v", err)
+	}
+
+	release()
+
+	if _, err := checker.Reserve(t.Context(), "team", interfaces.QUOTA_IPS); err != nil {
+		t.Errorf("expected releasing without resources to release the rest, got %v", err)
+	}
+}
+
+func TestChecker_AddedResourceCountsOnce(t *testing.T) {
+	quotas := config.Quotas{Default: config.Limits{Networks: 2}}
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	checker := quota.NewChecker(quotas, networkRepository, subnetworkRepository, noContainers{})
+	configurator := &blockingConfigurator{configuring: make(chan struct{}), unblock: make(chan struct{})}
+	service := network.NewService(networkRepository, subnetworkRepository, configurator, checker, idempotency.NewMockTracker())
+
+	created := make(chan error)
+	go func() {
+		_, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+		created <- err
+	}()
+
+	// The first network is in the repository and being configured
+	<-configurator.configuring
+
+	second := make(chan error)
+	go func() {
+		_, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+		second <- err
+	}()
+
+	select {
+	case <-configurator.configuring:
+	case err := <-second:
+		t.Fatalf("expected the network that is being configured to count once towards the quota, got %v", err)
+	}
+
+	close(configurator.unblock)
+	for _, results := range []chan error{created, second} {
+		if err := <-results; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := service.Create(t.Context(), &pb.NetworkCreationRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected the quota of 2 networks to be used up, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type service struct {
+	pb.UnimplementedQuotaServiceServer
+	checker *checker
+}
+
+func NewService(checker *checker) *service {
+	return &service{
+		checker: checker,
+	}
+}
+
+func (s *service) GetUsage(ctx context.Context, req *emptypb.Empty) (*pb.QuotaUsage, error) {
+	return s.checker.Usage(ctx, auth.Identity(ctx))
+}
@@ -0,0 +1,139 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrPermissionDenied = apierrors.New(codes.PermissionDenied, "permission denied")
+
+// Methods that act across all projects or on the whole host, so the project selected by the caller does not scope them
+var clusterScopedMethods = []string{
+	"/bx2cloud.ProjectService/*",
+	"/bx2cloud.AdminService/*",
+}
+
+var _ interfaces.Authorizer = &authorizer{}
+
+// Checks every call against the policy, it needs the caller's identity and project, so it runs after authentication and project resolution
+type authorizer struct {
+	policy *Policy
+	// Groups of each identity
+	memberships map[string][]string
+}
+
+func NewAuthorizer(policy *Policy) *authorizer {
+	memberships := make(map[string][]string)
+	for group, members := range policy.Groups {
+		for _, member := range members {
+			memberships[member] = append(memberships[member], group)
+		}
+	}
+
+	return &authorizer{
+		policy:      policy,
+		memberships: memberships,
+	}
+}
+
+func (a *authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.Authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// Returns ErrPermissionDenied if none of the caller's roles allow the method in the caller's project, public methods are always allowed.
+// Cluster scoped methods are only allowed by roles that apply in every project.
+func (a *authorizer) Authorize(ctx context.Context, fullMethod string) error {
+	if auth.IsPublic(fullMethod) {
+		return nil
+	}
+
+	identity := auth.Identity(ctx)
+	projectName := project.CallerName(ctx)
+
+	for _, binding := range a.policy.Bindings {
+		if !a.binds(binding, identity) {
+			continue
+		}
+
+		role := a.policy.Roles[binding.Role]
+		if allows(role, fullMethod, projectName) {
+			return nil
+		}
+	}
+
+	slog.WarnContext(ctx, "Denied a call", "method", fullMethod, "identity", identity, "project", projectName)
+
+	return fmt.Errorf("%w: %s is not allowed in project %q", ErrPermissionDenied, fullMethod, projectName)
+}
+
+func (a *authorizer) binds(binding Binding, identity string) bool {
+	if slices.Contains(binding.Identities, ANY_IDENTITY) {
+		return true
+	}
+
+	if identity == "" {
+		return false
+	}
+
+	if slices.Contains(binding.Identities, identity) {
+		return true
+	}
+
+	return slices.ContainsFunc(a.memberships[identity], func(group string) bool {
+		return slices.Contains(binding.Groups, group)
+	})
+}
+
+func allows(role Role, fullMethod string, projectName string) bool {
+	if len(role.Projects) > 0 && (!slices.Contains(role.Projects, projectName) || matches(clusterScopedMethods, fullMethod)) {
+		return false
+	}
+
+	return matches(role.Methods, fullMethod)
+}
+
+func matches(patterns []string, fullMethod string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, fullMethod)
+		return matched
+	})
+}
+
+var _ interfaces.Authorizer = &allowAllAuthorizer{}
+
+// Allows every call. Used when no policy is loaded, which means access control is off.
+type allowAllAuthorizer struct{}
+
+func NewAllowAllAuthorizer() interfaces.Authorizer {
+	return &allowAllAuthorizer{}
+}
+
+func (a *allowAllAuthorizer) Authorize(ctx context.Context, fullMethod string) error {
+	return nil
+}
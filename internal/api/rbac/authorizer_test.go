@@ -0,0 +1,150 @@
+package rbac_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/rbac"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const policy = `
+groups:
+  viewers: [alice]
+roles:
+  reader:
+    methods:
+      - /bx2cloud.*/Get
+      - /bx2cloud.*/List
+      - /bx2cloud.ContainerService/Logs
+  team-admin:
+    methods: [/bx2cloud.*/*]
+    projects: [team]
+bindings:
+  - role: reader
+    groups: [viewers]
+  - role: team-admin
+    identities: [bob]
+`
+
+func TestAuthorizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := rbac.LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Identities come from tokens, no project is resolved so every call is in the default project
+	authenticated := auth.NewTokenAuthenticator([]auth.Token{
+		{Value: "alice", Identity: "alice"},
+		{Value: "bob", Identity: "bob"},
+		{Value: "nobody"},
+	}).UnaryInterceptor()
+	authorized := rbac.NewAuthorizer(p).UnaryInterceptor()
+
+	tests := map[string]struct {
+		token  string
+		method string
+		want   codes.Code
+	}{
+		"reader gets":              {"alice", "/bx2cloud.NetworkService/Get", codes.OK},
+		"reader reads logs":        {"alice", "/bx2cloud.ContainerService/Logs", codes.OK},
+		"reader can not exec":      {"alice", "/bx2cloud.ContainerService/Exec", codes.PermissionDenied},
+		"reader can not delete":    {"alice", "/bx2cloud.NetworkService/Delete", codes.PermissionDenied},
+		"scoped role elsewhere":    {"bob", "/bx2cloud.NetworkService/Create", codes.PermissionDenied},
+		"no identity":              {"nobody", "/bx2cloud.NetworkService/Get", codes.PermissionDenied},
+		"wildcard stays in a part": {"alice", "/bx2cloud.NetworkService/Get/x", codes.PermissionDenied},
+		"health checks are public": {"nobody", "/grpc.health.v1.Health/Check", codes.OK},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("authorization", "Bearer "+test.token))
+			_, err := authenticated(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				return authorized(ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				})
+			})
+			if code := status.Code(err); code != test.want {
+				t.Errorf("expected %s, got %s (%v)", test.want, code, err)
+			}
+			if test.want == codes.PermissionDenied && !errors.Is(err, rbac.ErrPermissionDenied) {
+				t.Errorf("expected ErrPermissionDenied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_AnyIdentity(t *testing.T) {
+	p := &rbac.Policy{
+		Roles:    map[string]rbac.Role{"reader": {Methods: []string{"/bx2cloud.*/Get"}}},
+		Bindings: []rbac.Binding{{Role: "reader", Identities: []string{rbac.ANY_IDENTITY}}},
+	}
+
+	if err := rbac.NewAuthorizer(p).Authorize(t.Context(), "/bx2cloud.NetworkService/Get"); err != nil {
+		t.Errorf("expected anonymous callers to be allowed, got %v", err)
+	}
+}
+
+func TestAuthorizer_ClusterScopedMethods(t *testing.T) {
+	// Calls without a resolved project are in the default project, which the scoped role applies in
+	scoped := &rbac.Policy{
+		Roles:    map[string]rbac.Role{"team-admin": {Methods: []string{"/bx2cloud.*/*"}, Projects: []string{"default"}}},
+		Bindings: []rbac.Binding{{Role: "team-admin", Identities: []string{rbac.ANY_IDENTITY}}},
+	}
+	unscoped := &rbac.Policy{
+		Roles:    map[string]rbac.Role{"admin": {Methods: []string{"/bx2cloud.*/*"}}},
+		Bindings: []rbac.Binding{{Role: "admin", Identities: []string{rbac.ANY_IDENTITY}}},
+	}
+
+	tests := map[string]struct {
+		policy *rbac.Policy
+		method string
+		want   error
+	}{
+		"scoped role in its project":      {scoped, "/bx2cloud.NetworkService/Create", nil},
+		"scoped role creates a project":   {scoped, "/bx2cloud.ProjectService/Create", rbac.ErrPermissionDenied},
+		"scoped role deletes a project":   {scoped, "/bx2cloud.ProjectService/Delete", rbac.ErrPermissionDenied},
+		"scoped role collects garbage":    {scoped, "/bx2cloud.AdminService/CollectGarbage", rbac.ErrPermissionDenied},
+		"unscoped role deletes a project": {unscoped, "/bx2cloud.ProjectService/Delete", nil},
+		"unscoped role collects garbage":  {unscoped, "/bx2cloud.AdminService/CollectGarbage", nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := rbac.NewAuthorizer(test.policy).Authorize(t.Context(), test.method)
+			if !errors.Is(err, test.want) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	reader := map[string]rbac.Role{"reader": {Methods: []string{"/bx2cloud.*/Get"}}}
+
+	for name, p := range map[string]*rbac.Policy{
+		"no methods":      {Roles: map[string]rbac.Role{"empty": {}}},
+		"relative method": {Roles: map[string]rbac.Role{"bad": {Methods: []string{"bx2cloud.NetworkService/Get"}}}},
+		"bad pattern":     {Roles: map[string]rbac.Role{"bad": {Methods: []string{"/bx2cloud.[/Get"}}}},
+		"unknown role":    {Roles: reader, Bindings: []rbac.Binding{{Role: "writer", Identities: []string{"alice"}}}},
+		"unknown group":   {Roles: reader, Bindings: []rbac.Binding{{Role: "reader", Groups: []string{"viewers"}}}},
+		"nobody is bound": {Roles: reader, Bindings: []rbac.Binding{{Role: "reader"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := p.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
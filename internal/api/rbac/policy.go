@@ -0,0 +1,87 @@
+package rbac
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matches every caller, including callers without an identity
+const ANY_IDENTITY = "*"
+
+// Grants roles to callers, a call is allowed if any role of the caller allows it
+type Policy struct {
+	// Members of each group by identity
+	Groups   map[string][]string `yaml:"groups"`
+	Roles    map[string]Role     `yaml:"roles"`
+	Bindings []Binding           `yaml:"bindings"`
+}
+
+type Role struct {
+	// Full gRPC method names, e.g. "/bx2cloud.NetworkService/Get", where '*' matches any part of a service or method name
+	Methods []string `yaml:"methods"`
+	// Names of the projects that the role applies in, every project if empty
+	Projects []string `yaml:"projects"`
+}
+
+type Binding struct {
+	Role       string   `yaml:"role"`
+	Identities []string `yaml:"identities"`
+	Groups     []string `yaml:"groups"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the policy file: %w", err)
+	}
+	defer f.Close()
+
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("failed to parse the policy file: %w", err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	for name, role := range p.Roles {
+		if len(role.Methods) == 0 {
+			return fmt.Errorf("role %q does not allow any methods", name)
+		}
+		for _, method := range role.Methods {
+			if !strings.HasPrefix(method, "/") {
+				return fmt.Errorf("role %q has method %q which is not a full method name starting with '/'", name, method)
+			}
+			if _, err := path.Match(method, ""); err != nil {
+				return fmt.Errorf("role %q has an invalid method pattern %q: %w", name, method, err)
+			}
+		}
+	}
+
+	for i, binding := range p.Bindings {
+		if _, ok := p.Roles[binding.Role]; !ok {
+			return fmt.Errorf("binding %d refers to an unknown role %q", i, binding.Role)
+		}
+		if len(binding.Identities) == 0 && len(binding.Groups) == 0 {
+			return fmt.Errorf("binding %d of role %q has no identities or groups", i, binding.Role)
+		}
+		for _, group := range binding.Groups {
+			if _, ok := p.Groups[group]; !ok {
+				return fmt.Errorf("binding %d refers to an unknown group %q", i, group)
+			}
+		}
+	}
+
+	return nil
+}
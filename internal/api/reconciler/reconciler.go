@@ -0,0 +1,203 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	runspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type networkConfigurator interface {
+	Configure(ctx context.Context, model *interfaces.NetworkModel) error
+	Verify(model *interfaces.NetworkModel) error
+}
+
+type subnetworkConfigurator interface {
+	Configure(ctx context.Context, model *interfaces.SubnetworkModel) error
+	Verify(model *interfaces.SubnetworkModel) error
+}
+
+type containerConfigurator interface {
+	Configure(ctx context.Context, model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
+	Verify(model interfaces.ContainerModel, subnetworkModel *interfaces.SubnetworkModel) error
+}
+
+// Outcome of a single reconciliation pass
+type Report struct {
+	// Resources whose host configuration matched the desired state
+	InSync int
+	// Resources whose host configuration drifted and was re-applied
+	Repaired int
+	// Resources whose host configuration drifted and could not be re-applied
+	Failed int
+}
+
+type reconciler struct {
+	networkRepository      interfaces.NetworkRepository
+	subnetworkRepository   interfaces.SubnetworkRepository
+	containerRepository    interfaces.ContainerRepository
+	networkConfigurator    networkConfigurator
+	subnetworkConfigurator subnetworkConfigurator
+	containerConfigurator  containerConfigurator
+}
+
+func New(
+	networkRepository interfaces.NetworkRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+	containerRepository interfaces.ContainerRepository,
+	networkConfigurator networkConfigurator,
+	subnetworkConfigurator subnetworkConfigurator,
+	containerConfigurator containerConfigurator,
+) *reconciler {
+	return &reconciler{
+		networkRepository:      networkRepository,
+		subnetworkRepository:   subnetworkRepository,
+		containerRepository:    containerRepository,
+		networkConfigurator:    networkConfigurator,
+		subnetworkConfigurator: subnetworkConfigurator,
+		containerConfigurator:  containerConfigurator,
+	}
+}
+
+// Reconciles periodically until the context is cancelled
+func (r *reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				slog.ErrorContext(ctx, "Reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Walks every network, subnetwork and running container (in that order, since each depends on the previous)
+// and re-applies the host configuration of the ones that drifted from the desired state.
+// Each resource is checked while holding its lock, so resources that are being created or deleted are waited for,
+// and resources that were deleted since they were listed are skipped.
+func (r *reconciler) Reconcile(ctx context.Context) (*Report, error) {
+	report := &Report{}
+
+	networks, err := shared.CollectAll(r.networkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve networks: %w", err)
+	}
+	for _, listed := range networks {
+		func() {
+			defer shared.NetworkLocks.Lock(listed.Id)()
+
+			// The model may have changed since it was listed
+			network, err := r.networkRepository.Get(listed.Id)
+			if err != nil {
+				r.skip(ctx, report, "network", listed.Id, err)
+				return
+			}
+
+			r.reconcileOne(ctx, report, "network", network.Id,
+				func() error { return r.networkConfigurator.Verify(network) },
+				func() error { return r.networkConfigurator.Configure(ctx, network) },
+			)
+		}()
+	}
+
+	subnetworks, err := shared.CollectAll(r.subnetworkRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve subnetworks: %w", err)
+	}
+	for _, listed := range subnetworks {
+		func() {
+			defer shared.SubnetworkLocks.Lock(listed.Id)()
+
+			subnetwork, err := r.subnetworkRepository.Get(listed.Id)
+			if err != nil {
+				r.skip(ctx, report, "subnetwork", listed.Id, err)
+				return
+			}
+
+			r.reconcileOne(ctx, report, "subnetwork", subnetwork.Id,
+				func() error { return r.subnetworkConfigurator.Verify(subnetwork) },
+				func() error { return r.subnetworkConfigurator.Configure(ctx, subnetwork) },
+			)
+		}()
+	}
+
+	containers, err := shared.CollectAll(r.containerRepository.GetAll(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve containers: %w", err)
+	}
+	for _, listed := range containers {
+		id := listed.GetData().Id
+		func() {
+			defer shared.ContainerLocks.Lock(id)()
+
+			container, err := r.containerRepository.Get(id)
+			if err != nil {
+				r.skip(ctx, report, "container", id, err)
+				return
+			}
+
+			// Only containers with a process have a network namespace to configure
+			state, err := container.GetState()
+			if err != nil || (state.Status != runspecs.StateRunning && state.Status != runspecs.StateCreated) {
+				return
+			}
+
+			data := container.GetData()
+			subnetwork, err := r.subnetworkRepository.Get(data.SubnetworkId)
+			if err != nil {
+				slog.WarnContext(ctx, "Skipping reconciliation of a container, since its subnetwork could not be found", "container_id", data.Id, "error", err)
+				report.Failed++
+				return
+			}
+
+			r.reconcileOne(ctx, report, "container", data.Id,
+				func() error { return r.containerConfigurator.Verify(container, subnetwork) },
+				func() error { return r.containerConfigurator.Configure(ctx, container, subnetwork) },
+			)
+		}()
+	}
+
+	slog.InfoContext(ctx, "Reconciliation finished", "in_sync", report.InSync, "repaired", report.Repaired, "failed", report.Failed)
+
+	return report, nil
+}
+
+// Resources that were deleted while waiting for their lock are not counted
+func (r *reconciler) skip(ctx context.Context, report *Report, kind string, id uint32, err error) {
+	if status.Code(err) == codes.NotFound {
+		return
+	}
+
+	slog.ErrorContext(ctx, "Failed to retrieve a resource to reconcile", "kind", kind, "id", id, "error", err)
+	report.Failed++
+}
+
+func (r *reconciler) reconcileOne(ctx context.Context, report *Report, kind string, id uint32, verify func() error, configure func() error) {
+	drift := verify()
+	if drift == nil {
+		report.InSync++
+		return
+	}
+
+	slog.WarnContext(ctx, "Detected drift", "kind", kind, "id", id, "drift", drift)
+
+	if err := configure(); err != nil {
+		slog.ErrorContext(ctx, "Failed to repair drift", "kind", kind, "id", id, "error", err)
+		report.Failed++
+		return
+	}
+
+	slog.InfoContext(ctx, "Repaired drift", "kind", kind, "id", id)
+	report.Repaired++
+}
@@ -0,0 +1,297 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/network"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
+	"github.com/BenasB/bx2cloud/internal/api/reconciler"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+)
+
+type driftingNetworkConfigurator struct {
+	configured map[uint32]bool
+}
+
+func (c *driftingNetworkConfigurator) Configure(ctx context.Context, model *interfaces.NetworkModel) error {
+	c.configured[model.Id] = true
+	return nil
+}
+
+func (c *driftingNetworkConfigurator) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
+	delete(c.configured, model.Id)
+	return nil
+}
+
+func (c *driftingNetworkConfigurator) Verify(model *interfaces.NetworkModel) error {
+	if !c.configured[model.Id] {
+		return errors.New("namespace is missing")
+	}
+	return nil
+}
+
+type emptyContainerRepository struct {
+	interfaces.ContainerRepository
+}
+
+func (r *emptyContainerRepository) GetAll(ctx context.Context) (<-chan interfaces.ContainerModel, <-chan error) {
+	results := make(chan interfaces.ContainerModel)
+	errChan := make(chan error)
+	close(results)
+	close(errChan)
+	return results, errChan
+}
+
+func TestReconciler_RepairsDrift(t *testing.T) {
+	networkRepository := network.NewMemoryRepository([]*interfaces.NetworkModel{
+		{Id: 1, InternetAccess: true},
+		{Id: 2, InternetAccess: false},
+	})
+	networkConfigurator := &driftingNetworkConfigurator{
+		configured: map[uint32]bool{1: true},
+	}
+
+	r := reconciler.New(
+		networkRepository,
+		subnetwork.NewMemoryRepository(nil),
+		&emptyContainerRepository{},
+		networkConfigurator,
+		subnetwork.NewMockConfigurator(),
+		nil,
+	)
+
+	report, err := r.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.InSync != 1 || report.Repaired != 1 || report.Failed != 0 {
+		t.Errorf("Unexpected report of the first pass: %+v", report)
+	}
+
+	report, err = r.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.InSync != 2 || report.Repaired != 0 {
+		t.Errorf("Expected everything to be in sync after a repair, got %+v", report)
+	}
+}
+
+// Blocks the service's configuration changes until released and fails the test if two of them overlap
+type blockingNetworkConfigurator struct {
+	t          *testing.T
+	mu         sync.Mutex
+	configured map[uint32]bool
+	busy       map[uint32]bool
+	// Receives the id of a network whose configuration change started blocking
+	blocked chan uint32
+	release chan struct{}
+}
+
+func newBlockingNetworkConfigurator(t *testing.T) *blockingNetworkConfigurator {
+	return &blockingNetworkConfigurator{
+		t:          t,
+		configured: make(map[uint32]bool),
+		busy:       make(map[uint32]bool),
+		blocked:    make(chan uint32, 1),
+		release:    make(chan struct{}),
+	}
+}
+
+func (c *blockingNetworkConfigurator) change(id uint32, configured bool, block bool) {
+	c.mu.Lock()
+	if c.busy[id] {
+		c.t.Errorf("network %d was configured while its configuration was already being changed", id)
+	}
+	c.busy[id] = true
+	c.mu.Unlock()
+
+	if block {
+		c.blocked <- id
+		<-c.release
+	}
+
+	c.mu.Lock()
+	c.configured[id] = configured
+	c.busy[id] = false
+	c.mu.Unlock()
+}
+
+func (c *blockingNetworkConfigurator) Configure(ctx context.Context, model *interfaces.NetworkModel) error {
+	// Only the service's calls block, the reconciler's calls are told apart by their context
+	c.change(model.Id, true, ctx.Value(serviceCall{}) != nil)
+	return nil
+}
+
+func (c *blockingNetworkConfigurator) Unconfigure(ctx context.Context, model *interfaces.NetworkModel) error {
+	c.change(model.Id, false, true)
+	return nil
+}
+
+func (c *blockingNetworkConfigurator) Verify(model *interfaces.NetworkModel) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.configured[model.Id] {
+		return errors.New("namespace is missing")
+	}
+	return nil
+}
+
+type serviceCall struct{}
+
+// Tells when the reconciler has listed the networks, after which it takes the lock of each of them
+type listingNetworkRepository struct {
+	interfaces.NetworkRepository
+	listed chan struct{}
+}
+
+func (r *listingNetworkRepository) GetAll(ctx context.Context) (<-chan *interfaces.NetworkModel, <-chan error) {
+	networks, err := shared.CollectAll(r.NetworkRepository.GetAll(ctx))
+
+	results := make(chan *interfaces.NetworkModel, len(networks))
+	errChan := make(chan error, 1)
+	for _, network := range networks {
+		results <- network
+	}
+	if err != nil {
+		errChan <- err
+	}
+	close(results)
+	close(errChan)
+
+	r.listed <- struct{}{}
+	return results, errChan
+}
+
+func TestReconciler_WaitsForCreateAndDelete(t *testing.T) {
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	configurator := newBlockingNetworkConfigurator(t)
+	service := network.NewService(networkRepository, subnetworkRepository, configurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+	listingRepository := &listingNetworkRepository{NetworkRepository: networkRepository, listed: make(chan struct{})}
+	r := reconciler.New(
+		listingRepository,
+		subnetworkRepository,
+		&emptyContainerRepository{},
+		configurator,
+		subnetwork.NewMockConfigurator(),
+		nil,
+	)
+	ctx := context.WithValue(t.Context(), serviceCall{}, true)
+
+	// Reconciles while the service is blocked in the middle of a change, then lets the service finish
+	reconcileDuring := func(call func() error) *reconciler.Report {
+		t.Helper()
+
+		called := make(chan error)
+		go func() { called <- call() }()
+		<-configurator.blocked
+
+		reconciled := make(chan *reconciler.Report)
+		go func() {
+			report, err := r.Reconcile(t.Context())
+			if err != nil {
+				t.Error(err)
+			}
+			reconciled <- report
+		}()
+
+		// The network is listed while the service holds its lock, so the reconciler has to wait for the service
+		<-listingRepository.listed
+		close(configurator.release)
+		if err := <-called; err != nil {
+			t.Fatal(err)
+		}
+		configurator.release = make(chan struct{})
+		return <-reconciled
+	}
+
+	var created *pb.Network
+	report := reconcileDuring(func() error {
+		var err error
+		created, err = service.Create(ctx, &pb.NetworkCreationRequest{})
+		return err
+	})
+	if report.Repaired != 0 || report.Failed != 0 {
+		t.Errorf("Expected the reconciler to wait for the network to be created, got %+v", report)
+	}
+
+	report = reconcileDuring(func() error {
+		_, err := service.Delete(ctx, &pb.NetworkIdentificationRequest{
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: created.Id},
+		})
+		return err
+	})
+	if report.Repaired != 0 || report.Failed != 0 {
+		t.Errorf("Expected the reconciler to skip the deleted network, got %+v", report)
+	}
+	if configurator.configured[created.Id] {
+		t.Error("Expected the deleted network to stay unconfigured")
+	}
+}
+
+// Blocks right after a network is added, before the service configures it
+type blockingAddNetworkRepository struct {
+	interfaces.NetworkRepository
+	added   chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingAddNetworkRepository) Add(network *interfaces.NetworkModel) (*interfaces.NetworkModel, error) {
+	added, err := r.NetworkRepository.Add(network)
+	r.added <- struct{}{}
+	<-r.release
+	return added, err
+}
+
+func TestReconciler_WaitsForAddedNetwork(t *testing.T) {
+	networkRepository := network.NewMemoryRepository(nil)
+	subnetworkRepository := subnetwork.NewMemoryRepository(nil)
+	configurator := &driftingNetworkConfigurator{configured: make(map[uint32]bool)}
+	addingRepository := &blockingAddNetworkRepository{NetworkRepository: networkRepository, added: make(chan struct{}), release: make(chan struct{})}
+	service := network.NewService(addingRepository, subnetworkRepository, configurator, quota.NewMockChecker(), idempotency.NewMockTracker())
+	listingRepository := &listingNetworkRepository{NetworkRepository: networkRepository, listed: make(chan struct{})}
+	r := reconciler.New(
+		listingRepository,
+		subnetworkRepository,
+		&emptyContainerRepository{},
+		configurator,
+		subnetwork.NewMockConfigurator(),
+		nil,
+	)
+
+	created := make(chan error)
+	go func() {
+		_, err := service.Create(t.Context(), &pb.NetworkCreationRequest{})
+		created <- err
+	}()
+	<-addingRepository.added
+
+	reconciled := make(chan *reconciler.Report)
+	go func() {
+		report, err := r.Reconcile(t.Context())
+		if err != nil {
+			t.Error(err)
+		}
+		reconciled <- report
+	}()
+
+	// The network is in the repository but not configured yet, the reconciler has to leave it to the service
+	<-listingRepository.listed
+	close(addingRepository.release)
+	if err := <-created; err != nil {
+		t.Fatal(err)
+	}
+
+	report := <-reconciled
+	if report.InSync != 1 || report.Repaired != 0 {
+		t.Errorf("Expected the reconciler to wait for the network to be configured by the service, got %+v", report)
+	}
+}
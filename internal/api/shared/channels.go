@@ -0,0 +1,26 @@
+package shared
+
+// Drains the channels returned by the repositories' GetAll methods into a slice
+func CollectAll[T any](items <-chan T, errs <-chan error) ([]T, error) {
+	result := make([]T, 0)
+	for {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				select {
+				case err := <-errs:
+					if err != nil {
+						return nil, err
+					}
+				default:
+				}
+				return result, nil
+			}
+			result = append(result, item)
+		case err, ok := <-errs:
+			if ok {
+				return nil, err
+			}
+		}
+	}
+}
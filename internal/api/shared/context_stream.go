@@ -0,0 +1,24 @@
+package shared
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// Lets stream interceptors pass values to the handler, like unary interceptors do by calling the handler with a new context
+func WithContext(stream grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{
+		ServerStream: stream,
+		ctx:          ctx,
+	}
+}
@@ -0,0 +1,45 @@
+package shared
+
+import "sync"
+
+// Serializes operations on the same key while letting operations on different keys run concurrently
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[uint32]*keyedLock
+}
+
+type keyedLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{
+		locks: make(map[uint32]*keyedLock),
+	}
+}
+
+// Locks the key and returns a function that unlocks it
+func (m *KeyedMutex) Lock(key uint32) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		m.locks[key] = lock
+	}
+	lock.waiters++
+	m.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		m.mu.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,10 @@
+package shared
+
+// Per-id locks of the resources that are configured on the host, held while a resource's host configuration is changed.
+// They are shared by the services and the reconciler, so that the reconciler does not configure a resource
+// that is still being created or is being deleted.
+var (
+	NetworkLocks    = NewKeyedMutex()
+	SubnetworkLocks = NewKeyedMutex()
+	ContainerLocks  = NewKeyedMutex()
+)
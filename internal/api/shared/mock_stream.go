@@ -4,11 +4,13 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type mockStream[T any] struct {
 	grpc.ServerStream
 	SentItems []T
+	Trailer   metadata.MD
 	ctx       context.Context
 }
 
@@ -17,6 +19,10 @@ func (s *mockStream[T]) Send(item T) error {
 	return nil
 }
 
+func (s *mockStream[T]) SetTrailer(md metadata.MD) {
+	s.Trailer = metadata.Join(s.Trailer, md)
+}
+
 func (s *mockStream[T]) Context() context.Context {
 	return s.ctx
 }
@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses host artifact names like "<prefix><id>", rejecting anything that has more after the id (e.g. the "-ns" veth ends)
+func ParseIdSuffix(name string, prefix string) (uint32, bool) {
+	idString, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(idString, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint32(id), true
+}
+
+// Lists entries of a directory whose names are resource ids, along with their modification times
+func ListIdEntries(dir string) (map[uint32]time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", dir, err)
+	}
+
+	result := make(map[uint32]time.Time, len(entries))
+	for _, entry := range entries {
+		id, ok := ParseIdSuffix(entry.Name(), "")
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+
+		result[id] = info.ModTime()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+type compensation struct {
+	description string
+	undo        func() error
+}
+
+// Records compensating actions of a multi-step operation, so that applied steps can be undone if a later step fails
+type Rollback struct {
+	compensations []compensation
+}
+
+func NewRollback() *Rollback {
+	return &Rollback{
+		compensations: make([]compensation, 0),
+	}
+}
+
+// Registers the compensating action of a step that has just been applied
+func (r *Rollback) Add(description string, undo func() error) {
+	r.compensations = append(r.compensations, compensation{
+		description: description,
+		undo:        undo,
+	})
+}
+
+// Undoes all registered steps in reverse order and returns the cause joined with any errors encountered while undoing
+func (r *Rollback) Run(cause error) error {
+	errs := []error{cause}
+	for i := len(r.compensations) - 1; i >= 0; i-- {
+		c := r.compensations[i]
+		if err := c.undo(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to %s during rollback: %w", c.description, err))
+		}
+	}
+	r.compensations = r.compensations[:0]
+
+	return errors.Join(errs...)
+}
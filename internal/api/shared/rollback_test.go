@@ -0,0 +1,39 @@
+package shared_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+)
+
+func TestRollback_UndoesInReverseOrder(t *testing.T) {
+	undone := make([]string, 0)
+	rollback := shared.NewRollback()
+	rollback.Add("release the IP", func() error {
+		undone = append(undone, "ip")
+		return nil
+	})
+	rollback.Add("remove the rootfs", func() error {
+		undone = append(undone, "rootfs")
+		return errors.New("busy")
+	})
+	rollback.Add("remove the log file", func() error {
+		undone = append(undone, "log")
+		return nil
+	})
+
+	cause := errors.New("exec failed")
+	err := rollback.Run(cause)
+
+	if expected := []string{"log", "rootfs", "ip"}; !slices.Equal(expected, undone) {
+		t.Errorf("Expected steps to be undone in order %v, got %v", expected, undone)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected the returned error to wrap the cause, got %v", err)
+	}
+	if err.Error() != "exec failed\nfailed to remove the rootfs during rollback: busy" {
+		t.Errorf("Unexpected error message: %q", err.Error())
+	}
+}
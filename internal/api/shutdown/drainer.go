@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrShuttingDown = apierrors.New(codes.Unavailable, "the API is shutting down, retry once it is back")
+
+// Ends open streams when the API shuts down, since streams such as Watch, Logs and Exec would otherwise keep it running
+type drainer struct {
+	draining context.Context
+	drain    context.CancelFunc
+}
+
+func NewDrainer() *drainer {
+	draining, drain := context.WithCancel(context.Background())
+	return &drainer{
+		draining: draining,
+		drain:    drain,
+	}
+}
+
+// Cancels the context of every open and future stream, which then ends with ErrShuttingDown
+func (d *drainer) Drain() {
+	d.drain()
+}
+
+func (d *drainer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithCancelCause(stream.Context())
+		defer cancel(nil)
+		stop := context.AfterFunc(d.draining, func() { cancel(ErrShuttingDown) })
+		defer stop()
+
+		err := handler(srv, shared.WithContext(stream, ctx))
+		// Handlers end with whatever error the cancellation caused them, the client only needs to know why
+		if errors.Is(context.Cause(ctx), ErrShuttingDown) {
+			return ErrShuttingDown
+		}
+
+		return err
+	}
+}
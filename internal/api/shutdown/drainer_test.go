@@ -0,0 +1,48 @@
+package shutdown_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
+	"github.com/BenasB/bx2cloud/internal/api/shutdown"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrainer_EndsOpenStreams(t *testing.T) {
+	drainer := shutdown.NewDrainer()
+	interceptor := drainer.StreamInterceptor()
+
+	started := make(chan struct{})
+	result := make(chan error)
+	go func() {
+		result <- interceptor(nil, shared.NewMockStream[*pb.ContainerLogsResponse](t.Context()), &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+			close(started)
+			<-stream.Context().Done()
+			return nil
+		})
+	}()
+
+	<-started
+	drainer.Drain()
+
+	err := <-result
+	if !errors.Is(err, shutdown.ErrShuttingDown) || status.Code(err) != codes.Unavailable {
+		t.Errorf("expected ErrShuttingDown, got %v", err)
+	}
+}
+
+func TestDrainer_KeepsErrorsOfFinishedStreams(t *testing.T) {
+	interceptor := shutdown.NewDrainer().StreamInterceptor()
+
+	err := interceptor(nil, shared.NewMockStream[*pb.ContainerLogsResponse](t.Context()), &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+		return apierrors.NotFound("could not find container with id %d", 1)
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected the handler's error, got %v", err)
+	}
+}
@@ -1,10 +1,16 @@
 package subnetwork
 
-import "github.com/BenasB/bx2cloud/internal/api/interfaces"
+import (
+	"context"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+)
 
 type configurator interface {
-	Configure(model *interfaces.SubnetworkModel) error
-	Unconfigure(model *interfaces.SubnetworkModel) error
+	Configure(ctx context.Context, model *interfaces.SubnetworkModel) error
+	Unconfigure(ctx context.Context, model *interfaces.SubnetworkModel) error
+	// Returns an error describing how the host differs from the configuration of the subnetwork, if it does
+	Verify(model *interfaces.SubnetworkModel) error
 }
 
 var _ configurator = &mockConfigurator{}
@@ -15,10 +21,14 @@ func NewMockConfigurator() configurator {
 	return &mockConfigurator{}
 }
 
-func (m *mockConfigurator) Configure(model *interfaces.SubnetworkModel) error {
+func (m *mockConfigurator) Configure(ctx context.Context, model *interfaces.SubnetworkModel) error {
+	return nil
+}
+
+func (m *mockConfigurator) Unconfigure(ctx context.Context, model *interfaces.SubnetworkModel) error {
 	return nil
 }
 
-func (m *mockConfigurator) Unconfigure(model *interfaces.SubnetworkModel) error {
+func (m *mockConfigurator) Verify(model *interfaces.SubnetworkModel) error {
 	return nil
 }
@@ -1,11 +1,14 @@
 package subnetwork
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"runtime"
 
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netns"
 )
@@ -15,16 +18,18 @@ var _ configurator = &bridgeConfigurator{}
 type bridgeConfigurator struct {
 	getNetworkNamespaceName func(uint32) string
 	ipamRepository          interfaces.IpamRepository
+	interfacePrefix         string
 }
 
-func NewBridgeConfigurator(getNetworkNamespaceName func(uint32) string, ipamRepository interfaces.IpamRepository) *bridgeConfigurator {
+func NewBridgeConfigurator(getNetworkNamespaceName func(uint32) string, ipamRepository interfaces.IpamRepository, interfacePrefix string) *bridgeConfigurator {
 	return &bridgeConfigurator{
 		getNetworkNamespaceName: getNetworkNamespaceName,
 		ipamRepository:          ipamRepository,
+		interfacePrefix:         interfacePrefix,
 	}
 }
 
-func (b *bridgeConfigurator) Configure(model *interfaces.SubnetworkModel) error {
+func (b *bridgeConfigurator) Configure(ctx context.Context, model *interfaces.SubnetworkModel) error {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
@@ -103,12 +108,12 @@ func (b *bridgeConfigurator) Configure(model *interfaces.SubnetworkModel) error
 		return fmt.Errorf("failed to switch back to the root network namespace: %w", err)
 	}
 
-	log.Printf("Successfully configured subnetwork with the id %d", model.Id)
+	slog.InfoContext(ctx, "Configured subnetwork", "subnetwork_id", model.Id)
 
 	return nil
 }
 
-func (b *bridgeConfigurator) Unconfigure(model *interfaces.SubnetworkModel) error {
+func (b *bridgeConfigurator) Unconfigure(ctx context.Context, model *interfaces.SubnetworkModel) error {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
@@ -145,11 +150,80 @@ func (b *bridgeConfigurator) Unconfigure(model *interfaces.SubnetworkModel) erro
 		return fmt.Errorf("failed to switch to the root network namespace: %w", err)
 	}
 
-	log.Printf("Successfully unconfigured subnetwork with the id %d", model.Id)
+	slog.InfoContext(ctx, "Unconfigured subnetwork", "subnetwork_id", model.Id)
 
 	return nil
 }
 
+func (b *bridgeConfigurator) Verify(model *interfaces.SubnetworkModel) error {
+	netNsName := b.getNetworkNamespaceName(model.NetworkId)
+	netNs, err := netns.GetFromName(netNsName)
+	if err != nil {
+		return fmt.Errorf("network namespace %q is missing", netNsName)
+	}
+	defer netNs.Close()
+
+	handle, err := netlink.NewHandleAt(netNs)
+	if err != nil {
+		return fmt.Errorf("failed to open the network's namespace: %w", err)
+	}
+	defer handle.Close()
+
+	bridgeName := b.GetBridgeName(model.Id)
+	bridge, err := handle.LinkByName(bridgeName)
+	if err != nil {
+		return fmt.Errorf("bridge %q is missing", bridgeName)
+	}
+
+	if bridge.Attrs().Flags&net.FlagUp == 0 {
+		return fmt.Errorf("bridge %q is down", bridgeName)
+	}
+
+	bridgeAddrs, err := handle.AddrList(bridge, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve IP addresses of the bridge: %w", err)
+	}
+
+	bridgeAddr := &netlink.Addr{
+		IPNet: b.ipamRepository.GetSubnetworkGateway(model),
+	}
+
+	if len(bridgeAddrs) != 1 || !bridgeAddr.Equal(bridgeAddrs[0]) {
+		return fmt.Errorf("bridge %q does not have exactly the gateway address %s", bridgeName, bridgeAddr.IPNet.String())
+	}
+
+	return nil
+}
+
+// Returns ids of all subnetworks that have a bridge in the namespace of the specified network
+func (b *bridgeConfigurator) ListConfigured(networkId uint32) ([]uint32, error) {
+	netNs, err := netns.GetFromName(b.getNetworkNamespaceName(networkId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the network namespace for the network: %w", err)
+	}
+	defer netNs.Close()
+
+	handle, err := netlink.NewHandleAt(netNs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the network's namespace: %w", err)
+	}
+	defer handle.Close()
+
+	links, err := handle.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links of the network's namespace: %w", err)
+	}
+
+	ids := make([]uint32, 0)
+	for _, link := range links {
+		if id, ok := shared.ParseIdSuffix(link.Attrs().Name, b.interfacePrefix+"br-"); ok && link.Type() == "bridge" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
 func (b *bridgeConfigurator) GetBridgeName(id uint32) string {
-	return fmt.Sprintf("bx2-br-%d", id)
+	return fmt.Sprintf("%sbr-%d", b.interfacePrefix, id)
 }
@@ -0,0 +1,43 @@
+package subnetwork
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BenasB/bx2cloud/internal/api/id"
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/persistence"
+)
+
+// Keeps subnetworks in memory and persists every change to a JSON file before acknowledging it
+func NewFileRepository(stateDir string) (interfaces.SubnetworkRepository, error) {
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the state directory: %w", err)
+	}
+
+	path := filepath.Join(stateDir, "subnetworks.json")
+	subnetworks, lastId, err := persistence.LoadCollection(path, func() *interfaces.SubnetworkModel {
+		return &interfaces.SubnetworkModel{}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subnetworks: %w", err)
+	}
+
+	for _, subnetwork := range subnetworks {
+		lastId = max(lastId, subnetwork.Id)
+		// Subnetworks persisted before resource versions were introduced
+		subnetwork.ResourceVersion = max(subnetwork.ResourceVersion, 1)
+	}
+	id.Restore("subnetwork", lastId)
+
+	return &memoryRepository{
+		subnetworks: subnetworks,
+		persist: func(subnetworks []*interfaces.SubnetworkModel) error {
+			for _, subnetwork := range subnetworks {
+				lastId = max(lastId, subnetwork.Id)
+			}
+			return persistence.SaveCollection(path, subnetworks, lastId)
+		},
+	}, nil
+}
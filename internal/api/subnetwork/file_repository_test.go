@@ -0,0 +1,62 @@
+package subnetwork_test
+
+import (
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestSubnetwork_FileRepository_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	repository, err := subnetwork.NewFileRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := repository.Add(&interfaces.SubnetworkModel{NetworkId: 1, Address: 0x0a000000, PrefixLength: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := repository.Add(&interfaces.SubnetworkModel{NetworkId: 1, Address: 0x0a000100, PrefixLength: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repository.Delete(deleted.Id, nil); err != nil {
+		t.Fatal(err)
+	}
+	kept, err = repository.Update(kept.Id, nil, func(sn *interfaces.SubnetworkModel) {
+		sn.PrefixLength = 16
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := subnetwork.NewFileRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := restarted.Get(kept.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(kept, got, protocmp.Transform()); diff != "" {
+		t.Errorf("subnetwork mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := restarted.Get(deleted.Id); err == nil {
+		t.Error("A deleted subnetwork was loaded after a restart")
+	}
+
+	added, err := restarted.Add(&interfaces.SubnetworkModel{NetworkId: 1, Address: 0x0a010000, PrefixLength: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Id <= deleted.Id {
+		t.Errorf("Id %d was handed out after a restart even though ids up to %d were already used", added.Id, deleted.Id)
+	}
+}
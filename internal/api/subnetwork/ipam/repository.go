@@ -1,17 +1,25 @@
 package ipam
 
 import (
-	"fmt"
 	"math"
 	"net"
+	"sync"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ErrNotAllocated     = apierrors.New(codes.FailedPrecondition, "subnetwork does not have this IP allocated")
+	ErrAlreadyAllocated = apierrors.New(codes.AlreadyExists, "subnetwork already has this IP allocated")
+	ErrOutOfRange       = apierrors.New(codes.OutOfRange, "IP is outside of bounds of the subnetwork")
 )
 
 var _ interfaces.IpamRepository = &memoryRepository{}
 
-// Caution: not thread safe
 type memoryRepository struct {
+	mu                    sync.Mutex
 	subnetworkAllocations map[uint32][]interfaces.IpamType
 	reservedIpCount       uint32
 }
@@ -24,6 +32,9 @@ func NewMemoryRepository() interfaces.IpamRepository {
 }
 
 func (r *memoryRepository) Allocate(subnetwork *interfaces.SubnetworkModel, resourceType interfaces.IpamType) (*net.IPNet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	allocations, exists := r.subnetworkAllocations[subnetwork.Id]
 
 	if !exists {
@@ -43,31 +54,59 @@ func (r *memoryRepository) Allocate(subnetwork *interfaces.SubnetworkModel, reso
 		}, nil
 	}
 
-	return nil, fmt.Errorf("subnetwork has run out of allocatable IPs")
+	return nil, apierrors.ResourceExhausted("subnetwork has run out of allocatable IPs")
 }
 
 func (r *memoryRepository) Deallocate(subnetwork *interfaces.SubnetworkModel, ip *net.IPNet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	allocations, exists := r.subnetworkAllocations[subnetwork.Id]
 
 	if !exists {
-		return fmt.Errorf("subnetwork does not have this IP allocated")
+		return ErrNotAllocated
 	}
 
-	address := uint32(ip.IP[0])<<24 | uint32(ip.IP[1])<<16 | uint32(ip.IP[2])<<8 | uint32(ip.IP[3])
-	i := address - subnetwork.Address - r.reservedIpCount - 1
-	if i < 0 || i >= uint32(len(allocations)) {
-		return fmt.Errorf("IP is outside of bounds of the subnetwork")
+	i, err := r.getAllocationIndex(subnetwork, allocations, ip)
+	if err != nil {
+		return err
 	}
 
 	if allocations[i] == interfaces.IPAM_UNALLOCATED {
-		return fmt.Errorf("subnetwork does not have this IP allocated")
+		return ErrNotAllocated
 	}
 
 	allocations[i] = interfaces.IPAM_UNALLOCATED
 	return nil
 }
 
+func (r *memoryRepository) Reserve(subnetwork *interfaces.SubnetworkModel, ip *net.IPNet, resourceType interfaces.IpamType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	allocations, exists := r.subnetworkAllocations[subnetwork.Id]
+
+	if !exists {
+		allocations = r.initSubnetworkAllocation(subnetwork)
+	}
+
+	i, err := r.getAllocationIndex(subnetwork, allocations, ip)
+	if err != nil {
+		return err
+	}
+
+	if allocations[i] != interfaces.IPAM_UNALLOCATED {
+		return ErrAlreadyAllocated
+	}
+
+	allocations[i] = resourceType
+	return nil
+}
+
 func (r *memoryRepository) HasAllocations(subnetwork *interfaces.SubnetworkModel) (interfaces.IpamType, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	allocations, exists := r.subnetworkAllocations[subnetwork.Id]
 
 	if !exists {
@@ -83,6 +122,25 @@ func (r *memoryRepository) HasAllocations(subnetwork *interfaces.SubnetworkModel
 	return interfaces.IPAM_UNALLOCATED, false
 }
 
+func (r *memoryRepository) CountAllocations(subnetwork *interfaces.SubnetworkModel) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, allocation := range r.subnetworkAllocations[subnetwork.Id] {
+		if allocation != interfaces.IPAM_UNALLOCATED {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r *memoryRepository) CountAllocatable(subnetwork *interfaces.SubnetworkModel) int {
+	noOfHosts := int(math.Pow(2, float64(32-subnetwork.PrefixLength))) - 2
+	return max(noOfHosts-int(r.reservedIpCount), 0)
+}
+
 func (r *memoryRepository) GetSubnetworkGateway(subnetwork *interfaces.SubnetworkModel) *net.IPNet {
 	ip := subnetwork.Address + 1
 
@@ -101,3 +159,18 @@ func (r *memoryRepository) initSubnetworkAllocation(subnetwork *interfaces.Subne
 	r.subnetworkAllocations[subnetwork.Id] = allocations
 	return allocations
 }
+
+func (r *memoryRepository) getAllocationIndex(subnetwork *interfaces.SubnetworkModel, allocations []interfaces.IpamType, ip *net.IPNet) (int, error) {
+	ip4 := ip.IP.To4()
+	if ip4 == nil {
+		return 0, ErrOutOfRange
+	}
+
+	address := uint32(ip4[0])<<24 | uint32(ip4[1])<<16 | uint32(ip4[2])<<8 | uint32(ip4[3])
+	i := int64(address) - int64(subnetwork.Address) - int64(r.reservedIpCount) - 1
+	if i < 0 || i >= int64(len(allocations)) {
+		return 0, ErrOutOfRange
+	}
+
+	return int(i), nil
+}
@@ -2,6 +2,7 @@ package ipam_test
 
 import (
 	"encoding/binary"
+	"errors"
 	"net"
 	"testing"
 
@@ -45,3 +46,68 @@ func TestIpam_Memory_Dogfood(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestIpam_Memory_Reserve(t *testing.T) {
+	repository := ipam.NewMemoryRepository()
+	subnetwork := &interfaces.SubnetworkModel{
+		Id:           1,
+		Address:      binary.BigEndian.Uint32([]byte{10, 0, 42, 0}),
+		PrefixLength: 24,
+	}
+
+	reserved := &net.IPNet{IP: net.IPv4(10, 0, 42, 2).To4(), Mask: net.CIDRMask(24, 32)}
+	if err := repository.Reserve(subnetwork, reserved, interfaces.IPAM_CONTAINER); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repository.Reserve(subnetwork, reserved, interfaces.IPAM_CONTAINER); !errors.Is(err, ipam.ErrAlreadyAllocated) {
+		t.Errorf("Reserving the same IP twice was expected to fail with %q, got: %v", ipam.ErrAlreadyAllocated, err)
+	}
+
+	ip, err := repository.Allocate(subnetwork, interfaces.IPAM_CONTAINER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip.IP.Equal(reserved.IP) {
+		t.Errorf("Allocate handed out the reserved IP %s", reserved.IP)
+	}
+}
+
+func TestIpam_Memory_Reserve_OutOfRange(t *testing.T) {
+	repository := ipam.NewMemoryRepository()
+	subnetwork := &interfaces.SubnetworkModel{
+		Id:           1,
+		Address:      binary.BigEndian.Uint32([]byte{10, 0, 42, 0}),
+		PrefixLength: 24,
+	}
+
+	for _, ip := range []net.IP{
+		net.IPv4(10, 0, 42, 1),   // gateway
+		net.IPv4(10, 0, 42, 255), // broadcast
+		net.IPv4(10, 0, 43, 2),
+	} {
+		t.Run(ip.String(), func(t *testing.T) {
+			err := repository.Reserve(subnetwork, &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(24, 32)}, interfaces.IPAM_CONTAINER)
+			if !errors.Is(err, ipam.ErrOutOfRange) {
+				t.Errorf("Reserving %s was expected to fail with %q, got: %v", ip, ipam.ErrOutOfRange, err)
+			}
+		})
+	}
+}
+
+func TestIpam_Memory_CountAllocatable(t *testing.T) {
+	repository := ipam.NewMemoryRepository()
+
+	// The network and broadcast addresses and the gateway can not be allocated
+	for prefixLength, want := range map[uint32]int{24: 253, 30: 1, 31: 0} {
+		subnetwork := &interfaces.SubnetworkModel{
+			Id:           1,
+			Address:      binary.BigEndian.Uint32([]byte{10, 0, 42, 0}),
+			PrefixLength: prefixLength,
+		}
+
+		if got := repository.CountAllocatable(subnetwork); got != want {
+			t.Errorf("expected a /%d to have %d allocatable IPs, got %d", prefixLength, want, got)
+		}
+	}
+}
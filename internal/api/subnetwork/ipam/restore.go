@@ -0,0 +1,94 @@
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+)
+
+// Marks the IPs of already existing containers as allocated, so that they are not handed out again.
+// Allocations that could not be restored are logged and returned instead of failing the whole restoration.
+func Restore(
+	ctx context.Context,
+	repository interfaces.IpamRepository,
+	containerRepository interfaces.ContainerRepository,
+	subnetworkRepository interfaces.SubnetworkRepository,
+) ([]*pb.IpamIssue, error) {
+	issues := make([]*pb.IpamIssue, 0)
+	containers, errs := containerRepository.GetAll(ctx)
+
+	restored := 0
+	for {
+		select {
+		case container, ok := <-containers:
+			if !ok {
+				select {
+				case err := <-errs:
+					if err != nil {
+						return nil, fmt.Errorf("failed to retrieve existing containers: %w", err)
+					}
+				default:
+				}
+
+				slog.InfoContext(ctx, "Restored container IP allocations", "restored", restored, "issues", len(issues))
+				return issues, nil
+			}
+
+			data := container.GetData()
+			issue := restoreContainer(repository, subnetworkRepository, data)
+			if issue == nil {
+				restored++
+				continue
+			}
+
+			slog.WarnContext(ctx, "Failed to restore the IP allocation of a container", "ip", data.Ip.String(), "container_id", data.Id, "issue", issue.Description)
+			issues = append(issues, issue)
+		case err, ok := <-errs:
+			if ok {
+				return nil, fmt.Errorf("failed to retrieve existing containers: %w", err)
+			}
+		}
+	}
+}
+
+func restoreContainer(repository interfaces.IpamRepository, subnetworkRepository interfaces.SubnetworkRepository, data *interfaces.ContainerModelData) *pb.IpamIssue {
+	prefixLength, _ := data.Ip.Mask.Size()
+	ip4 := data.Ip.IP.To4()
+	issue := &pb.IpamIssue{
+		ContainerId:  data.Id,
+		SubnetworkId: data.SubnetworkId,
+		PrefixLength: uint32(prefixLength),
+	}
+	if ip4 != nil {
+		issue.Address = uint32(ip4[0])<<24 | uint32(ip4[1])<<16 | uint32(ip4[2])<<8 | uint32(ip4[3])
+	}
+
+	subnetwork, err := subnetworkRepository.Get(data.SubnetworkId)
+	if err != nil {
+		issue.Description = fmt.Sprintf("the container's subnetwork could not be found: %v", err)
+		return issue
+	}
+
+	if uint32(prefixLength) != subnetwork.PrefixLength {
+		issue.Description = fmt.Sprintf("the container's prefix length /%d does not match the subnetwork's /%d", prefixLength, subnetwork.PrefixLength)
+		return issue
+	}
+
+	err = repository.Reserve(subnetwork, data.Ip, interfaces.IPAM_CONTAINER)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrAlreadyAllocated):
+		issue.Description = "the IP is already allocated to another resource"
+	case errors.Is(err, ErrOutOfRange):
+		issue.Description = "the IP is outside of the subnetwork's allocatable range"
+	default:
+		issue.Description = err.Error()
+	}
+
+	return issue
+}
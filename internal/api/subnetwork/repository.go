@@ -3,19 +3,26 @@ package subnetwork
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
 	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var _ interfaces.SubnetworkRepository = &memoryRepository{}
 
-// Caution: not thread safe
+// The slice is never modified in place, so readers can keep iterating over a snapshot of it without holding the lock
 type memoryRepository struct {
+	mu          sync.RWMutex
 	subnetworks []*interfaces.SubnetworkModel
+	// Called with the new state before a change is committed, the change is discarded if it fails
+	persist func(subnetworks []*interfaces.SubnetworkModel) error
 }
 
 func NewMemoryRepository(subnetworks []*interfaces.SubnetworkModel) interfaces.SubnetworkRepository {
@@ -26,30 +33,40 @@ func NewMemoryRepository(subnetworks []*interfaces.SubnetworkModel) interfaces.S
 
 	return &memoryRepository{
 		subnetworks: sns,
+		persist: func(subnetworks []*interfaces.SubnetworkModel) error {
+			return nil
+		},
 	}
 }
 
 func (r *memoryRepository) Get(id uint32) (*interfaces.SubnetworkModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, subnetwork := range r.subnetworks {
 		if subnetwork.Id == id {
-			return subnetwork, nil
+			return proto.Clone(subnetwork).(*interfaces.SubnetworkModel), nil
 		}
 	}
 
-	return nil, fmt.Errorf("could not find subnetwork with id %d", id)
+	return nil, apierrors.NotFound("could not find subnetwork with id %d", id)
 }
 
 func (r *memoryRepository) GetAll(ctx context.Context) (<-chan *interfaces.SubnetworkModel, <-chan error) {
 	results := make(chan *interfaces.SubnetworkModel, 0)
 	errChan := make(chan error, 1)
 
+	r.mu.RLock()
+	subnetworks := r.subnetworks
+	r.mu.RUnlock()
+
 	go func() {
 		defer close(results)
 		defer close(errChan)
 
-		for _, subnetwork := range r.subnetworks {
+		for _, subnetwork := range subnetworks {
 			select {
-			case results <- subnetwork:
+			case results <- proto.Clone(subnetwork).(*interfaces.SubnetworkModel):
 			case <-ctx.Done():
 				errChan <- ctx.Err()
 				return
@@ -64,11 +81,15 @@ func (r *memoryRepository) GetAllByNetworkId(id uint32, ctx context.Context) (<-
 	results := make(chan *interfaces.SubnetworkModel, 0)
 	errChan := make(chan error, 1)
 
+	r.mu.RLock()
+	subnetworks := r.subnetworks
+	r.mu.RUnlock()
+
 	go func() {
 		defer close(results)
 		defer close(errChan)
 
-		for _, subnetwork := range r.subnetworks {
+		for _, subnetwork := range subnetworks {
 			select {
 			case <-ctx.Done():
 				errChan <- ctx.Err()
@@ -81,7 +102,7 @@ func (r *memoryRepository) GetAllByNetworkId(id uint32, ctx context.Context) (<-
 			}
 
 			select {
-			case results <- subnetwork:
+			case results <- proto.Clone(subnetwork).(*interfaces.SubnetworkModel):
 			case <-ctx.Done():
 				errChan <- ctx.Err()
 				return
@@ -92,33 +113,144 @@ func (r *memoryRepository) GetAllByNetworkId(id uint32, ctx context.Context) (<-
 	return results, errChan
 }
 
+func (r *memoryRepository) List(ctx context.Context, filter *interfaces.SubnetworkFilter, options *listing.Options) ([]*interfaces.SubnetworkModel, bool, error) {
+	r.mu.RLock()
+	subnetworks := r.subnetworks
+	r.mu.RUnlock()
+
+	matching := make([]*interfaces.SubnetworkModel, 0)
+	for _, subnetwork := range subnetworks {
+		if subnetwork.ProjectId != filter.ProjectId {
+			continue
+		}
+		if filter.NetworkId != nil && subnetwork.NetworkId != *filter.NetworkId {
+			continue
+		}
+		if options.MatchesLabels(subnetwork.Labels) {
+			matching = append(matching, subnetwork)
+		}
+	}
+
+	page, more := listing.Page(matching, options, interfaces.SubnetworkOrderFields)
+	for i, subnetwork := range page {
+		page[i] = proto.Clone(subnetwork).(*interfaces.SubnetworkModel)
+	}
+
+	return page, more, ctx.Err()
+}
+
 func (r *memoryRepository) Add(subnetwork *interfaces.SubnetworkModel) (*interfaces.SubnetworkModel, error) {
-	//newSubnetwork := *subnetwork
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkNameAvailable(subnetwork.ProjectId, subnetwork.Name, 0); err != nil {
+		return nil, err
+	}
+
 	newSubnetwork := proto.Clone(subnetwork).(*interfaces.SubnetworkModel)
-	newSubnetwork.Id = id.NextId("subnetwork")
+	// The caller may have taken the id from id.NextId already, so that it can lock the id before the subnetwork can be seen
+	if newSubnetwork.Id == 0 {
+		newSubnetwork.Id = id.NextId("subnetwork")
+	} else if slices.ContainsFunc(r.subnetworks, func(existing *interfaces.SubnetworkModel) bool { return existing.Id == newSubnetwork.Id }) {
+		return nil, fmt.Errorf("subnetwork with id %d already exists", newSubnetwork.Id)
+	}
 	newSubnetwork.CreatedAt = timestamppb.New(time.Now())
-	r.subnetworks = append(r.subnetworks, newSubnetwork)
-	return newSubnetwork, nil
+	newSubnetwork.ResourceVersion = 1
+
+	subnetworks := append(slices.Clone(r.subnetworks), newSubnetwork)
+	if err := r.persist(subnetworks); err != nil {
+		return nil, fmt.Errorf("failed to persist the new subnetwork: %w", err)
+	}
+
+	r.subnetworks = subnetworks
+	return proto.Clone(newSubnetwork).(*interfaces.SubnetworkModel), nil
 }
 
-func (r *memoryRepository) Delete(id uint32) (*interfaces.SubnetworkModel, error) {
+func (r *memoryRepository) Delete(id uint32, expectedVersion *uint64) (*interfaces.SubnetworkModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i, subnetwork := range r.subnetworks {
-		if subnetwork.Id == id {
-			r.subnetworks = append(r.subnetworks[:i], r.subnetworks[i+1:]...)
-			return subnetwork, nil
+		if subnetwork.Id != id {
+			continue
+		}
+
+		if err := interfaces.CheckResourceVersion(expectedVersion, subnetwork.ResourceVersion); err != nil {
+			return nil, err
+		}
+
+		subnetworks := slices.Delete(slices.Clone(r.subnetworks), i, i+1)
+		if err := r.persist(subnetworks); err != nil {
+			return nil, fmt.Errorf("failed to persist the subnetwork deletion: %w", err)
 		}
+
+		r.subnetworks = subnetworks
+		return subnetwork, nil
 	}
 
-	return nil, fmt.Errorf("could not find subnetwork with id %d", id)
+	return nil, apierrors.NotFound("could not find subnetwork with id %d", id)
 }
 
-func (r *memoryRepository) Update(id uint32, updateFn func(*interfaces.SubnetworkModel)) (*interfaces.SubnetworkModel, error) {
+func (r *memoryRepository) Update(id uint32, expectedVersion *uint64, updateFn func(*interfaces.SubnetworkModel)) (*interfaces.SubnetworkModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, subnetwork := range r.subnetworks {
+		if subnetwork.Id != id {
+			continue
+		}
+
+		if err := interfaces.CheckResourceVersion(expectedVersion, subnetwork.ResourceVersion); err != nil {
+			return nil, err
+		}
+
+		updated := proto.Clone(subnetwork).(*interfaces.SubnetworkModel)
+		updateFn(updated)
+		updated.Id = subnetwork.Id
+		updated.ProjectId = subnetwork.ProjectId
+		updated.CreatedBy = subnetwork.CreatedBy
+		if err := r.checkNameAvailable(updated.ProjectId, updated.Name, subnetwork.Id); err != nil {
+			return nil, err
+		}
+		updated.ResourceVersion = subnetwork.ResourceVersion + 1
+
+		subnetworks := slices.Clone(r.subnetworks)
+		subnetworks[i] = updated
+		if err := r.persist(subnetworks); err != nil {
+			return nil, fmt.Errorf("failed to persist the subnetwork update: %w", err)
+		}
+
+		r.subnetworks = subnetworks
+		return proto.Clone(updated).(*interfaces.SubnetworkModel), nil
+	}
+
+	return nil, apierrors.NotFound("could not find subnetwork with id %d", id)
+}
+
+func (r *memoryRepository) GetByName(projectId uint32, name string) (*interfaces.SubnetworkModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, subnetwork := range r.subnetworks {
-		if subnetwork.Id == id {
-			updateFn(subnetwork)
-			return subnetwork, nil
+		if name != "" && subnetwork.ProjectId == projectId && subnetwork.Name == name {
+			return proto.Clone(subnetwork).(*interfaces.SubnetworkModel), nil
+		}
+	}
+
+	return nil, apierrors.NotFound("could not find subnetwork with name %q", name)
+}
+
+// Must be called with the lock held, the subnetwork with exceptId is allowed to keep its own name
+func (r *memoryRepository) checkNameAvailable(projectId uint32, name string, exceptId uint32) error {
+	if err := interfaces.ValidateName(name); err != nil {
+		return err
+	}
+
+	for _, subnetwork := range r.subnetworks {
+		if name != "" && subnetwork.ProjectId == projectId && subnetwork.Name == name && subnetwork.Id != exceptId {
+			return fmt.Errorf("%w: subnetwork %d is already named %q", interfaces.ErrNameTaken, subnetwork.Id, name)
 		}
 	}
 
-	return nil, fmt.Errorf("could not find subnetwork with id %d", id)
+	return nil
 }
@@ -3,11 +3,19 @@ package subnetwork
 import (
 	"context"
 	"encoding/binary"
-	"fmt"
 	"net"
+	"slices"
 
+	"github.com/BenasB/bx2cloud/internal/api/apierrors"
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/api/events"
+	"github.com/BenasB/bx2cloud/internal/api/id"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/labels"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/project"
+	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -18,6 +26,9 @@ type service struct {
 	networkRepository interfaces.NetworkRepository
 	configurator      configurator
 	ipamRepository    interfaces.IpamRepository
+	quotas            interfaces.QuotaChecker
+	idempotency       interfaces.IdempotencyTracker
+	events            *events.Broker[*pb.Subnetwork]
 }
 
 func NewService(
@@ -25,122 +36,142 @@ func NewService(
 	networkRepository interfaces.NetworkRepository,
 	configurator configurator,
 	ipamRepository interfaces.IpamRepository,
+	quotas interfaces.QuotaChecker,
+	idempotency interfaces.IdempotencyTracker,
 ) *service {
 	return &service{
 		repository:        subnetworkRepository,
 		networkRepository: networkRepository,
 		configurator:      configurator,
 		ipamRepository:    ipamRepository,
+		quotas:            quotas,
+		idempotency:       idempotency,
+		events:            events.NewBroker[*pb.Subnetwork](events.DEFAULT_HISTORY_SIZE),
 	}
 }
 
 func (s *service) Get(ctx context.Context, req *pb.SubnetworkIdentificationRequest) (*pb.Subnetwork, error) {
-	return s.repository.Get(req.Id)
+	return s.resolve(ctx, req)
 }
 
 func (s *service) Delete(ctx context.Context, req *pb.SubnetworkIdentificationRequest) (*emptypb.Empty, error) {
-	subnetwork, err := s.repository.Get(req.Id)
+	subnetwork, err := s.resolve(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	defer shared.SubnetworkLocks.Lock(subnetwork.Id)()
+
 	if alloc, found := s.ipamRepository.HasAllocations(subnetwork); found {
 		switch alloc {
 		case interfaces.IPAM_CONTAINER:
-			return nil, fmt.Errorf("the subnetwork still has an IP allocated for a container")
+			return nil, apierrors.FailedPrecondition("the subnetwork still has an IP allocated for a container")
 		default:
-			return nil, fmt.Errorf("the subnetwork still has an IP allocated for a resource")
+			return nil, apierrors.FailedPrecondition("the subnetwork still has an IP allocated for a resource")
 		}
 	}
 
-	_, err = s.repository.Delete(subnetwork.Id)
+	deleted, err := s.repository.Delete(subnetwork.Id, req.ResourceVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.configurator.Unconfigure(subnetwork); err != nil {
+	if err := s.configurator.Unconfigure(ctx, subnetwork); err != nil {
 		return nil, err
 	}
 
+	s.events.Publish(pb.EventType_DELETED, deleted)
+
 	return &emptypb.Empty{}, nil
 }
 
 func (s *service) Create(ctx context.Context, req *pb.SubnetworkCreationRequest) (*pb.Subnetwork, error) {
-	if _, err := s.networkRepository.Get(req.NetworkId); err != nil {
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	newSubnetwork := &interfaces.SubnetworkModel{
-		NetworkId:    req.NetworkId,
-		Address:      req.Address, // TODO: #1 AND address with network mask to make sure this stores the network IP + unit test
-		PrefixLength: req.PrefixLength,
-	}
-
-	subnetworks, errors := s.repository.GetAllByNetworkId(req.NetworkId, ctx)
-
-	err := func() error {
-		for {
-			select {
-			case subnetwork, ok := <-subnetworks:
-				if !ok {
-					select {
-					case err := <-errors:
-						return err
-					default:
-						return nil
-					}
-				} else {
-					minPrefixLength := min(newSubnetwork.PrefixLength, subnetwork.PrefixLength)
-					minMask := binary.BigEndian.Uint32(net.CIDRMask(int(minPrefixLength), 32))
-					a := newSubnetwork.Address & minMask
-					b := subnetwork.Address & minMask
-					if a == b {
-						return fmt.Errorf("new subnetwork would overlap with subnetwork %d", subnetwork.Id)
-					}
-				}
-			case err, ok := <-errors:
-				if ok {
-					return err
-				}
-			}
-		}
-	}()
-
+	releaseKey, err := s.idempotency.Claim(ctx, projectId, interfaces.IDEMPOTENCY_SUBNETWORKS, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseKey()
 
-	returnedSubnetwork, err := s.repository.Add(newSubnetwork)
+	if existing, err := s.createdWithKey(ctx, projectId, req.IdempotencyKey); err != nil || existing != nil {
+		return existing, err
+	}
+
+	network, err := s.networkRepository.Get(req.NetworkId)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.configurator.Configure(returnedSubnetwork); err != nil {
+	// Subnetworks can only be added to networks of the caller's project, other networks do not exist for the caller
+	if network.ProjectId != projectId {
+		return nil, apierrors.NotFound("could not find network with id %d", req.NetworkId)
+	}
+
+	if err := labels.Validate(req.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	createdBy := auth.Identity(ctx)
+	release, err := s.quotas.Reserve(ctx, createdBy, interfaces.QUOTA_SUBNETWORKS)
+	if err != nil {
 		return nil, err
 	}
+	defer release()
 
-	return returnedSubnetwork, nil
-}
+	subnetworkId := id.NextId("subnetwork")
 
-func (s *service) Update(ctx context.Context, req *pb.SubnetworkUpdateRequest) (*pb.Subnetwork, error) {
-	subnetwork, err := s.repository.Update(req.Identification.Id, func(sn *interfaces.SubnetworkModel) {
-		sn.Address = req.Update.Address
-		sn.PrefixLength = req.Update.PrefixLength
-	})
+	// Held until the subnetwork is configured, before it can be seen in the repository
+	defer shared.SubnetworkLocks.Lock(subnetworkId)()
+
+	newSubnetwork := &interfaces.SubnetworkModel{
+		Id:             subnetworkId,
+		NetworkId:      req.NetworkId,
+		Address:        req.Address, // TODO: #1 AND address with network mask to make sure this stores the network IP + unit test
+		PrefixLength:   req.PrefixLength,
+		Labels:         req.Labels,
+		Name:           req.Name,
+		ProjectId:      projectId,
+		IdempotencyKey: req.IdempotencyKey,
+		CreatedBy:      createdBy,
+	}
+
+	// Checking for overlaps and adding the subnetwork are one step in each network, so that concurrent creations can not overlap.
+	// Holding the network's lock also keeps the network from being deleted in between.
+	returnedSubnetwork, err := func() (*interfaces.SubnetworkModel, error) {
+		defer shared.NetworkLocks.Lock(req.NetworkId)()
 
+		if _, err := s.networkRepository.Get(req.NetworkId); err != nil {
+			return nil, err
+		}
+
+		if err := s.checkOverlap(ctx, newSubnetwork); err != nil {
+			return nil, err
+		}
+
+		return s.repository.Add(newSubnetwork)
+	}()
 	if err != nil {
 		return nil, err
 	}
+	// The subnetwork is counted from the repository now
+	release()
 
-	if err := s.configurator.Configure(subnetwork); err != nil {
+	if err := s.configurator.Configure(ctx, returnedSubnetwork); err != nil {
 		return nil, err
 	}
 
-	return subnetwork, nil
+	s.events.Publish(pb.EventType_ADDED, returnedSubnetwork)
+
+	return returnedSubnetwork, nil
 }
 
-func (s *service) List(req *emptypb.Empty, stream grpc.ServerStreamingServer[pb.Subnetwork]) error {
-	subnetworks, errors := s.repository.GetAll(stream.Context())
+// Fails if the subnetwork would overlap with another subnetwork of its network
+func (s *service) checkOverlap(ctx context.Context, newSubnetwork *interfaces.SubnetworkModel) error {
+	subnetworks, errors := s.repository.GetAllByNetworkId(newSubnetwork.NetworkId, ctx)
 
 	for {
 		select {
@@ -152,9 +183,14 @@ func (s *service) List(req *emptypb.Empty, stream grpc.ServerStreamingServer[pb.
 				default:
 					return nil
 				}
-			}
-			if err := stream.Send(subnetwork); err != nil {
-				return err
+			} else {
+				minPrefixLength := min(newSubnetwork.PrefixLength, subnetwork.PrefixLength)
+				minMask := binary.BigEndian.Uint32(net.CIDRMask(int(minPrefixLength), 32))
+				a := newSubnetwork.Address & minMask
+				b := subnetwork.Address & minMask
+				if a == b {
+					return apierrors.FailedPrecondition("new subnetwork would overlap with subnetwork %d", subnetwork.Id)
+				}
 			}
 		case err, ok := <-errors:
 			if ok {
@@ -163,3 +199,137 @@ func (s *service) List(req *emptypb.Empty, stream grpc.ServerStreamingServer[pb.
 		}
 	}
 }
+
+func (s *service) Update(ctx context.Context, req *pb.SubnetworkUpdateRequest) (*pb.Subnetwork, error) {
+	if err := labels.Validate(req.Update.Labels); err != nil {
+		return nil, apierrors.InvalidArgument("labels", err)
+	}
+
+	existing, err := s.resolve(ctx, req.Identification)
+	if err != nil {
+		return nil, err
+	}
+
+	defer shared.SubnetworkLocks.Lock(existing.Id)()
+
+	subnetwork, err := s.repository.Update(existing.Id, req.Identification.ResourceVersion, func(sn *interfaces.SubnetworkModel) {
+		sn.Address = req.Update.Address
+		sn.PrefixLength = req.Update.PrefixLength
+		sn.Labels = req.Update.Labels
+		sn.Name = req.Update.Name
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.configurator.Configure(ctx, subnetwork); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(pb.EventType_MODIFIED, subnetwork)
+
+	return subnetwork, nil
+}
+
+func (s *service) List(req *pb.ListRequest, stream grpc.ServerStreamingServer[pb.Subnetwork]) error {
+	if err := listing.CheckFilters(req, "subnetworks", "network_id"); err != nil {
+		return err
+	}
+
+	options, err := listing.FromRequest(req, interfaces.SubnetworkOrderFields)
+	if err != nil {
+		return err
+	}
+
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	filter := &interfaces.SubnetworkFilter{
+		ProjectId: projectId,
+		NetworkId: req.NetworkId,
+	}
+
+	subnetworks, more, err := s.repository.List(stream.Context(), filter, options)
+	if err != nil {
+		return err
+	}
+
+	for _, subnetwork := range subnetworks {
+		if err := stream.Send(subnetwork); err != nil {
+			return err
+		}
+	}
+
+	listing.SetNextPageToken(stream, options.NextPageToken(more))
+	return nil
+}
+
+func (s *service) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.SubnetworkEvent]) error {
+	projectId, err := project.CallerId(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	return events.Serve(stream.Context(), s.events, req.SinceRevision,
+		func() ([]*pb.Subnetwork, error) {
+			subnetworks, err := shared.CollectAll(s.repository.GetAll(stream.Context()))
+			return slices.DeleteFunc(subnetworks, func(s *pb.Subnetwork) bool { return s.ProjectId != projectId }), err
+		},
+		func(event events.Event[*pb.Subnetwork]) error {
+			if event.Object.ProjectId != projectId {
+				return nil
+			}
+			return stream.Send(&pb.SubnetworkEvent{
+				Type:       event.Type,
+				Revision:   event.Revision,
+				Subnetwork: event.Object,
+			})
+		},
+	)
+}
+
+// Subnetworks can be identified either by id or by name, subnetworks of other projects are not found
+func (s *service) resolve(ctx context.Context, req *pb.SubnetworkIdentificationRequest) (*interfaces.SubnetworkModel, error) {
+	projectId, err := project.CallerId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := req.Identifier.(*pb.SubnetworkIdentificationRequest_Name); ok {
+		return s.repository.GetByName(projectId, req.GetName())
+	}
+
+	subnetwork, err := s.repository.Get(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if subnetwork.ProjectId != projectId {
+		return nil, apierrors.NotFound("could not find subnetwork with id %d", req.GetId())
+	}
+
+	return subnetwork, nil
+}
+
+// Returns the subnetwork that an earlier request with the same idempotency key created, or nil if there is none
+func (s *service) createdWithKey(ctx context.Context, projectId uint32, key string) (*interfaces.SubnetworkModel, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	subnetworks, err := shared.CollectAll(s.repository.GetAll(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subnetwork := range subnetworks {
+		if subnetwork.ProjectId == projectId && subnetwork.IdempotencyKey == key && s.idempotency.Retains(subnetwork.CreatedAt.AsTime()) {
+			return subnetwork, nil
+		}
+	}
+
+	return nil, nil
+}
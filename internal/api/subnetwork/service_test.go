@@ -6,18 +6,23 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/BenasB/bx2cloud/internal/api/idempotency"
 	"github.com/BenasB/bx2cloud/internal/api/interfaces"
+	"github.com/BenasB/bx2cloud/internal/api/listing"
 	"github.com/BenasB/bx2cloud/internal/api/network"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/api/quota"
 	"github.com/BenasB/bx2cloud/internal/api/shared"
 	"github.com/BenasB/bx2cloud/internal/api/subnetwork"
 	"github.com/BenasB/bx2cloud/internal/api/subnetwork/ipam"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
-	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -52,7 +57,7 @@ func TestSubnetwork_Create(t *testing.T) {
 	repository := subnetwork.NewMemoryRepository(nil)
 	networkRepository := network.NewMemoryRepository(testNetworks)
 	ipamRepository := ipam.NewMemoryRepository()
-	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 	req := &pb.SubnetworkCreationRequest{
 		NetworkId:    testNetworks[0].Id,
@@ -70,7 +75,7 @@ func TestSubnetwork_Create_NetworkNotFound(t *testing.T) {
 	repository := subnetwork.NewMemoryRepository(nil)
 	networkRepository := network.NewMemoryRepository(nil)
 	ipamRepository := ipam.NewMemoryRepository()
-	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 	req := &pb.SubnetworkCreationRequest{
 		NetworkId:    0,
 		Address:      binary.BigEndian.Uint32([]byte{192, 168, 0, 0}),
@@ -124,7 +129,7 @@ func TestSubnetwork_Create_Overlap(t *testing.T) {
 		repository := subnetwork.NewMemoryRepository([]*interfaces.SubnetworkModel{existingSubnetwork})
 		networkRepository := network.NewMemoryRepository(testNetworks)
 		ipamRepository := ipam.NewMemoryRepository()
-		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(fmt.Sprintf("%s:%s", tt.existing.String(), tt.new.String()), func(t *testing.T) {
 			newPrefixLength, _ := tt.existing.Mask.Size()
@@ -144,6 +149,64 @@ func TestSubnetwork_Create_Overlap(t *testing.T) {
 	}
 }
 
+// Blocks the first added subnetwork until released, the ones after it are added right away
+type blockingAddRepository struct {
+	interfaces.SubnetworkRepository
+	adding  chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (r *blockingAddRepository) Add(subnetwork *interfaces.SubnetworkModel) (*interfaces.SubnetworkModel, error) {
+	r.once.Do(func() {
+		r.adding <- struct{}{}
+		<-r.release
+	})
+	return r.SubnetworkRepository.Add(subnetwork)
+}
+
+func TestSubnetwork_Create_ConcurrentOverlap(t *testing.T) {
+	repository := &blockingAddRepository{
+		SubnetworkRepository: subnetwork.NewMemoryRepository(nil),
+		adding:               make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	networkRepository := network.NewMemoryRepository(testNetworks)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipam.NewMemoryRepository(), quota.NewMockChecker(), idempotency.NewMockTracker())
+	req := &pb.SubnetworkCreationRequest{
+		NetworkId:    testNetworks[0].Id,
+		Address:      binary.BigEndian.Uint32([]byte{192, 168, 0, 0}),
+		PrefixLength: 24,
+	}
+
+	results := make(chan error, 2)
+	go func() {
+		_, err := service.Create(t.Context(), req)
+		results <- err
+	}()
+
+	// The first subnetwork passed the overlap check, but is not in the repository yet
+	<-repository.adding
+
+	go func() {
+		_, err := service.Create(t.Context(), req)
+		results <- err
+	}()
+	close(repository.release)
+
+	failed := 0
+	for range 2 {
+		if err := <-results; status.Code(err) == codes.FailedPrecondition {
+			failed++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("Expected exactly one of the overlapping subnetworks to be rejected, %d were", failed)
+	}
+}
+
 func TestSubnetwork_Create_NonOverlap(t *testing.T) {
 	existingSubnetwork := &interfaces.SubnetworkModel{
 		Id:           1,
@@ -155,7 +218,7 @@ func TestSubnetwork_Create_NonOverlap(t *testing.T) {
 	repository := subnetwork.NewMemoryRepository([]*interfaces.SubnetworkModel{existingSubnetwork})
 	networkRepository := network.NewMemoryRepository(testNetworks)
 	ipamRepository := ipam.NewMemoryRepository()
-	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 	req := &pb.SubnetworkCreationRequest{
 		NetworkId:    testNetworks[0].Id,
@@ -174,11 +237,11 @@ func TestSubnetwork_Delete(t *testing.T) {
 		repository := subnetwork.NewMemoryRepository(testSubnetworks)
 		networkRepository := network.NewMemoryRepository(testNetworks)
 		ipamRepository := ipam.NewMemoryRepository()
-		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(strconv.FormatUint(uint64(tt.Id), 10), func(t *testing.T) {
 			_, err := service.Delete(t.Context(), &pb.SubnetworkIdentificationRequest{
-				Id: tt.Id,
+				Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: tt.Id},
 			})
 			if err != nil {
 				t.Error(err)
@@ -199,9 +262,9 @@ func TestSubnetwork_Delete_StillAllocated(t *testing.T) {
 		t.Error(err)
 	}
 
-	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 	_, err = service.Delete(t.Context(), &pb.SubnetworkIdentificationRequest{
-		Id: sn.Id,
+		Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: sn.Id},
 	})
 	if err == nil {
 		t.Error("Subnetwork was deleted even though it had at least 1 resource IP allocated")
@@ -215,11 +278,11 @@ func TestSubnetwork_Get(t *testing.T) {
 		repository := subnetwork.NewMemoryRepository(testSubnetworks)
 		networkRepository := network.NewMemoryRepository(nil)
 		ipamRepository := ipam.NewMemoryRepository()
-		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
+		service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
 
 		t.Run(strconv.FormatUint(uint64(tt.Id), 10), func(t *testing.T) {
 			resp, err := service.Get(t.Context(), &pb.SubnetworkIdentificationRequest{
-				Id: tt.Id,
+				Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: tt.Id},
 			})
 			if err != nil {
 				t.Error(err)
@@ -237,8 +300,8 @@ func TestSubnetwork_List(t *testing.T) {
 	repository := subnetwork.NewMemoryRepository(testSubnetworks)
 	networkRepository := network.NewMemoryRepository(nil)
 	ipamRepository := ipam.NewMemoryRepository()
-	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository)
-	service.List(&emptypb.Empty{}, stream)
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
+	service.List(&pb.ListRequest{}, stream)
 
 	if len(testSubnetworks) != len(stream.SentItems) {
 		t.Error("not the same amount of subnetworks received")
@@ -250,3 +313,46 @@ func TestSubnetwork_List(t *testing.T) {
 		}
 	}
 }
+
+func TestSubnetwork_List_Pagination(t *testing.T) {
+	repository := subnetwork.NewMemoryRepository(testSubnetworks)
+	networkRepository := network.NewMemoryRepository(nil)
+	ipamRepository := ipam.NewMemoryRepository()
+	service := subnetwork.NewService(repository, networkRepository, mockConfigurator, ipamRepository, quota.NewMockChecker(), idempotency.NewMockTracker())
+
+	req := &pb.ListRequest{
+		PageSize:  1,
+		OrderBy:   "created_at desc",
+		NetworkId: &testNetworks[0].Id,
+	}
+
+	ids := make([]uint32, 0)
+	for range len(testSubnetworks) + 1 {
+		stream := shared.NewMockStream[*interfaces.SubnetworkModel](t.Context())
+		if err := service.List(req, stream); err != nil {
+			t.Fatal(err)
+		}
+		for _, sn := range stream.SentItems {
+			ids = append(ids, sn.Id)
+		}
+
+		tokens := stream.Trailer.Get(listing.NEXT_PAGE_TOKEN_KEY)
+		if len(tokens) != 1 {
+			t.Fatalf("Expected a single page token in the trailer, got %v", tokens)
+		}
+		if tokens[0] == "" {
+			break
+		}
+		req.PageToken = tokens[0]
+	}
+
+	if diff := cmp.Diff([]uint32{2, 1}, ids); diff != "" {
+		t.Errorf("listed subnetworks mismatch (-want +got):\n%s", diff)
+	}
+
+	req.OrderBy = "id"
+	stream := shared.NewMockStream[*interfaces.SubnetworkModel](t.Context())
+	if err := service.List(req, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected %v for a page token of a different ordering, got %v", codes.InvalidArgument, err)
+	}
+}
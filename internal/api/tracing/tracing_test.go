@@ -0,0 +1,38 @@
+package tracing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartEnd(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	ctx, parent := tracing.Start(t.Context(), "parent")
+	_, child := tracing.Start(ctx, "child")
+	tracing.End(child, errors.New("registry unreachable"))
+	tracing.End(parent, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Parent.SpanID() != parentSpan.SpanContext.SpanID() {
+		t.Error("expected the child span to be a child of the parent span")
+	}
+	if childSpan.Status.Code != codes.Error || childSpan.Status.Description != "registry unreachable" {
+		t.Errorf("expected the child span to have failed, got %v", childSpan.Status)
+	}
+	if parentSpan.Status.Code != codes.Unset {
+		t.Errorf("expected the parent span to have no status, got %v", parentSpan.Status)
+	}
+}
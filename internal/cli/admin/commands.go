@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"flag"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"github.com/BenasB/bx2cloud/internal/cli/exits"
+	"google.golang.org/grpc"
+)
+
+var flags = struct {
+	dryRun bool
+}{
+	dryRun: false,
+}
+
+var Commands = []*common.CliCommand{
+	common.NewCliSubcommand(
+		"admin",
+		[]*common.CliCommand{
+			common.NewCliCommandWithFlags(
+				"gc",
+				"Finds and removes host resources (namespaces, links, rootfs, logs) that have no owning resource",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewAdminServiceClient(conn)
+					if err := CollectGarbage(client, flags.dryRun); err != nil {
+						return exits.ADMIN_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					fs.BoolVar(&flags.dryRun, "dry-run", flags.dryRun, "only report orphans without removing them")
+				},
+			),
+		},
+	),
+}
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+)
+
+func CollectGarbage(client pb.AdminServiceClient, dryRun bool) error {
+	resp, err := client.CollectGarbage(context.Background(), &pb.GarbageCollectionRequest{
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Orphans) == 0 {
+		fmt.Printf("No orphans found\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "kind\tid\tlocation\tremoved\terror\n")
+	for _, orphan := range resp.Orphans {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%t\t%s\n", orphan.Kind, orphan.Id, orphan.Location, orphan.Removed, orphan.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package apply
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"github.com/BenasB/bx2cloud/internal/cli/exits"
+	"google.golang.org/grpc"
+)
+
+var flags = struct {
+	file     string
+	manifest string
+	prune    bool
+	dryRun   bool
+}{
+	file:     "",
+	manifest: "",
+	prune:    false,
+	dryRun:   false,
+}
+
+var Commands = []*common.CliCommand{
+	common.NewCliCommandWithFlags(
+		"apply",
+		"Creates, updates, replaces and optionally deletes resources to match a manifest",
+		"",
+		func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+			client := pb.NewApplyServiceClient(conn)
+
+			if flags.file == "" {
+				return exits.MISSING_ARGUMENT, fmt.Errorf("missing the manifest file, set it with -f")
+			}
+
+			var yamlBytes []byte
+			var err error
+			if flags.file == "-" {
+				yamlBytes, err = io.ReadAll(os.Stdin)
+			} else {
+				yamlBytes, err = os.ReadFile(flags.file)
+			}
+			if err != nil {
+				return exits.APPLY_ERROR, err
+			}
+
+			if err := Apply(client, yamlBytes, manifestName(), flags.prune, flags.dryRun); err != nil {
+				return exits.APPLY_ERROR, err
+			}
+			return exits.SUCCESS, nil
+		},
+		func(fs *flag.FlagSet) {
+			fs.StringVar(&flags.file, "f", flags.file, "YAML file with one resource per document, - reads from stdin")
+			fs.StringVar(&flags.manifest, "manifest", flags.manifest, "name the applied resources are labeled with, the file name without its extension if empty")
+			fs.BoolVar(&flags.prune, "prune", flags.prune, "delete resources that were applied with the same manifest name but are no longer in it")
+			fs.BoolVar(&flags.dryRun, "dry-run", flags.dryRun, "only show the changes without making them")
+		},
+	),
+}
+
+func manifestName() string {
+	if flags.manifest != "" || flags.file == "-" {
+		return flags.manifest
+	}
+	return strings.TrimSuffix(filepath.Base(flags.file), filepath.Ext(flags.file))
+}
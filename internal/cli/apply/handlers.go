@@ -0,0 +1,147 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+var actionSymbols = map[pb.ChangeAction]string{
+	pb.ChangeAction_CREATE:  "+",
+	pb.ChangeAction_UPDATE:  "~",
+	pb.ChangeAction_REPLACE: "-/+",
+	pb.ChangeAction_DELETE:  "-",
+}
+
+func Apply(client pb.ApplyServiceClient, yamlBytes []byte, manifest string, prune bool, dryRun bool) error {
+	resources, err := parseManifest(yamlBytes)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Apply(context.Background(), &pb.ApplyRequest{
+		Resources: resources,
+		Manifest:  manifest,
+		Prune:     prune,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		// Applying is not atomic, the changes made before the failure stay applied
+		for _, detail := range status.Convert(err).Details() {
+			if applied, ok := detail.(*pb.ApplyResponse); ok && len(applied.Changes) > 0 {
+				fmt.Fprintf(os.Stdout, "Applied before the failure:\n")
+				printChangeList(os.Stdout, applied.Changes)
+			}
+		}
+		return err
+	}
+
+	printChanges(os.Stdout, resp)
+
+	return nil
+}
+
+// Reads every document of a multi-document YAML file, empty documents are skipped
+func parseManifest(yamlBytes []byte) ([]*pb.ManifestResource, error) {
+	resources := make([]*pb.ManifestResource, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlBytes))
+	for i := 1; ; i++ {
+		var document *manifestDocument
+		if err := decoder.Decode(&document); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", i, err)
+		}
+		if document == nil {
+			continue
+		}
+
+		if err := document.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid document %d: %w", i, err)
+		}
+
+		resource, err := document.resource()
+		if err != nil {
+			return nil, fmt.Errorf("invalid document %d: %w", i, err)
+		}
+		resources = append(resources, resource)
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("the manifest does not have any resources")
+	}
+
+	return resources, nil
+}
+
+// Unchanged resources are only counted
+func printChanges(w io.Writer, resp *pb.ApplyResponse) {
+	counts := make(map[pb.ChangeAction]int)
+	for _, change := range resp.Changes {
+		counts[change.Action]++
+	}
+	printChangeList(w, resp.Changes)
+
+	summary := make([]string, 0)
+	for _, item := range []struct {
+		action pb.ChangeAction
+		label  string
+	}{
+		{pb.ChangeAction_CREATE, "to create"},
+		{pb.ChangeAction_UPDATE, "to update"},
+		{pb.ChangeAction_REPLACE, "to replace"},
+		{pb.ChangeAction_DELETE, "to delete"},
+		{pb.ChangeAction_UNCHANGED, "unchanged"},
+	} {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[item.action], item.label))
+	}
+	fmt.Fprintf(w, "Plan: %s\n", strings.Join(summary, ", "))
+
+	if resp.Applied {
+		fmt.Fprintf(w, "Successfully applied\n")
+	} else {
+		fmt.Fprintf(w, "Dry run, nothing was changed\n")
+	}
+}
+
+// Unchanged resources are skipped
+func printChangeList(w io.Writer, changes []*pb.Change) {
+	for _, change := range changes {
+		symbol, ok := actionSymbols[change.Action]
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s %s %q", symbol, change.Kind, change.Name)
+		if change.Id != 0 {
+			fmt.Fprintf(w, " (id %d)", change.Id)
+		}
+		if change.Reason != "" {
+			fmt.Fprintf(w, ": %s", change.Reason)
+		}
+		fmt.Fprintf(w, "\n")
+
+		for _, field := range change.Fields {
+			if change.Action == pb.ChangeAction_CREATE {
+				fmt.Fprintf(w, "    %s: %s\n", field.Field, orNone(field.Desired))
+			} else {
+				fmt.Fprintf(w, "    %s: %s -> %s\n", field.Field, orNone(field.Current), orNone(field.Desired))
+			}
+		}
+	}
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+package apply
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/inputs"
+)
+
+const (
+	KIND_NETWORK    = "network"
+	KIND_SUBNETWORK = "subnetwork"
+	KIND_CONTAINER  = "container"
+)
+
+var _ inputs.Input = &manifestDocument{}
+
+// A single resource of a manifest, other resources are referred to by name
+type manifestDocument struct {
+	Kind           string            `yaml:"kind"`
+	Name           string            `yaml:"name"`
+	InternetAccess bool              `yaml:"internetAccess"`
+	Network        string            `yaml:"network"`
+	Cidr           string            `yaml:"cidr"`
+	Subnetwork     string            `yaml:"subnetwork"`
+	Image          string            `yaml:"image"`
+	Entrypoint     []string          `yaml:"entrypoint"`
+	Cmd            []string          `yaml:"cmd"`
+	Env            []string          `yaml:"env"`
+	Labels         map[string]string `yaml:"labels"`
+}
+
+func (i *manifestDocument) Validate() error {
+	if i.Name == "" {
+		return fmt.Errorf("missing required field: name")
+	}
+
+	switch i.Kind {
+	case KIND_NETWORK:
+	case KIND_SUBNETWORK:
+		if i.Network == "" {
+			return fmt.Errorf("missing required field: network")
+		}
+		if i.Cidr == "" {
+			return fmt.Errorf("missing required field: cidr")
+		}
+		if _, _, err := net.ParseCIDR(i.Cidr); err != nil {
+			return fmt.Errorf("Could not parse CIDR: %v", err)
+		}
+	case KIND_CONTAINER:
+		if i.Subnetwork == "" {
+			return fmt.Errorf("missing required field: subnetwork")
+		}
+		if i.Image == "" {
+			return fmt.Errorf("missing required field: image")
+		}
+	case "":
+		return fmt.Errorf("missing required field: kind")
+	default:
+		return fmt.Errorf("unknown kind %q, expected one of %s, %s, %s", i.Kind, KIND_NETWORK, KIND_SUBNETWORK, KIND_CONTAINER)
+	}
+
+	return nil
+}
+
+func (i *manifestDocument) resource() (*pb.ManifestResource, error) {
+	switch i.Kind {
+	case KIND_NETWORK:
+		return &pb.ManifestResource{Resource: &pb.ManifestResource_Network{Network: &pb.ManifestNetwork{
+			Name:           i.Name,
+			InternetAccess: i.InternetAccess,
+			Labels:         i.Labels,
+		}}}, nil
+	case KIND_SUBNETWORK:
+		_, ipNet, err := net.ParseCIDR(i.Cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse CIDR: %v", err)
+		}
+
+		ip := ipNet.IP.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("Could not convert the ip to an IPv4 ip")
+		}
+		address := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+		prefixLength, _ := ipNet.Mask.Size()
+
+		return &pb.ManifestResource{Resource: &pb.ManifestResource_Subnetwork{Subnetwork: &pb.ManifestSubnetwork{
+			Name:         i.Name,
+			Network:      i.Network,
+			Address:      address,
+			PrefixLength: uint32(prefixLength),
+			Labels:       i.Labels,
+		}}}, nil
+	default:
+		return &pb.ManifestResource{Resource: &pb.ManifestResource_Container{Container: &pb.ManifestContainer{
+			Name:       i.Name,
+			Subnetwork: i.Subnetwork,
+			Image:      i.Image,
+			Entrypoint: i.Entrypoint,
+			Cmd:        i.Cmd,
+			Env:        i.Env,
+			Labels:     i.Labels,
+		}}}, nil
+	}
+}
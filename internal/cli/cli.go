@@ -4,30 +4,51 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 
+	"github.com/BenasB/bx2cloud/internal/api/auth"
+	"github.com/BenasB/bx2cloud/internal/cli/admin"
+	"github.com/BenasB/bx2cloud/internal/cli/apply"
 	"github.com/BenasB/bx2cloud/internal/cli/common"
 	"github.com/BenasB/bx2cloud/internal/cli/container"
 	"github.com/BenasB/bx2cloud/internal/cli/exits"
 	"github.com/BenasB/bx2cloud/internal/cli/introspection"
 	"github.com/BenasB/bx2cloud/internal/cli/network"
+	"github.com/BenasB/bx2cloud/internal/cli/project"
+	"github.com/BenasB/bx2cloud/internal/cli/quota"
 	"github.com/BenasB/bx2cloud/internal/cli/subnetwork"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var globalFlagSet = flag.NewFlagSet("bx2cloud", flag.ExitOnError)
 var globalFlags = struct {
-	target *string
+	target  *string
+	tls     *bool
+	ca      *string
+	cert    *string
+	key     *string
+	token   *string
+	project *string
 }{
-	target: globalFlagSet.String("t", "localhost:8080", "API target <host>:<port>"),
+	target:  globalFlagSet.String("t", "localhost:8080", "API target <host>:<port> or unix:///<path> for a Unix socket"),
+	tls:     globalFlagSet.Bool("tls", envBool("BX2CLOUD_TLS"), "use TLS, verifying the API with the system CAs unless -ca is set, env BX2CLOUD_TLS"),
+	ca:      globalFlagSet.String("ca", os.Getenv("BX2CLOUD_CA_FILE"), "PEM CA certificate to verify the API with, enables TLS, env BX2CLOUD_CA_FILE"),
+	cert:    globalFlagSet.String("cert", os.Getenv("BX2CLOUD_CERT_FILE"), "PEM client certificate for APIs that verify clients, enables TLS, env BX2CLOUD_CERT_FILE"),
+	key:     globalFlagSet.String("key", os.Getenv("BX2CLOUD_KEY_FILE"), "PEM private key of the client certificate, env BX2CLOUD_KEY_FILE"),
+	token:   globalFlagSet.String("token", os.Getenv("BX2CLOUD_TOKEN"), "bearer token to authenticate with, env BX2CLOUD_TOKEN"),
+	project: globalFlagSet.String("project", os.Getenv("BX2CLOUD_PROJECT"), "project to work in, the token's project or \"default\" if empty, env BX2CLOUD_PROJECT"),
 }
 
 func Run(args []string) exits.ExitCode {
 	subcommands := make([]*common.CliCommand, 0)
 	subcommands = append(subcommands, introspection.Commands...)
+	subcommands = append(subcommands, project.Commands...)
 	subcommands = append(subcommands, network.Commands...)
 	subcommands = append(subcommands, subnetwork.Commands...)
 	subcommands = append(subcommands, container.Commands...)
+	subcommands = append(subcommands, quota.Commands...)
+	subcommands = append(subcommands, apply.Commands...)
+	subcommands = append(subcommands, admin.Commands...)
 	mainCommand := common.NewCliSubcommand(globalFlagSet.Name(), subcommands)
 
 	globalFlagSet.Usage = func() {
@@ -41,7 +62,14 @@ func Run(args []string) exits.ExitCode {
 		return exits.BAD_FLAG
 	}
 
-	conn, err := newConn(*globalFlags.target)
+	conn, err := newConn(*globalFlags.target, &auth.ClientOptions{
+		TLS:      *globalFlags.tls,
+		CAFile:   *globalFlags.ca,
+		CertFile: *globalFlags.cert,
+		KeyFile:  *globalFlags.key,
+		Token:    *globalFlags.token,
+		Project:  *globalFlags.project,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return exits.BAD_FLAG
@@ -51,8 +79,12 @@ func Run(args []string) exits.ExitCode {
 	return mainCommand.Execute(globalFlagSet.Args(), conn, []string{})
 }
 
-func newConn(target string) (*grpc.ClientConn, error) {
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+func newConn(target string, options *auth.ClientOptions) (*grpc.ClientConn, error) {
+	opts, err := options.DialOptions(target)
+	if err != nil {
+		return nil, err
+	}
+
 	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
 		return nil, err
@@ -60,3 +92,9 @@ func newConn(target string) (*grpc.ClientConn, error) {
 
 	return conn, nil
 }
+
+// Unset and invalid values are false
+func envBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
@@ -23,3 +23,75 @@ func ParseUint32Arg(args *[]string) (uint32, exits.ExitCode, error) {
 
 	return uint32(arg), exits.SUCCESS, nil
 }
+
+// Maps an unset (zero) resource version flag to nil, so that the API skips the version check
+func OptionalResourceVersion(resourceVersion uint64) *uint64 {
+	if resourceVersion == 0 {
+		return nil
+	}
+
+	return &resourceVersion
+}
+
+// Maps a field that was left out of the input to its zero value
+func ValueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+
+	return *value
+}
+
+// Maps an unset (zero) revision flag to nil, so that the watch starts with the current resources
+func OptionalRevision(revision uint64) *uint64 {
+	if revision == 0 {
+		return nil
+	}
+
+	return &revision
+}
+
+// Maps an unset (zero) id flag to nil, so that the API does not filter by it
+func OptionalId(id uint) *uint32 {
+	if id == 0 {
+		return nil
+	}
+
+	result := uint32(id)
+	return &result
+}
+
+// Identifies a resource either by its id or by its name
+type Identifier struct {
+	Id   uint32
+	Name string
+}
+
+func (i *Identifier) String() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	return strconv.FormatUint(uint64(i.Id), 10)
+}
+
+// Arguments that are unsigned integers are treated as ids, anything else as a name
+func ParseIdentifierArg(args *[]string) (*Identifier, exits.ExitCode, error) {
+	if len(*args) == 0 {
+		return nil, exits.MISSING_ARGUMENT, fmt.Errorf("missing argument")
+	}
+
+	argString := (*args)[0]
+	if argString == "" {
+		return nil, exits.BAD_ARGUMENT, fmt.Errorf("expected an id or a name, got an empty argument")
+	}
+
+	*args = (*args)[1:]
+
+	if id, err := strconv.ParseUint(argString, 10, 32); err == nil {
+		return &Identifier{Id: uint32(id)}, exits.SUCCESS, nil
+	}
+
+	return &Identifier{Name: argString}, exits.SUCCESS, nil
+}
@@ -54,6 +54,36 @@ func TestArgParse_Uint32_Chained(t *testing.T) {
 	}
 }
 
+func TestArgParse_Identifier(t *testing.T) {
+	var tests = []struct {
+		inArgs  []string
+		outArgs []string
+		out     common.Identifier
+	}{
+		{[]string{"42"}, []string{}, common.Identifier{Id: 42}},
+		{[]string{"web", "foo"}, []string{"foo"}, common.Identifier{Name: "web"}},
+		{[]string{"web-1"}, []string{}, common.Identifier{Name: "web-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.inArgs, ","), func(t *testing.T) {
+			out, _, err := common.ParseIdentifierArg(&tt.inArgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !arrEqual(tt.inArgs, tt.outArgs) || *out != tt.out {
+				t.Fatalf("got %q, %+v, want %q, %+v", tt.inArgs, *out, tt.outArgs, tt.out)
+			}
+		})
+	}
+
+	for _, args := range [][]string{{}, {""}} {
+		if _, _, err := common.ParseIdentifierArg(&args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
 func arrEqual[T comparable](a []T, b []T) bool {
 	for i, v := range a {
 		if v != b[i] {
@@ -8,7 +8,9 @@ import (
 	"strings"
 
 	"github.com/BenasB/bx2cloud/internal/cli/exits"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type CliCommand struct {
@@ -104,7 +106,8 @@ func (c *CliCommand) Execute(args []string, conn *grpc.ClientConn, cmdNameChain
 
 		exitCode, err := c.handler(args, conn)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			printError(err)
+			return exits.FromError(err, exitCode)
 		}
 
 		return exitCode
@@ -136,3 +139,23 @@ func FprintSubcommands(w io.Writer, cmdName string, subcommands []*CliCommand) {
 		fmt.Fprintf(w, "  %s\n", sc.flagSet.Name())
 	}
 }
+
+// API errors are printed without the gRPC prefix, followed by the request fields that were rejected
+func printError(err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", s.Message())
+	for _, detail := range s.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.FieldViolations {
+			fmt.Fprintf(os.Stderr, "  rejected field: %s\n", violation.Field)
+		}
+	}
+}
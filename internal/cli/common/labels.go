@@ -0,0 +1,18 @@
+package common
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Formats labels as a sorted, comma separated list of key=value pairs
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+
+	return strings.Join(pairs, ",")
+}
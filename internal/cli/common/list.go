@@ -0,0 +1,45 @@
+package common
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/BenasB/bx2cloud/internal/api/listing"
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/grpc"
+)
+
+// Flags shared by the list commands of every resource type
+type ListFlags struct {
+	LabelSelector string
+	PageSize      uint
+	PageToken     string
+	OrderBy       string
+}
+
+func (f *ListFlags) Register(fs *flag.FlagSet, resources string, orderFields string) {
+	fs.StringVar(&f.LabelSelector, "l", f.LabelSelector, fmt.Sprintf("only list %s whose labels match this selector, e.g. 'env=prod,team in (payments,billing)'", resources))
+	fs.UintVar(&f.PageSize, "page-size", f.PageSize, fmt.Sprintf("list at most this many %s, 0 lists all of them", resources))
+	fs.StringVar(&f.PageToken, "page-token", f.PageToken, "continue a previous list, using the token it printed")
+	fs.StringVar(&f.OrderBy, "order-by", f.OrderBy, fmt.Sprintf("comma separated fields with an optional 'desc' suffix, one of: %s", orderFields))
+}
+
+func (f *ListFlags) Request() *pb.ListRequest {
+	return &pb.ListRequest{
+		LabelSelector: f.LabelSelector,
+		PageSize:      uint32(f.PageSize),
+		PageToken:     f.PageToken,
+		OrderBy:       f.OrderBy,
+	}
+}
+
+// Has to be called after the stream has ended. Printed to stderr, so that it does not get mixed with the listed resources
+func PrintNextPageToken(stream grpc.ClientStream) {
+	tokens := stream.Trailer().Get(listing.NEXT_PAGE_TOKEN_KEY)
+	if len(tokens) == 0 || tokens[0] == "" {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "More results are available, continue with -page-token %s\n", tokens[0])
+}
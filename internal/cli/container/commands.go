@@ -13,62 +13,112 @@ import (
 )
 
 var flags = struct {
-	follow bool
+	follow          bool
+	list            common.ListFlags
+	networkId       uint
+	subnetworkId    uint
+	status          string
+	image           string
+	resourceVersion uint64
+	sinceRevision   uint64
+	idempotencyKey  string
 }{
-	follow: false,
+	follow:          false,
+	list:            common.ListFlags{},
+	networkId:       0,
+	subnetworkId:    0,
+	status:          "",
+	image:           "",
+	resourceVersion: 0,
+	sinceRevision:   0,
+	idempotencyKey:  "",
+}
+
+func listRequest() *pb.ListRequest {
+	req := flags.list.Request()
+	req.NetworkId = common.OptionalId(flags.networkId)
+	req.SubnetworkId = common.OptionalId(flags.subnetworkId)
+	req.Status = flags.status
+	req.Image = flags.image
+	return req
 }
 
 var Commands = []*common.CliCommand{
 	common.NewCliSubcommand(
 		"container",
 		[]*common.CliCommand{
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"list",
 				"Retrieves all existing containers",
 				"",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					if err := List(client); err != nil {
+					if err := List(client, listRequest()); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					flags.list.Register(fs, "containers", "id, name, created_at, image, subnetwork_id")
+					fs.UintVar(&flags.networkId, "network", flags.networkId, "only list containers in subnetworks of this network id")
+					fs.UintVar(&flags.subnetworkId, "subnetwork", flags.subnetworkId, "only list containers of this subnetwork id")
+					fs.StringVar(&flags.status, "status", flags.status, "only list containers with this status, e.g. 'running' or 'stopped'")
+					fs.StringVar(&flags.image, "image", flags.image, "only list containers of this image, e.g. 'nginx:latest'")
+				},
+			),
+			common.NewCliCommandWithFlags(
+				"watch",
+				"Streams changes to containers as they happen",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewContainerServiceClient(conn)
+					if err := Watch(client, flags.sinceRevision); err != nil {
+						return exits.CONTAINER_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.sinceRevision, "since-revision", flags.sinceRevision, "resume after this revision instead of starting with the current containers, 0 starts with the current containers")
+				},
 			),
 			common.NewCliCommand(
 				"get",
 				"Retrieves a specified container",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Get(client, id); err != nil {
+					if err := Get(client, identifier); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"delete",
 				"Deletes a specified container. Before that, stops it if it is running.",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Delete(client, id); err != nil {
+					if err := Delete(client, identifier, flags.resourceVersion); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 skips the check")
+				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"create",
 				"Creates and starts a new container resource",
 				"< file.yaml",
@@ -80,24 +130,27 @@ var Commands = []*common.CliCommand{
 						return exits.CONTAINER_ERROR, err
 					}
 
-					if err := Create(client, yamlBytes); err != nil {
+					if err := Create(client, yamlBytes, flags.idempotencyKey); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.StringVar(&flags.idempotencyKey, "idempotency-key", flags.idempotencyKey, "repeating the command with the same key returns the container created the first time instead of creating another one")
+				},
 			),
 			common.NewCliCommand(
 				"exec",
 				"Starts a shell process inside a specified container or executes a specific command, if specified",
-				"<id> [cmd]",
+				"<id|name> [cmd]",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Exec(client, id, args); err != nil {
+					if err := Exec(client, identifier, args); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
@@ -106,15 +159,15 @@ var Commands = []*common.CliCommand{
 			common.NewCliCommand(
 				"start",
 				"Starts a specified container resource",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Start(client, id); err != nil {
+					if err := Start(client, identifier); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
@@ -123,15 +176,15 @@ var Commands = []*common.CliCommand{
 			common.NewCliCommand(
 				"stop",
 				"Stops a specified container resource",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Stop(client, id); err != nil {
+					if err := Stop(client, identifier); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
@@ -140,15 +193,15 @@ var Commands = []*common.CliCommand{
 			common.NewCliCommandWithFlags(
 				"logs",
 				"Retrieves the logs of a specified container resource",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewContainerServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Logs(client, id, flags.follow); err != nil {
+					if err := Logs(client, identifier, flags.follow); err != nil {
 						return exits.CONTAINER_ERROR, err
 					}
 					return exits.SUCCESS, nil
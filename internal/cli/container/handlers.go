@@ -9,14 +9,14 @@ import (
 	"time"
 
 	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
 	"golang.org/x/term"
-	"google.golang.org/protobuf/types/known/emptypb"
 	"gopkg.in/yaml.v3"
 )
 
 func newWriter() *tabwriter.Writer {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "id\timage\tstatus\tip\n")
+	fmt.Fprintf(w, "id\tname\timage\tstatus\tip\tlabels\n")
 	return w
 }
 
@@ -34,11 +34,11 @@ func print(w *tabwriter.Writer, container *pb.Container) {
 		status = fmt.Sprintf("%s (%s)", container.Status, since.Round(time.Second))
 	}
 
-	fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", container.Id, container.Image, status, cidr)
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", container.Id, container.Name, container.Image, status, cidr, common.FormatLabels(container.Labels))
 }
 
-func List(client pb.ContainerServiceClient) error {
-	stream, err := client.List(context.Background(), &emptypb.Empty{})
+func List(client pb.ContainerServiceClient, req *pb.ListRequest) error {
+	stream, err := client.List(context.Background(), req)
 	if err != nil {
 		return err
 	}
@@ -56,13 +56,15 @@ func List(client pb.ContainerServiceClient) error {
 		print(w, container)
 	}
 
+	// The table has to be printed before the hint about the next page
+	w.Flush()
+	common.PrintNextPageToken(stream)
+
 	return nil
 }
 
-func Get(client pb.ContainerServiceClient, id uint32) error {
-	container, err := client.Get(context.Background(), &pb.ContainerIdentificationRequest{
-		Id: id,
-	})
+func Get(client pb.ContainerServiceClient, identifier *common.Identifier) error {
+	container, err := client.Get(context.Background(), identification(identifier, 0))
 	if err != nil {
 		return err
 	}
@@ -74,20 +76,18 @@ func Get(client pb.ContainerServiceClient, id uint32) error {
 	return nil
 }
 
-func Delete(client pb.ContainerServiceClient, id uint32) error {
-	_, err := client.Delete(context.Background(), &pb.ContainerIdentificationRequest{
-		Id: id,
-	})
+func Delete(client pb.ContainerServiceClient, identifier *common.Identifier, resourceVersion uint64) error {
+	_, err := client.Delete(context.Background(), identification(identifier, resourceVersion))
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully deleted %d\n", id)
+	fmt.Printf("Successfully deleted %s\n", identifier)
 
 	return nil
 }
 
-func Create(client pb.ContainerServiceClient, yamlBytes []byte) error {
+func Create(client pb.ContainerServiceClient, yamlBytes []byte, idempotencyKey string) error {
 	input := &containerCreation{}
 	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
 		return err
@@ -98,11 +98,14 @@ func Create(client pb.ContainerServiceClient, yamlBytes []byte) error {
 	}
 
 	req := &pb.ContainerCreationRequest{
-		SubnetworkId: input.SubnetworkId,
-		Image:        input.Image,
-		Entrypoint:   input.Entrypoint,
-		Cmd:          input.Cmd,
-		Env:          input.Env,
+		SubnetworkId:   input.SubnetworkId,
+		Image:          input.Image,
+		Entrypoint:     input.Entrypoint,
+		Cmd:            input.Cmd,
+		Env:            input.Env,
+		Labels:         input.Labels,
+		Name:           input.Name,
+		IdempotencyKey: idempotencyKey,
 	}
 
 	resp, err := client.Create(context.Background(), req)
@@ -115,7 +118,7 @@ func Create(client pb.ContainerServiceClient, yamlBytes []byte) error {
 	return nil
 }
 
-func Exec(client pb.ContainerServiceClient, id uint32, args []string) error {
+func Exec(client pb.ContainerServiceClient, identifier *common.Identifier, args []string) error {
 	inputFd := int(os.Stdin.Fd())
 
 	if !term.IsTerminal(inputFd) {
@@ -150,13 +153,11 @@ func Exec(client pb.ContainerServiceClient, id uint32, args []string) error {
 	stream.Send(&pb.ContainerExecRequest{
 		Input: &pb.ContainerExecRequest_Initialization{
 			Initialization: &pb.ContainerExecInitializationRequest{
-				Identification: &pb.ContainerIdentificationRequest{
-					Id: id,
-				},
-				ConsoleWidth:  int32(width),
-				ConsoleHeight: int32(height),
-				Terminal:      terminal,
-				Args:          args,
+				Identification: identification(identifier, 0),
+				ConsoleWidth:   int32(width),
+				ConsoleHeight:  int32(height),
+				Terminal:       terminal,
+				Args:           args,
 			},
 		},
 	})
@@ -205,10 +206,8 @@ func Exec(client pb.ContainerServiceClient, id uint32, args []string) error {
 	return nil
 }
 
-func Start(client pb.ContainerServiceClient, id uint32) error {
-	resp, err := client.Start(context.Background(), &pb.ContainerIdentificationRequest{
-		Id: id,
-	})
+func Start(client pb.ContainerServiceClient, identifier *common.Identifier) error {
+	resp, err := client.Start(context.Background(), identification(identifier, 0))
 
 	if err != nil {
 		return err
@@ -219,10 +218,8 @@ func Start(client pb.ContainerServiceClient, id uint32) error {
 	return nil
 }
 
-func Stop(client pb.ContainerServiceClient, id uint32) error {
-	resp, err := client.Stop(context.Background(), &pb.ContainerIdentificationRequest{
-		Id: id,
-	})
+func Stop(client pb.ContainerServiceClient, identifier *common.Identifier) error {
+	resp, err := client.Stop(context.Background(), identification(identifier, 0))
 
 	if err != nil {
 		return err
@@ -233,12 +230,10 @@ func Stop(client pb.ContainerServiceClient, id uint32) error {
 	return nil
 }
 
-func Logs(client pb.ContainerServiceClient, id uint32, follow bool) error {
+func Logs(client pb.ContainerServiceClient, identifier *common.Identifier, follow bool) error {
 	req := &pb.ContainerLogsRequest{
-		Identification: &pb.ContainerIdentificationRequest{
-			Id: id,
-		},
-		Follow: follow,
+		Identification: identification(identifier, 0),
+		Follow:         follow,
 	}
 
 	stream, err := client.Logs(context.Background(), req)
@@ -259,3 +254,45 @@ func Logs(client pb.ContainerServiceClient, id uint32, follow bool) error {
 
 	return nil
 }
+
+func Watch(client pb.ContainerServiceClient, sinceRevision uint64) error {
+	stream, err := client.Watch(context.Background(), &pb.WatchRequest{
+		SinceRevision: common.OptionalRevision(sinceRevision),
+	})
+	if err != nil {
+		return err
+	}
+
+	// A minimum cell width keeps the columns of separately flushed events roughly aligned
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "revision\tevent\tid\tname\timage\tstatus\tip\tlabels\n")
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%s\t", event.Revision, event.Type)
+		print(w, event.Container)
+		// Events arrive indefinitely, so each one is shown as soon as it is received
+		w.Flush()
+	}
+
+	return nil
+}
+
+func identification(identifier *common.Identifier, resourceVersion uint64) *pb.ContainerIdentificationRequest {
+	req := &pb.ContainerIdentificationRequest{
+		ResourceVersion: common.OptionalResourceVersion(resourceVersion),
+	}
+
+	if identifier.Name != "" {
+		req.Identifier = &pb.ContainerIdentificationRequest_Name{Name: identifier.Name}
+	} else {
+		req.Identifier = &pb.ContainerIdentificationRequest_Id{Id: identifier.Id}
+	}
+
+	return req
+}
@@ -9,11 +9,13 @@ import (
 var _ inputs.Input = &containerCreation{}
 
 type containerCreation struct {
-	SubnetworkId uint32   `yaml:"subnetworkId"`
-	Image        string   `yaml:"image"`
-	Entrypoint   []string `yaml:"entrypoint"`
-	Cmd          []string `yaml:"cmd"`
-	Env          []string `yaml:"env"`
+	Name         string            `yaml:"name"`
+	SubnetworkId uint32            `yaml:"subnetworkId"`
+	Image        string            `yaml:"image"`
+	Entrypoint   []string          `yaml:"entrypoint"`
+	Cmd          []string          `yaml:"cmd"`
+	Env          []string          `yaml:"env"`
+	Labels       map[string]string `yaml:"labels"`
 }
 
 func (i *containerCreation) Validate() error {
@@ -1,5 +1,10 @@
 package exits
 
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
 type ExitCode int
 
 const (
@@ -13,4 +18,43 @@ const (
 	SUBNETWORK_ERROR
 	CONTAINER_ERROR
 	BAD_FLAG
+	ADMIN_ERROR
+	NOT_FOUND
+	ALREADY_EXISTS
+	FAILED_PRECONDITION
+	RESOURCE_EXHAUSTED
+	INVALID_ARGUMENT
+	CONFLICT
+	UNAUTHENTICATED
+	PROJECT_ERROR
+	QUOTA_ERROR
+	PERMISSION_DENIED
+	INTROSPECTION_ERROR
+	// The API is reachable but reports a problem with its host
+	UNHEALTHY
+	APPLY_ERROR
 )
+
+// Picks a more specific exit code for API errors whose status code scripts may want to react to
+func FromError(err error, fallback ExitCode) ExitCode {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return NOT_FOUND
+	case codes.AlreadyExists:
+		return ALREADY_EXISTS
+	case codes.FailedPrecondition:
+		return FAILED_PRECONDITION
+	case codes.ResourceExhausted:
+		return RESOURCE_EXHAUSTED
+	case codes.InvalidArgument:
+		return INVALID_ARGUMENT
+	case codes.Aborted:
+		return CONFLICT
+	case codes.Unauthenticated:
+		return UNAUTHENTICATED
+	case codes.PermissionDenied:
+		return PERMISSION_DENIED
+	default:
+		return fallback
+	}
+}
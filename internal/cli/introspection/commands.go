@@ -18,4 +18,20 @@ var Commands = []*common.CliCommand{
 			return exits.SUCCESS, nil
 		},
 	),
+	common.NewCliCommand(
+		"introspection",
+		"Prints a health report of the API and its host",
+		"",
+		func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+			client := pb.NewIntrospectionServiceClient(conn)
+			healthy, err := Report(client)
+			if err != nil {
+				return exits.INTROSPECTION_ERROR, err
+			}
+			if !healthy {
+				return exits.UNHEALTHY, nil
+			}
+			return exits.SUCCESS, nil
+		},
+	),
 }
@@ -3,6 +3,10 @@ package introspection
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/BenasB/bx2cloud/internal/api/pb"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -20,4 +24,124 @@ func Version(client pb.IntrospectionServiceClient) {
 	}
 
 	fmt.Printf("API version: %s\n", resp.Version)
+
+	if len(resp.IpamIssues) > 0 {
+		fmt.Printf("\nWarning: the API could not restore the following container IP allocations:\n")
+		printIpamIssues(resp.IpamIssues)
+	}
+}
+
+// Returns whether every required capability is available and all IP allocations were restored
+func Report(client pb.IntrospectionServiceClient) (bool, error) {
+	resp, err := client.Get(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return false, err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	build := resp.Build
+	fmt.Fprintf(w, "API version:\t%s\n", resp.Version)
+	fmt.Fprintf(w, "Commit:\t%s\n", orUnknown(build.GetCommit()))
+	fmt.Fprintf(w, "Build date:\t%s\n", orUnknown(build.GetDate()))
+	fmt.Fprintf(w, "Go version:\t%s\n", orUnknown(build.GetGoVersion()))
+	fmt.Fprintf(w, "Uptime:\t%s\n", resp.Uptime.AsDuration().Round(time.Second))
+	fmt.Fprintf(w, "Features:\t%s\n", orNone(strings.Join(resp.Features, ", ")))
+
+	host := resp.Host
+	cgroupVersion := "unknown"
+	if host.GetCgroupVersion() != 0 {
+		cgroupVersion = fmt.Sprintf("v%d", host.GetCgroupVersion())
+	}
+	fmt.Fprintf(w, "\nHost\n")
+	fmt.Fprintf(w, "  Kernel:\t%s\n", orUnknown(host.GetKernelVersion()))
+	fmt.Fprintf(w, "  cgroup:\t%s\n", cgroupVersion)
+	fmt.Fprintf(w, "  iptables backend:\t%s\n", orUnknown(host.GetIptablesBackend()))
+	fmt.Fprintf(w, "  CPUs:\t%d\n", host.GetCpus())
+	fmt.Fprintf(w, "  Memory:\t%s available of %s\n", formatBytes(host.GetMemoryAvailableBytes()), formatBytes(host.GetMemoryTotalBytes()))
+	w.Flush()
+
+	fmt.Printf("\nData directories\n")
+	fmt.Fprintf(w, "  name\tpath\tfree\ttotal\n")
+	for _, dir := range host.GetDataDirectories() {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", dir.Name, dir.Path, formatBytes(dir.FreeBytes), formatBytes(dir.TotalBytes))
+	}
+	w.Flush()
+
+	healthy := true
+	fmt.Printf("\nCapabilities\n")
+	fmt.Fprintf(w, "  name\tstatus\trequired\tdetail\n")
+	for _, capability := range resp.Capabilities {
+		status := "ok"
+		if !capability.Available {
+			status = "missing"
+			healthy = healthy && !capability.Required
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%t\t%s\n", capability.Name, status, capability.Required, capability.Detail)
+	}
+	w.Flush()
+
+	resources := resp.Resources
+	fmt.Printf("\nResources\n")
+	fmt.Fprintf(w, "  projects\tnetworks\tsubnetworks\tcontainers\trunning containers\n")
+	fmt.Fprintf(w, "  %d\t%d\t%d\t%d\t%d\n", resources.GetProjects(), resources.GetNetworks(), resources.GetSubnetworks(), resources.GetContainers(), resources.GetRunningContainers())
+	w.Flush()
+
+	if len(resp.IpamIssues) > 0 {
+		healthy = false
+		fmt.Printf("\nContainer IP allocations that could not be restored\n")
+		printIpamIssues(resp.IpamIssues)
+	}
+
+	if healthy {
+		fmt.Printf("\nStatus: healthy\n")
+	} else {
+		fmt.Printf("\nStatus: unhealthy\n")
+	}
+
+	return healthy, nil
+}
+
+func printIpamIssues(issues []*pb.IpamIssue) {
+	for _, issue := range issues {
+		fmt.Printf("  container %d (subnetwork %d, %d.%d.%d.%d/%d): %s\n",
+			issue.ContainerId,
+			issue.SubnetworkId,
+			byte(issue.Address>>24),
+			byte(issue.Address>>16),
+			byte(issue.Address>>8),
+			byte(issue.Address),
+			issue.PrefixLength,
+			issue.Description)
+	}
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// Formats sizes in binary units, e.g. "1.5 GiB"
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -11,57 +12,94 @@ import (
 	"google.golang.org/grpc"
 )
 
+var flags = struct {
+	list            common.ListFlags
+	resourceVersion uint64
+	sinceRevision   uint64
+	idempotencyKey  string
+}{
+	list:            common.ListFlags{},
+	resourceVersion: 0,
+	sinceRevision:   0,
+	idempotencyKey:  "",
+}
+
+func listRequest() *pb.ListRequest {
+	return flags.list.Request()
+}
+
 var Commands = []*common.CliCommand{
 	common.NewCliSubcommand(
 		"network",
 		[]*common.CliCommand{
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"list",
 				"Retrieves all existing networks",
 				"",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewNetworkServiceClient(conn)
-					if err := List(client); err != nil {
+					if err := List(client, listRequest()); err != nil {
 						return exits.NETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					flags.list.Register(fs, "networks", "id, name, created_at")
+				},
+			),
+			common.NewCliCommandWithFlags(
+				"watch",
+				"Streams changes to networks as they happen",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewNetworkServiceClient(conn)
+					if err := Watch(client, flags.sinceRevision); err != nil {
+						return exits.NETWORK_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.sinceRevision, "since-revision", flags.sinceRevision, "resume after this revision instead of starting with the current networks, 0 starts with the current networks")
+				},
 			),
 			common.NewCliCommand(
 				"get",
 				"Retrieves a specified network",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewNetworkServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Get(client, id); err != nil {
+					if err := Get(client, identifier); err != nil {
 						return exits.NETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"delete",
 				"Deletes a specified network",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewNetworkServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Delete(client, id); err != nil {
+					if err := Delete(client, identifier, flags.resourceVersion); err != nil {
 						return exits.NETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 skips the check")
+				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"create",
 				"Creates a new network resource",
 				"< file.yaml",
@@ -74,15 +112,18 @@ var Commands = []*common.CliCommand{
 						return exits.NETWORK_ERROR, err
 					}
 
-					if err := Create(client, yamlBytes); err != nil {
+					if err := Create(client, yamlBytes, flags.idempotencyKey); err != nil {
 						return exits.NETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.StringVar(&flags.idempotencyKey, "idempotency-key", flags.idempotencyKey, "repeating the command with the same key returns the network created the first time instead of creating another one")
+				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"update",
-				"Updates an existing network resource",
+				"Updates an existing network resource, keeping its name and labels if the file leaves them out",
 				"< file.yaml",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewNetworkServiceClient(conn)
@@ -92,16 +133,19 @@ var Commands = []*common.CliCommand{
 						return exits.NETWORK_ERROR, err
 					}
 
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Update(client, id, yamlBytes); err != nil {
+					if err := Update(client, identifier, yamlBytes, flags.resourceVersion); err != nil {
 						return exits.NETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 only fails if it changes while being updated")
+				},
 			),
 		},
 	),
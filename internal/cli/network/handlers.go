@@ -8,22 +8,22 @@ import (
 	"text/tabwriter"
 
 	"github.com/BenasB/bx2cloud/internal/api/pb"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
 	"gopkg.in/yaml.v3"
 )
 
 func newWriter() *tabwriter.Writer {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "id\tinternetAccess\n")
+	fmt.Fprintf(w, "id\tname\tinternetAccess\tversion\tlabels\n")
 	return w
 }
 
 func print(w *tabwriter.Writer, network *pb.Network) {
-	fmt.Fprintf(w, "%d\t%t\n", network.Id, network.InternetAccess)
+	fmt.Fprintf(w, "%d\t%s\t%t\t%d\t%s\n", network.Id, network.Name, network.InternetAccess, network.ResourceVersion, common.FormatLabels(network.Labels))
 }
 
-func List(client pb.NetworkServiceClient) error {
-	stream, err := client.List(context.Background(), &emptypb.Empty{})
+func List(client pb.NetworkServiceClient, req *pb.ListRequest) error {
+	stream, err := client.List(context.Background(), req)
 	if err != nil {
 		return err
 	}
@@ -41,13 +41,15 @@ func List(client pb.NetworkServiceClient) error {
 		print(w, network)
 	}
 
+	// The table has to be printed before the hint about the next page
+	w.Flush()
+	common.PrintNextPageToken(stream)
+
 	return nil
 }
 
-func Get(client pb.NetworkServiceClient, id uint32) error {
-	network, err := client.Get(context.Background(), &pb.NetworkIdentificationRequest{
-		Id: id,
-	})
+func Get(client pb.NetworkServiceClient, identifier *common.Identifier) error {
+	network, err := client.Get(context.Background(), identification(identifier, 0))
 	if err != nil {
 		return err
 	}
@@ -59,20 +61,18 @@ func Get(client pb.NetworkServiceClient, id uint32) error {
 	return nil
 }
 
-func Delete(client pb.NetworkServiceClient, id uint32) error {
-	_, err := client.Delete(context.Background(), &pb.NetworkIdentificationRequest{
-		Id: id,
-	})
+func Delete(client pb.NetworkServiceClient, identifier *common.Identifier, resourceVersion uint64) error {
+	_, err := client.Delete(context.Background(), identification(identifier, resourceVersion))
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully deleted %d\n", id)
+	fmt.Printf("Successfully deleted %s\n", identifier)
 
 	return nil
 }
 
-func Create(client pb.NetworkServiceClient, yamlBytes []byte) error {
+func Create(client pb.NetworkServiceClient, yamlBytes []byte, idempotencyKey string) error {
 	input := &networkCreation{}
 	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
 		return err
@@ -84,6 +84,9 @@ func Create(client pb.NetworkServiceClient, yamlBytes []byte) error {
 
 	req := &pb.NetworkCreationRequest{
 		InternetAccess: input.InternetAccess,
+		Labels:         input.Labels,
+		Name:           common.ValueOrZero(input.Name),
+		IdempotencyKey: idempotencyKey,
 	}
 
 	resp, err := client.Create(context.Background(), req)
@@ -96,7 +99,7 @@ func Create(client pb.NetworkServiceClient, yamlBytes []byte) error {
 	return nil
 }
 
-func Update(client pb.NetworkServiceClient, id uint32, yamlBytes []byte) error {
+func Update(client pb.NetworkServiceClient, identifier *common.Identifier, yamlBytes []byte, resourceVersion uint64) error {
 	input := &networkCreation{}
 	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
 		return err
@@ -106,13 +109,31 @@ func Update(client pb.NetworkServiceClient, id uint32, yamlBytes []byte) error {
 		return err
 	}
 
+	current, err := client.Get(context.Background(), identification(identifier, 0))
+	if err != nil {
+		return err
+	}
+
+	update := &pb.NetworkCreationRequest{
+		InternetAccess: input.InternetAccess,
+		Labels:         current.Labels,
+		Name:           current.Name,
+	}
+	if input.Labels != nil {
+		update.Labels = input.Labels
+	}
+	if input.Name != nil {
+		update.Name = *input.Name
+	}
+
+	// The kept values were read just now, so the update must not overwrite a change made since then
+	if resourceVersion == 0 {
+		resourceVersion = current.ResourceVersion
+	}
+
 	req := &pb.NetworkUpdateRequest{
-		Identification: &pb.NetworkIdentificationRequest{
-			Id: id,
-		},
-		Update: &pb.NetworkCreationRequest{
-			InternetAccess: input.InternetAccess,
-		},
+		Identification: identification(identifier, resourceVersion),
+		Update:         update,
 	}
 
 	resp, err := client.Update(context.Background(), req)
@@ -124,3 +145,45 @@ func Update(client pb.NetworkServiceClient, id uint32, yamlBytes []byte) error {
 
 	return nil
 }
+
+func Watch(client pb.NetworkServiceClient, sinceRevision uint64) error {
+	stream, err := client.Watch(context.Background(), &pb.WatchRequest{
+		SinceRevision: common.OptionalRevision(sinceRevision),
+	})
+	if err != nil {
+		return err
+	}
+
+	// A minimum cell width keeps the columns of separately flushed events roughly aligned
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "revision\tevent\tid\tname\tinternetAccess\tversion\tlabels\n")
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%s\t", event.Revision, event.Type)
+		print(w, event.Network)
+		// Events arrive indefinitely, so each one is shown as soon as it is received
+		w.Flush()
+	}
+
+	return nil
+}
+
+func identification(identifier *common.Identifier, resourceVersion uint64) *pb.NetworkIdentificationRequest {
+	req := &pb.NetworkIdentificationRequest{
+		ResourceVersion: common.OptionalResourceVersion(resourceVersion),
+	}
+
+	if identifier.Name != "" {
+		req.Identifier = &pb.NetworkIdentificationRequest_Name{Name: identifier.Name}
+	} else {
+		req.Identifier = &pb.NetworkIdentificationRequest_Id{Id: identifier.Id}
+	}
+
+	return req
+}
@@ -0,0 +1,65 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"github.com/BenasB/bx2cloud/internal/cli/network"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+type fakeClient struct {
+	pb.NetworkServiceClient
+	current *pb.Network
+	updated *pb.NetworkUpdateRequest
+}
+
+func (c *fakeClient) Get(ctx context.Context, in *pb.NetworkIdentificationRequest, opts ...grpc.CallOption) (*pb.Network, error) {
+	return c.current, nil
+}
+
+func (c *fakeClient) Update(ctx context.Context, in *pb.NetworkUpdateRequest, opts ...grpc.CallOption) (*pb.Network, error) {
+	c.updated = in
+	return c.current, nil
+}
+
+func TestNetwork_Update_KeepsLeftOutFields(t *testing.T) {
+	client := &fakeClient{current: &pb.Network{
+		Id:              3,
+		Name:            "backend",
+		ResourceVersion: 4,
+		Labels:          map[string]string{"bx2cloud.io/manifest": "prod"},
+	}}
+
+	if err := network.Update(client, &common.Identifier{Id: 3}, []byte("internetAccess: true\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	version := uint64(4)
+	expected := &pb.NetworkUpdateRequest{
+		Identification: &pb.NetworkIdentificationRequest{
+			Identifier:      &pb.NetworkIdentificationRequest_Id{Id: 3},
+			ResourceVersion: &version,
+		},
+		Update: &pb.NetworkCreationRequest{
+			InternetAccess: true,
+			Name:           "backend",
+			Labels:         map[string]string{"bx2cloud.io/manifest": "prod"},
+		},
+	}
+	if diff := cmp.Diff(expected, client.updated, protocmp.Transform()); diff != "" {
+		t.Errorf("update request mismatch (-want +got):\n%s", diff)
+	}
+
+	// Fields that are set, even to be empty, replace the current values
+	if err := network.Update(client, &common.Identifier{Id: 3}, []byte("name: \"\"\nlabels: {}\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if client.updated.Update.Name != "" || len(client.updated.Update.Labels) != 0 {
+		t.Errorf("expected the name and labels to be cleared, got %q and %v", client.updated.Update.Name, client.updated.Update.Labels)
+	}
+}
@@ -4,8 +4,11 @@ import "github.com/BenasB/bx2cloud/internal/cli/inputs"
 
 var _ inputs.Input = &networkCreation{}
 
+// Name and labels are nil when they are left out, so that an update keeps their current values
 type networkCreation struct {
-	InternetAccess bool `yaml:"internetAccess"`
+	Name           *string           `yaml:"name"`
+	InternetAccess bool              `yaml:"internetAccess"`
+	Labels         map[string]string `yaml:"labels"`
 }
 
 func (i *networkCreation) Validate() error {
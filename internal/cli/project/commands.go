@@ -0,0 +1,103 @@
+package project
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"github.com/BenasB/bx2cloud/internal/cli/exits"
+	"google.golang.org/grpc"
+)
+
+var flags = struct {
+	list            common.ListFlags
+	resourceVersion uint64
+}{
+	list:            common.ListFlags{},
+	resourceVersion: 0,
+}
+
+func listRequest() *pb.ListRequest {
+	return flags.list.Request()
+}
+
+var Commands = []*common.CliCommand{
+	common.NewCliSubcommand(
+		"project",
+		[]*common.CliCommand{
+			common.NewCliCommandWithFlags(
+				"list",
+				"Retrieves all existing projects",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewProjectServiceClient(conn)
+					if err := List(client, listRequest()); err != nil {
+						return exits.PROJECT_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					flags.list.Register(fs, "projects", "id, name, created_at")
+				},
+			),
+			common.NewCliCommand(
+				"get",
+				"Retrieves a specified project",
+				"<id|name>",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewProjectServiceClient(conn)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
+					if err != nil {
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
+					}
+
+					if err := Get(client, identifier); err != nil {
+						return exits.PROJECT_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+			),
+			common.NewCliCommandWithFlags(
+				"delete",
+				"Deletes a specified project, which must not have any networks",
+				"<id|name>",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewProjectServiceClient(conn)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
+					if err != nil {
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
+					}
+
+					if err := Delete(client, identifier, flags.resourceVersion); err != nil {
+						return exits.PROJECT_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 skips the check")
+				},
+			),
+			common.NewCliCommand(
+				"create",
+				"Creates a new project",
+				"< file.yaml",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewProjectServiceClient(conn)
+
+					yamlBytes, err := io.ReadAll(os.Stdin)
+					if err != nil {
+						return exits.PROJECT_ERROR, err
+					}
+
+					if err := Create(client, yamlBytes); err != nil {
+						return exits.PROJECT_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+			),
+		},
+	),
+}
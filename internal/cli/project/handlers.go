@@ -0,0 +1,112 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"gopkg.in/yaml.v3"
+)
+
+func newWriter() *tabwriter.Writer {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "id\tname\tversion\tlabels\n")
+	return w
+}
+
+func print(w *tabwriter.Writer, project *pb.Project) {
+	fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", project.Id, project.Name, project.ResourceVersion, common.FormatLabels(project.Labels))
+}
+
+func List(client pb.ProjectServiceClient, req *pb.ListRequest) error {
+	stream, err := client.List(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	w := newWriter()
+	defer w.Flush()
+	for {
+		project, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		print(w, project)
+	}
+
+	// The table has to be printed before the hint about the next page
+	w.Flush()
+	common.PrintNextPageToken(stream)
+
+	return nil
+}
+
+func Get(client pb.ProjectServiceClient, identifier *common.Identifier) error {
+	project, err := client.Get(context.Background(), identification(identifier, 0))
+	if err != nil {
+		return err
+	}
+
+	w := newWriter()
+	defer w.Flush()
+	print(w, project)
+
+	return nil
+}
+
+func Delete(client pb.ProjectServiceClient, identifier *common.Identifier, resourceVersion uint64) error {
+	_, err := client.Delete(context.Background(), identification(identifier, resourceVersion))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully deleted %s\n", identifier)
+
+	return nil
+}
+
+func Create(client pb.ProjectServiceClient, yamlBytes []byte) error {
+	input := &projectCreation{}
+	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
+		return err
+	}
+
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	req := &pb.ProjectCreationRequest{
+		Name:   input.Name,
+		Labels: input.Labels,
+	}
+
+	resp, err := client.Create(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully created %d\n", resp.Id)
+
+	return nil
+}
+
+func identification(identifier *common.Identifier, resourceVersion uint64) *pb.ProjectIdentificationRequest {
+	req := &pb.ProjectIdentificationRequest{
+		ResourceVersion: common.OptionalResourceVersion(resourceVersion),
+	}
+
+	if identifier.Name != "" {
+		req.Identifier = &pb.ProjectIdentificationRequest_Name{Name: identifier.Name}
+	} else {
+		req.Identifier = &pb.ProjectIdentificationRequest_Id{Id: identifier.Id}
+	}
+
+	return req
+}
@@ -0,0 +1,22 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/BenasB/bx2cloud/internal/cli/inputs"
+)
+
+var _ inputs.Input = &projectCreation{}
+
+type projectCreation struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+func (i *projectCreation) Validate() error {
+	if i.Name == "" {
+		return fmt.Errorf("projects must have a name")
+	}
+
+	return nil
+}
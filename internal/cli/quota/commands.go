@@ -0,0 +1,28 @@
+package quota
+
+import (
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
+	"github.com/BenasB/bx2cloud/internal/cli/exits"
+	"google.golang.org/grpc"
+)
+
+var Commands = []*common.CliCommand{
+	common.NewCliSubcommand(
+		"quota",
+		[]*common.CliCommand{
+			common.NewCliCommand(
+				"usage",
+				"Shows how many resources the caller has created compared to its quota",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewQuotaServiceClient(conn)
+					if err := Usage(client); err != nil {
+						return exits.QUOTA_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+			),
+		},
+	),
+}
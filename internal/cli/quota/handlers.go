@@ -0,0 +1,31 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BenasB/bx2cloud/internal/api/pb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func Usage(client pb.QuotaServiceClient) error {
+	usage, err := client.GetUsage(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "resource\tused\tlimit\n")
+	for _, item := range usage.Items {
+		limit := "unlimited"
+		if item.Limit > 0 {
+			limit = fmt.Sprintf("%d", item.Limit)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", item.Resource, item.Used, limit)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package subnetwork
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -11,57 +12,99 @@ import (
 	"google.golang.org/grpc"
 )
 
+var flags = struct {
+	list            common.ListFlags
+	networkId       uint
+	resourceVersion uint64
+	sinceRevision   uint64
+	idempotencyKey  string
+}{
+	list:            common.ListFlags{},
+	networkId:       0,
+	resourceVersion: 0,
+	sinceRevision:   0,
+	idempotencyKey:  "",
+}
+
+func listRequest() *pb.ListRequest {
+	req := flags.list.Request()
+	req.NetworkId = common.OptionalId(flags.networkId)
+	return req
+}
+
 var Commands = []*common.CliCommand{
 	common.NewCliSubcommand(
 		"subnetwork",
 		[]*common.CliCommand{
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"list",
 				"Retrieves all existing subnetworks",
 				"",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewSubnetworkServiceClient(conn)
-					if err := List(client); err != nil {
+					if err := List(client, listRequest()); err != nil {
 						return exits.SUBNETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					flags.list.Register(fs, "subnetworks", "id, name, created_at, network_id, address")
+					fs.UintVar(&flags.networkId, "network", flags.networkId, "only list subnetworks of this network id")
+				},
+			),
+			common.NewCliCommandWithFlags(
+				"watch",
+				"Streams changes to subnetworks as they happen",
+				"",
+				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
+					client := pb.NewSubnetworkServiceClient(conn)
+					if err := Watch(client, flags.sinceRevision); err != nil {
+						return exits.SUBNETWORK_ERROR, err
+					}
+					return exits.SUCCESS, nil
+				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.sinceRevision, "since-revision", flags.sinceRevision, "resume after this revision instead of starting with the current subnetworks, 0 starts with the current subnetworks")
+				},
 			),
 			common.NewCliCommand(
 				"get",
 				"Retrieves a specified subnetwork",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewSubnetworkServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Get(client, id); err != nil {
+					if err := Get(client, identifier); err != nil {
 						return exits.SUBNETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"delete",
 				"Deletes a specified subnetwork",
-				"<id>",
+				"<id|name>",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewSubnetworkServiceClient(conn)
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Delete(client, id); err != nil {
+					if err := Delete(client, identifier, flags.resourceVersion); err != nil {
 						return exits.SUBNETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 skips the check")
+				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"create",
 				"Creates a new subnetwork resource",
 				"< file.yaml",
@@ -73,15 +116,18 @@ var Commands = []*common.CliCommand{
 						return exits.SUBNETWORK_ERROR, err
 					}
 
-					if err := Create(client, yamlBytes); err != nil {
+					if err := Create(client, yamlBytes, flags.idempotencyKey); err != nil {
 						return exits.SUBNETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.StringVar(&flags.idempotencyKey, "idempotency-key", flags.idempotencyKey, "repeating the command with the same key returns the subnetwork created the first time instead of creating another one")
+				},
 			),
-			common.NewCliCommand(
+			common.NewCliCommandWithFlags(
 				"update",
-				"Updates an existing network resource",
+				"Updates an existing subnetwork resource, keeping its name and labels if the file leaves them out",
 				"< file.yaml",
 				func(args []string, conn *grpc.ClientConn) (exits.ExitCode, error) {
 					client := pb.NewSubnetworkServiceClient(conn)
@@ -91,16 +137,19 @@ var Commands = []*common.CliCommand{
 						return exits.SUBNETWORK_ERROR, err
 					}
 
-					id, exitCode, err := common.ParseUint32Arg(&args)
+					identifier, exitCode, err := common.ParseIdentifierArg(&args)
 					if err != nil {
-						return exitCode, fmt.Errorf("failed to parse 'id' argument: %w", err)
+						return exitCode, fmt.Errorf("failed to parse 'id|name' argument: %w", err)
 					}
 
-					if err := Update(client, id, yamlBytes); err != nil {
+					if err := Update(client, identifier, yamlBytes, flags.resourceVersion); err != nil {
 						return exits.SUBNETWORK_ERROR, err
 					}
 					return exits.SUCCESS, nil
 				},
+				func(fs *flag.FlagSet) {
+					fs.Uint64Var(&flags.resourceVersion, "resource-version", flags.resourceVersion, "fail if the resource changed since this version, 0 only fails if it changes while being updated")
+				},
 			),
 		},
 	),
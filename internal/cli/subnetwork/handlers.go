@@ -9,13 +9,13 @@ import (
 	"text/tabwriter"
 
 	"github.com/BenasB/bx2cloud/internal/api/pb"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"github.com/BenasB/bx2cloud/internal/cli/common"
 	"gopkg.in/yaml.v3"
 )
 
 func newWriter() *tabwriter.Writer {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "id\tnetwork_id\tcidr\n")
+	fmt.Fprintf(w, "id\tname\tnetwork_id\tcidr\tversion\tlabels\n")
 	return w
 }
 
@@ -27,11 +27,11 @@ func print(w *tabwriter.Writer, subnetwork *pb.Subnetwork) {
 		byte(subnetwork.Address),
 		subnetwork.PrefixLength)
 
-	fmt.Fprintf(w, "%d\t%d\t%s\n", subnetwork.Id, subnetwork.NetworkId, cidr)
+	fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%d\t%s\n", subnetwork.Id, subnetwork.Name, subnetwork.NetworkId, cidr, subnetwork.ResourceVersion, common.FormatLabels(subnetwork.Labels))
 }
 
-func List(client pb.SubnetworkServiceClient) error {
-	stream, err := client.List(context.Background(), &emptypb.Empty{})
+func List(client pb.SubnetworkServiceClient, req *pb.ListRequest) error {
+	stream, err := client.List(context.Background(), req)
 	if err != nil {
 		return err
 	}
@@ -49,13 +49,15 @@ func List(client pb.SubnetworkServiceClient) error {
 		print(w, subnetwork)
 	}
 
+	// The table has to be printed before the hint about the next page
+	w.Flush()
+	common.PrintNextPageToken(stream)
+
 	return nil
 }
 
-func Get(client pb.SubnetworkServiceClient, id uint32) error {
-	subnetwork, err := client.Get(context.Background(), &pb.SubnetworkIdentificationRequest{
-		Id: id,
-	})
+func Get(client pb.SubnetworkServiceClient, identifier *common.Identifier) error {
+	subnetwork, err := client.Get(context.Background(), identification(identifier, 0))
 	if err != nil {
 		return err
 	}
@@ -67,20 +69,18 @@ func Get(client pb.SubnetworkServiceClient, id uint32) error {
 	return nil
 }
 
-func Delete(client pb.SubnetworkServiceClient, id uint32) error {
-	_, err := client.Delete(context.Background(), &pb.SubnetworkIdentificationRequest{
-		Id: id,
-	})
+func Delete(client pb.SubnetworkServiceClient, identifier *common.Identifier, resourceVersion uint64) error {
+	_, err := client.Delete(context.Background(), identification(identifier, resourceVersion))
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully deleted %d\n", id)
+	fmt.Printf("Successfully deleted %s\n", identifier)
 
 	return nil
 }
 
-func Create(client pb.SubnetworkServiceClient, yamlBytes []byte) error {
+func Create(client pb.SubnetworkServiceClient, yamlBytes []byte, idempotencyKey string) error {
 	input := &subnetworkCreation{}
 	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
 		return err
@@ -103,9 +103,12 @@ func Create(client pb.SubnetworkServiceClient, yamlBytes []byte) error {
 	prefixLength, _ := ipNet.Mask.Size()
 
 	req := &pb.SubnetworkCreationRequest{
-		NetworkId:    input.NetworkId,
-		Address:      address,
-		PrefixLength: uint32(prefixLength),
+		NetworkId:      input.NetworkId,
+		Address:        address,
+		PrefixLength:   uint32(prefixLength),
+		Labels:         input.Labels,
+		Name:           common.ValueOrZero(input.Name),
+		IdempotencyKey: idempotencyKey,
 	}
 
 	resp, err := client.Create(context.Background(), req)
@@ -118,7 +121,7 @@ func Create(client pb.SubnetworkServiceClient, yamlBytes []byte) error {
 	return nil
 }
 
-func Update(client pb.SubnetworkServiceClient, id uint32, yamlBytes []byte) error {
+func Update(client pb.SubnetworkServiceClient, identifier *common.Identifier, yamlBytes []byte, resourceVersion uint64) error {
 	input := &subnetworkCreation{}
 	if err := yaml.Unmarshal(yamlBytes, &input); err != nil {
 		return err
@@ -140,14 +143,32 @@ func Update(client pb.SubnetworkServiceClient, id uint32, yamlBytes []byte) erro
 	address := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
 	prefixLength, _ := ipNet.Mask.Size()
 
+	current, err := client.Get(context.Background(), identification(identifier, 0))
+	if err != nil {
+		return err
+	}
+
+	update := &pb.SubnetworkCreationRequest{
+		Address:      address,
+		PrefixLength: uint32(prefixLength),
+		Labels:       current.Labels,
+		Name:         current.Name,
+	}
+	if input.Labels != nil {
+		update.Labels = input.Labels
+	}
+	if input.Name != nil {
+		update.Name = *input.Name
+	}
+
+	// The kept values were read just now, so the update must not overwrite a change made since then
+	if resourceVersion == 0 {
+		resourceVersion = current.ResourceVersion
+	}
+
 	req := &pb.SubnetworkUpdateRequest{
-		Identification: &pb.SubnetworkIdentificationRequest{
-			Id: id,
-		},
-		Update: &pb.SubnetworkCreationRequest{
-			Address:      address,
-			PrefixLength: uint32(prefixLength),
-		},
+		Identification: identification(identifier, resourceVersion),
+		Update:         update,
 	}
 
 	resp, err := client.Update(context.Background(), req)
@@ -159,3 +180,45 @@ func Update(client pb.SubnetworkServiceClient, id uint32, yamlBytes []byte) erro
 
 	return nil
 }
+
+func Watch(client pb.SubnetworkServiceClient, sinceRevision uint64) error {
+	stream, err := client.Watch(context.Background(), &pb.WatchRequest{
+		SinceRevision: common.OptionalRevision(sinceRevision),
+	})
+	if err != nil {
+		return err
+	}
+
+	// A minimum cell width keeps the columns of separately flushed events roughly aligned
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "revision\tevent\tid\tname\tnetwork_id\tcidr\tversion\tlabels\n")
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%s\t", event.Revision, event.Type)
+		print(w, event.Subnetwork)
+		// Events arrive indefinitely, so each one is shown as soon as it is received
+		w.Flush()
+	}
+
+	return nil
+}
+
+func identification(identifier *common.Identifier, resourceVersion uint64) *pb.SubnetworkIdentificationRequest {
+	req := &pb.SubnetworkIdentificationRequest{
+		ResourceVersion: common.OptionalResourceVersion(resourceVersion),
+	}
+
+	if identifier.Name != "" {
+		req.Identifier = &pb.SubnetworkIdentificationRequest_Name{Name: identifier.Name}
+	} else {
+		req.Identifier = &pb.SubnetworkIdentificationRequest_Id{Id: identifier.Id}
+	}
+
+	return req
+}
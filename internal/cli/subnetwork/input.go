@@ -9,9 +9,12 @@ import (
 
 var _ inputs.Input = &subnetworkCreation{}
 
+// Name and labels are nil when they are left out, so that an update keeps their current values
 type subnetworkCreation struct {
-	NetworkId uint32 `yaml:"networkId"`
-	Cidr      string `yaml:"cidr"`
+	Name      *string           `yaml:"name"`
+	NetworkId uint32            `yaml:"networkId"`
+	Cidr      string            `yaml:"cidr"`
+	Labels    map[string]string `yaml:"labels"`
 }
 
 func (i *subnetworkCreation) Validate() error {
@@ -29,6 +29,7 @@ type containerDataSource struct {
 
 type containerDataSourceModel struct {
 	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
 	SubnetworkId types.String `tfsdk:"subnetwork_id"`
 	Ip           types.String `tfsdk:"ip"`
 	Image        types.String `tfsdk:"image"`
@@ -38,6 +39,7 @@ type containerDataSourceModel struct {
 	Env          types.Map    `tfsdk:"env"`
 	StartedAt    types.String `tfsdk:"started_at"`
 	CreatedAt    types.String `tfsdk:"created_at"`
+	Labels       types.Map    `tfsdk:"labels"`
 }
 
 func (d *containerDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -68,6 +70,9 @@ func (d *containerDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 			"id": schema.StringAttribute{
 				Required: true,
 			},
+			"name": schema.StringAttribute{
+				Computed: true,
+			},
 			"subnetwork_id": schema.StringAttribute{
 				Description: "The subnetwork this container is attached to.",
 				Computed:    true,
@@ -106,6 +111,10 @@ func (d *containerDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 			"created_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -129,15 +138,12 @@ func (d *containerDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	clientReq := &pb.ContainerIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.ContainerIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	container, err := d.client.Get(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading container",
-			"Could not read container id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading container", "Could not read container id "+state.Id.ValueString(), err)
 		return
 	}
 
@@ -149,12 +155,18 @@ func (d *containerDataSource) Read(ctx context.Context, req datasource.ReadReque
 		container.PrefixLength)
 
 	state.Id = types.StringValue(strconv.FormatInt(int64(container.Id), 10))
+	state.Name = nameValue(container.Name)
 	state.SubnetworkId = types.StringValue(strconv.FormatInt(int64(container.SubnetworkId), 10))
 	state.Ip = types.StringValue(cidr)
 	state.Image = types.StringValue(container.Image)
 	state.Status = types.StringValue(container.Status)
 	state.StartedAt = types.StringValue(container.StartedAt.AsTime().Format(time.RFC3339))
 	state.CreatedAt = types.StringValue(container.CreatedAt.AsTime().Format(time.RFC3339))
+	state.Labels, diags = types.MapValueFrom(ctx, types.StringType, container.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	state.Entrypoint, diags = types.ListValueFrom(ctx, types.StringType, container.Entrypoint)
 	resp.Diagnostics.Append(diags...)
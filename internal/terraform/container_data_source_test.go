@@ -44,7 +44,7 @@ func TestAccContainerDataSource(t *testing.T) {
 
 	t.Cleanup(func() {
 		containerDeleteReq := &pb.ContainerIdentificationRequest{
-			Id: container.Id,
+			Identifier: &pb.ContainerIdentificationRequest_Id{Id: container.Id},
 		}
 		_, err = grpcClients.Container.Delete(context.Background(), containerDeleteReq)
 		if err != nil {
@@ -52,7 +52,7 @@ func TestAccContainerDataSource(t *testing.T) {
 		}
 
 		subnetworkDeleteReq := &pb.SubnetworkIdentificationRequest{
-			Id: subnetwork.Id,
+			Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: subnetwork.Id},
 		}
 
 		_, err = grpcClients.Subnetwork.Delete(context.Background(), subnetworkDeleteReq)
@@ -61,7 +61,7 @@ func TestAccContainerDataSource(t *testing.T) {
 		}
 
 		networkDeleteReq := &pb.NetworkIdentificationRequest{
-			Id: network.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: network.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), networkDeleteReq)
 		if err != nil {
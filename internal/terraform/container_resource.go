@@ -19,6 +19,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -37,17 +39,20 @@ type containerResource struct {
 }
 
 type containerResourceModel struct {
-	Id           types.String `tfsdk:"id"`
-	SubnetworkId types.String `tfsdk:"subnetwork_id"`
-	Ip           types.String `tfsdk:"ip"`
-	Image        types.String `tfsdk:"image"`
-	Status       types.String `tfsdk:"status"`
-	Entrypoint   types.List   `tfsdk:"entrypoint"`
-	Cmd          types.List   `tfsdk:"cmd"`
-	Env          types.Map    `tfsdk:"env"`
-	StartedAt    types.String `tfsdk:"started_at"`
-	CreatedAt    types.String `tfsdk:"created_at"`
-	UpdatedAt    types.String `tfsdk:"updated_at"`
+	Id              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	SubnetworkId    types.String `tfsdk:"subnetwork_id"`
+	Ip              types.String `tfsdk:"ip"`
+	Image           types.String `tfsdk:"image"`
+	Status          types.String `tfsdk:"status"`
+	Entrypoint      types.List   `tfsdk:"entrypoint"`
+	Cmd             types.List   `tfsdk:"cmd"`
+	Env             types.Map    `tfsdk:"env"`
+	StartedAt       types.String `tfsdk:"started_at"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+	ResourceVersion types.Int64  `tfsdk:"resource_version"`
+	Labels          types.Map    `tfsdk:"labels"`
 }
 
 func (r *containerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -81,6 +86,13 @@ func (r *containerResource) Schema(_ context.Context, req resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"name": schema.StringAttribute{
+				Description: "Unique name of the container, which can be used instead of its id. It is also used as the container's hostname.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"subnetwork_id": schema.StringAttribute{
 				Description: "The subnetwork this container is attached to.",
 				Required:    true,
@@ -150,6 +162,18 @@ func (r *containerResource) Schema(_ context.Context, req resource.SchemaRequest
 			"updated_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Description: "Key-value pairs used to organize resources, e.g. by team or environment. They can be used to filter resources when listing them. Changing them recreates the container.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"resource_version": schema.Int64Attribute{
+				Description: "Increases with every change to the container's specification, including starting and stopping it. Starting, stopping and deleting fail if the container was changed outside of Terraform since it was last read.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -207,20 +231,25 @@ func (r *containerResource) Create(ctx context.Context, req resource.CreateReque
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 
+	labels, diags := labelsFromValue(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	clientReq := &pb.ContainerCreationRequest{
+		Name:         plan.Name.ValueString(),
 		SubnetworkId: uint32(subnetworkId),
 		Image:        plan.Image.ValueString(),
 		Entrypoint:   entrypoint,
 		Cmd:          cmd,
 		Env:          env,
+		Labels:       labels,
 	}
 
 	container, err := r.client.Create(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating container",
-			"Could not create container, unexpected error: "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error creating container", "Could not create container", err)
 		return
 	}
 
@@ -256,15 +285,17 @@ func (r *containerResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	clientReq := &pb.ContainerIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.ContainerIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	container, err := r.client.Get(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		// Deleted outside of Terraform, so it has to be created again
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading container",
-			"Could not read container id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading container", "Could not read container id "+state.Id.ValueString(), err)
 		return
 	}
 
@@ -307,7 +338,8 @@ func (r *containerResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	idReq := &pb.ContainerIdentificationRequest{
-		Id: uint32(id),
+		Identifier:      &pb.ContainerIdentificationRequest_Id{Id: uint32(id)},
+		ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 	}
 
 	var container *pb.Container
@@ -315,19 +347,13 @@ func (r *containerResource) Update(ctx context.Context, req resource.UpdateReque
 	case state.Status.ValueString() == "stopped" && plan.Status.ValueString() == "running":
 		container, err = r.client.Start(ctx, idReq)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error updating container",
-				"Could not start the container, unexpected error: "+err.Error(),
-			)
+			addApiError(&resp.Diagnostics, "Error updating container", "Could not start the container", err)
 			return
 		}
 	case state.Status.ValueString() == "running" && plan.Status.ValueString() == "stopped":
 		container, err = r.client.Stop(ctx, idReq)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error updating container",
-				"Could not stop the container, unexpected error: "+err.Error(),
-			)
+			addApiError(&resp.Diagnostics, "Error updating container", "Could not stop the container", err)
 			return
 		}
 	default:
@@ -370,15 +396,16 @@ func (r *containerResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 
 	clientReq := &pb.ContainerIdentificationRequest{
-		Id: uint32(id),
+		Identifier:      &pb.ContainerIdentificationRequest_Id{Id: uint32(id)},
+		ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 	}
 
 	_, err = r.client.Delete(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting container",
-			"Could not delete container id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error deleting container", "Could not delete container id "+state.Id.ValueString(), err)
 		return
 	}
 }
@@ -445,6 +472,7 @@ func (r *containerResource) populateFromResponse(ctx context.Context, model *con
 		response.PrefixLength)
 
 	model.Id = types.StringValue(strconv.FormatInt(int64(response.Id), 10))
+	model.Name = nameValue(response.Name)
 	model.SubnetworkId = types.StringValue(strconv.FormatInt(int64(response.SubnetworkId), 10))
 	model.Ip = types.StringValue(cidr)
 	model.Image = types.StringValue(response.Image)
@@ -452,6 +480,12 @@ func (r *containerResource) populateFromResponse(ctx context.Context, model *con
 	model.StartedAt = types.StringValue(response.StartedAt.AsTime().Format(time.RFC3339))
 	model.CreatedAt = types.StringValue(response.CreatedAt.AsTime().Format(time.RFC3339))
 	model.UpdatedAt = types.StringValue(time.Now().Format(time.RFC3339))
+	model.ResourceVersion = types.Int64Value(int64(response.ResourceVersion))
+
+	model.Labels, diags = labelsValue(ctx, response.Labels, model.Labels)
+	if diags.HasError() {
+		return diags
+	}
 
 	model.Entrypoint, diags = types.ListValueFrom(ctx, types.StringType, response.Entrypoint)
 	if diags.HasError() {
@@ -35,7 +35,7 @@ func TestAccContainerResource(t *testing.T) {
 
 	t.Cleanup(func() {
 		subnetworkDeleteReq := &pb.SubnetworkIdentificationRequest{
-			Id: subnetwork.Id,
+			Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: subnetwork.Id},
 		}
 
 		_, err = grpcClients.Subnetwork.Delete(context.Background(), subnetworkDeleteReq)
@@ -44,7 +44,7 @@ func TestAccContainerResource(t *testing.T) {
 		}
 
 		networkDeleteReq := &pb.NetworkIdentificationRequest{
-			Id: network.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: network.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), networkDeleteReq)
 		if err != nil {
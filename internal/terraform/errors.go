@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Request fields whose attribute is named differently
+var attributeOfField = map[string]string{
+	"address":       "cidr",
+	"prefix_length": "cidr",
+}
+
+// Adds an error for a failed API call. Rejected request fields are reported on their attributes, other errors are explained by their status code.
+func addApiError(diags *diag.Diagnostics, summary string, detail string, err error) {
+	s := status.Convert(err)
+	message := detail + ": " + s.Message()
+
+	switch s.Code() {
+	case codes.Aborted:
+		message += "\n\nThe resource was changed outside of Terraform since it was last read. Refresh the state and apply again."
+	case codes.AlreadyExists:
+		message += "\n\nAnother resource is already using this value."
+	case codes.FailedPrecondition:
+		message += "\n\nThe resource is not in a state that allows this operation, e.g. other resources still depend on it."
+	case codes.ResourceExhausted:
+		message += "\n\nThe API has run out of capacity for this resource, e.g. there are no free IPs left in the subnetwork, or the project has reached its quota."
+	case codes.PermissionDenied:
+		message += "\n\nThe provider's identity is not allowed to do this, check the roles granted to it in the API's access policy."
+	case codes.Unavailable:
+		message += "\n\nThe API could not be reached, check that it is running and that the provider's host is correct."
+	}
+
+	reported := false
+	for _, detail := range s.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, violation := range badRequest.FieldViolations {
+			attribute := violation.Field
+			if mapped, ok := attributeOfField[attribute]; ok {
+				attribute = mapped
+			}
+			diags.AddAttributeError(path.Root(attribute), summary, message)
+			reported = true
+		}
+	}
+
+	if !reported {
+		diags.AddError(summary, message)
+	}
+}
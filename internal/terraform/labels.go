@@ -0,0 +1,24 @@
+package terraform
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Maps the labels returned by the API to a Terraform value.
+// A resource without labels keeps an unset attribute null, so that it does not show up as a difference.
+func labelsValue(ctx context.Context, labels map[string]string, current types.Map) (types.Map, diag.Diagnostics) {
+	if len(labels) == 0 && (current.IsNull() || current.IsUnknown()) {
+		return types.MapNull(types.StringType), nil
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, labels)
+}
+
+func labelsFromValue(ctx context.Context, value types.Map) (map[string]string, diag.Diagnostics) {
+	labels := make(map[string]string)
+	diags := value.ElementsAs(ctx, &labels, false)
+	return labels, diags
+}
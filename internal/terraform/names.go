@@ -0,0 +1,12 @@
+package terraform
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+// Maps the name returned by the API to a Terraform value, an unnamed resource keeps the attribute null
+func nameValue(name string) types.String {
+	if name == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(name)
+}
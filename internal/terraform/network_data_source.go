@@ -28,8 +28,10 @@ type networkDataSource struct {
 
 type networkDataSourceModel struct {
 	Id             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
 	InternetAccess types.Bool   `tfsdk:"internet_access"`
 	CreatedAt      types.String `tfsdk:"created_at"`
+	Labels         types.Map    `tfsdk:"labels"`
 }
 
 func (d *networkDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -60,6 +62,9 @@ func (d *networkDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 			"id": schema.StringAttribute{
 				Required: true,
 			},
+			"name": schema.StringAttribute{
+				Computed: true,
+			},
 			"internet_access": schema.BoolAttribute{
 				Description: "Whether the network allows devices on it to access the internet.",
 				Computed:    true,
@@ -67,6 +72,10 @@ func (d *networkDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 			"created_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -90,21 +99,24 @@ func (d *networkDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	clientReq := &pb.NetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.NetworkIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	network, err := d.client.Get(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading network",
-			"Could not read network id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading network", "Could not read network id "+state.Id.ValueString(), err)
 		return
 	}
 
 	state.Id = types.StringValue(strconv.FormatInt(int64(network.Id), 10))
+	state.Name = nameValue(network.Name)
 	state.InternetAccess = types.BoolValue(network.InternetAccess)
 	state.CreatedAt = types.StringValue(network.CreatedAt.AsTime().Format(time.RFC3339))
+	state.Labels, diags = types.MapValueFrom(ctx, types.StringType, network.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
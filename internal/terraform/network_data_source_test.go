@@ -21,7 +21,7 @@ func TestAccNetworkDataSource(t *testing.T) {
 
 	t.Cleanup(func() {
 		deleteReq := &pb.NetworkIdentificationRequest{
-			Id: network.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: network.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), deleteReq)
 		if err != nil {
@@ -13,6 +13,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -30,10 +32,13 @@ type networkResource struct {
 }
 
 type networkResourceModel struct {
-	Id             types.String `tfsdk:"id"`
-	InternetAccess types.Bool   `tfsdk:"internet_access"`
-	CreatedAt      types.String `tfsdk:"created_at"`
-	UpdatedAt      types.String `tfsdk:"updated_at"`
+	Id              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	InternetAccess  types.Bool   `tfsdk:"internet_access"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+	ResourceVersion types.Int64  `tfsdk:"resource_version"`
+	Labels          types.Map    `tfsdk:"labels"`
 }
 
 func (r *networkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -67,6 +72,10 @@ func (r *networkResource) Schema(_ context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"name": schema.StringAttribute{
+				Description: "Unique name of the network, which can be used instead of its id.",
+				Optional:    true,
+			},
 			"internet_access": schema.BoolAttribute{
 				Description: "Whether the network allows devices on it to access the internet.",
 				Required:    true,
@@ -80,6 +89,15 @@ func (r *networkResource) Schema(_ context.Context, req resource.SchemaRequest,
 			"updated_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Description: "Key-value pairs used to organize resources, e.g. by team or environment. They can be used to filter resources when listing them.",
+				Optional:    true,
+			},
+			"resource_version": schema.Int64Attribute{
+				Description: "Increases with every change to the network. Updates and deletes fail if the network was changed outside of Terraform since it was last read.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -92,23 +110,35 @@ func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	labels, diags := labelsFromValue(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	clientReq := &pb.NetworkCreationRequest{
+		Name:           plan.Name.ValueString(),
 		InternetAccess: plan.InternetAccess.ValueBool(),
+		Labels:         labels,
 	}
 
 	network, err := r.client.Create(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating network",
-			"Could not create network, unexpected error: "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error creating network", "Could not create network", err)
 		return
 	}
 
 	plan.Id = types.StringValue(strconv.FormatInt(int64(network.Id), 10))
+	plan.Name = nameValue(network.Name)
 	plan.InternetAccess = types.BoolValue(network.InternetAccess)
 	plan.CreatedAt = types.StringValue(network.CreatedAt.AsTime().Format(time.RFC3339))
 	plan.UpdatedAt = types.StringValue(time.Now().Format(time.RFC3339))
+	plan.ResourceVersion = types.Int64Value(int64(network.ResourceVersion))
+	plan.Labels, diags = labelsValue(ctx, network.Labels, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -136,21 +166,30 @@ func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	clientReq := &pb.NetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.NetworkIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	network, err := r.client.Get(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		// Deleted outside of Terraform, so it has to be created again
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading network",
-			"Could not read network id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading network", "Could not read network id "+state.Id.ValueString(), err)
 		return
 	}
 
 	state.Id = types.StringValue(strconv.FormatInt(int64(network.Id), 10))
+	state.Name = nameValue(network.Name)
 	state.InternetAccess = types.BoolValue(network.InternetAccess)
 	state.CreatedAt = types.StringValue(network.CreatedAt.AsTime().Format(time.RFC3339))
+	state.ResourceVersion = types.Int64Value(int64(network.ResourceVersion))
+	state.Labels, diags = labelsValue(ctx, network.Labels, state.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -167,6 +206,13 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	var state networkResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	id, err := strconv.ParseInt(plan.Id.ValueString(), 10, 32)
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
@@ -177,27 +223,40 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	labels, diags := labelsFromValue(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	clientReq := &pb.NetworkUpdateRequest{
 		Identification: &pb.NetworkIdentificationRequest{
-			Id: uint32(id),
+			Identifier:      &pb.NetworkIdentificationRequest_Id{Id: uint32(id)},
+			ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 		},
 		Update: &pb.NetworkCreationRequest{
+			Name:           plan.Name.ValueString(),
 			InternetAccess: plan.InternetAccess.ValueBool(),
+			Labels:         labels,
 		},
 	}
 
 	network, err := r.client.Update(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating network",
-			"Could not update network, unexpected error: "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error updating network", "Could not update network", err)
 		return
 	}
 
 	plan.Id = types.StringValue(strconv.FormatInt(int64(network.Id), 10))
+	plan.Name = nameValue(network.Name)
 	plan.InternetAccess = types.BoolValue(network.InternetAccess)
 	plan.UpdatedAt = types.StringValue(time.Now().Format(time.RFC3339))
+	plan.ResourceVersion = types.Int64Value(int64(network.ResourceVersion))
+	plan.Labels, diags = labelsValue(ctx, network.Labels, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -225,15 +284,16 @@ func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 
 	clientReq := &pb.NetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier:      &pb.NetworkIdentificationRequest_Id{Id: uint32(id)},
+		ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 	}
 
 	_, err = r.client.Delete(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting network",
-			"Could not delete network id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error deleting network", "Could not delete network id "+state.Id.ValueString(), err)
 		return
 	}
 }
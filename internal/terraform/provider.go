@@ -3,7 +3,9 @@ package terraform
 import (
 	"context"
 	"os"
+	"strconv"
 
+	"github.com/BenasB/bx2cloud/internal/api/auth"
 	"github.com/BenasB/bx2cloud/internal/api/pb"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -12,11 +14,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type bx2cloudProviderModel struct {
-	Host types.String `tfsdk:"host"`
+	Host     types.String `tfsdk:"host"`
+	Tls      types.Bool   `tfsdk:"tls"`
+	CaFile   types.String `tfsdk:"ca_file"`
+	CertFile types.String `tfsdk:"cert_file"`
+	KeyFile  types.String `tfsdk:"key_file"`
+	Token    types.String `tfsdk:"token"`
+	Project  types.String `tfsdk:"project"`
 }
 
 type Bx2cloudClients struct {
@@ -52,6 +59,31 @@ func (p *bx2cloudProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Description: "The host and port of the bx2cloud API. May also be provided via BX2CLOUD_HOST environment variable.",
 				Required:    true,
 			},
+			"tls": schema.BoolAttribute{
+				Description: "Whether to use TLS, verifying the bx2cloud API's certificate with the system CAs unless ca_file is set. May also be provided via BX2CLOUD_TLS environment variable.",
+				Optional:    true,
+			},
+			"ca_file": schema.StringAttribute{
+				Description: "Path to the PEM CA certificate that the bx2cloud API's certificate is verified with, setting it enables TLS. May also be provided via BX2CLOUD_CA_FILE environment variable.",
+				Optional:    true,
+			},
+			"cert_file": schema.StringAttribute{
+				Description: "Path to the PEM client certificate, for APIs that verify client certificates. May also be provided via BX2CLOUD_CERT_FILE environment variable.",
+				Optional:    true,
+			},
+			"key_file": schema.StringAttribute{
+				Description: "Path to the PEM private key of the client certificate. May also be provided via BX2CLOUD_KEY_FILE environment variable.",
+				Optional:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "Bearer token to authenticate with, only sent over TLS or a Unix socket. May also be provided via BX2CLOUD_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"project": schema.StringAttribute{
+				Description: "Name of the project that resources are managed in, defaults to the project the token is bound to or \"default\". May also be provided via BX2CLOUD_PROJECT environment variable.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -76,6 +108,32 @@ func (p *bx2cloudProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	for name, value := range map[string]types.String{
+		"ca_file":   config.CaFile,
+		"cert_file": config.CertFile,
+		"key_file":  config.KeyFile,
+		"token":     config.Token,
+		"project":   config.Project,
+	} {
+		if value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Unknown bx2cloud API Client Setting",
+				"The provider cannot create the bx2cloud API client as there is an unknown configuration value for "+name+". "+
+					"Either target apply the source of the value first, set the value statically in the configuration, or use the environment variable.",
+			)
+		}
+	}
+
+	if config.Tls.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("tls"),
+			"Unknown bx2cloud API Client Setting",
+			"The provider cannot create the bx2cloud API client as there is an unknown configuration value for tls. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -100,7 +158,24 @@ func (p *bx2cloudProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	options := &auth.ClientOptions{
+		TLS:      boolOrEnv(config.Tls, "BX2CLOUD_TLS"),
+		CAFile:   valueOrEnv(config.CaFile, "BX2CLOUD_CA_FILE"),
+		CertFile: valueOrEnv(config.CertFile, "BX2CLOUD_CERT_FILE"),
+		KeyFile:  valueOrEnv(config.KeyFile, "BX2CLOUD_KEY_FILE"),
+		Token:    valueOrEnv(config.Token, "BX2CLOUD_TOKEN"),
+		Project:  valueOrEnv(config.Project, "BX2CLOUD_PROJECT"),
+	}
+	opts, err := options.DialOptions(host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid bx2cloud API Client Settings",
+			"The provider cannot create the bx2cloud API client with the given TLS or token settings.\n\n"+
+				"bx2cloud client Error: "+err.Error(),
+		)
+		return
+	}
+
 	conn, err := grpc.NewClient(host, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -137,3 +212,21 @@ func (p *bx2cloudProvider) Resources(ctx context.Context) []func() resource.Reso
 		NewContainerResource,
 	}
 }
+
+func valueOrEnv(value types.String, env string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+
+	return os.Getenv(env)
+}
+
+// Unset and invalid environment variables are false
+func boolOrEnv(value types.Bool, env string) bool {
+	if !value.IsNull() {
+		return value.ValueBool()
+	}
+
+	b, _ := strconv.ParseBool(os.Getenv(env))
+	return b
+}
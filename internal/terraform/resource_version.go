@@ -0,0 +1,14 @@
+package terraform
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+// Maps the resource version known to Terraform to the expected version sent with updates and deletes.
+// Unknown versions (e.g. state written by an older provider) skip the check instead of failing.
+func expectedResourceVersion(resourceVersion types.Int64) *uint64 {
+	if resourceVersion.IsNull() || resourceVersion.IsUnknown() || resourceVersion.ValueInt64() <= 0 {
+		return nil
+	}
+
+	v := uint64(resourceVersion.ValueInt64())
+	return &v
+}
@@ -28,9 +28,11 @@ type subnetworkDataSource struct {
 
 type subnetworkDataSourceModel struct {
 	Id        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
 	NetworkId types.String `tfsdk:"network_id"`
 	Cidr      types.String `tfsdk:"cidr"`
 	CreatedAt types.String `tfsdk:"created_at"`
+	Labels    types.Map    `tfsdk:"labels"`
 }
 
 func (d *subnetworkDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -61,6 +63,9 @@ func (d *subnetworkDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 			"id": schema.StringAttribute{
 				Required: true,
 			},
+			"name": schema.StringAttribute{
+				Computed: true,
+			},
 			"network_id": schema.StringAttribute{
 				Description: "The network this subnetwork is considered a part of.",
 				Computed:    true,
@@ -72,6 +77,10 @@ func (d *subnetworkDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 			"created_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -95,15 +104,12 @@ func (d *subnetworkDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	}
 
 	clientReq := &pb.SubnetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	subnetwork, err := d.client.Get(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading subnetwork",
-			"Could not read subnetwork id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading subnetwork", "Could not read subnetwork id "+state.Id.ValueString(), err)
 		return
 	}
 
@@ -115,9 +121,15 @@ func (d *subnetworkDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		subnetwork.PrefixLength)
 
 	state.Id = types.StringValue(strconv.FormatInt(int64(subnetwork.Id), 10))
+	state.Name = nameValue(subnetwork.Name)
 	state.NetworkId = types.StringValue(strconv.FormatInt(int64(subnetwork.NetworkId), 10))
 	state.Cidr = types.StringValue(cidr)
 	state.CreatedAt = types.StringValue(subnetwork.CreatedAt.AsTime().Format(time.RFC3339))
+	state.Labels, diags = types.MapValueFrom(ctx, types.StringType, subnetwork.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
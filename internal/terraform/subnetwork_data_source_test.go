@@ -32,7 +32,7 @@ func TestAccSubnetworkDataSource(t *testing.T) {
 
 	t.Cleanup(func() {
 		subnetworkDeleteReq := &pb.SubnetworkIdentificationRequest{
-			Id: subnetwork.Id,
+			Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: subnetwork.Id},
 		}
 		_, err = grpcClients.Subnetwork.Delete(context.Background(), subnetworkDeleteReq)
 		if err != nil {
@@ -40,7 +40,7 @@ func TestAccSubnetworkDataSource(t *testing.T) {
 		}
 
 		networkDeleteReq := &pb.NetworkIdentificationRequest{
-			Id: network.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: network.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), networkDeleteReq)
 		if err != nil {
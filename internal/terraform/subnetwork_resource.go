@@ -14,6 +14,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -31,11 +33,14 @@ type subnetworkResource struct {
 }
 
 type subnetworkResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	NetworkId types.String `tfsdk:"network_id"`
-	Cidr      types.String `tfsdk:"cidr"`
-	CreatedAt types.String `tfsdk:"created_at"`
-	UpdatedAt types.String `tfsdk:"updated_at"`
+	Id              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	NetworkId       types.String `tfsdk:"network_id"`
+	Cidr            types.String `tfsdk:"cidr"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+	ResourceVersion types.Int64  `tfsdk:"resource_version"`
+	Labels          types.Map    `tfsdk:"labels"`
 }
 
 func (r *subnetworkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -69,6 +74,10 @@ func (r *subnetworkResource) Schema(_ context.Context, req resource.SchemaReques
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"name": schema.StringAttribute{
+				Description: "Unique name of the subnetwork, which can be used instead of its id.",
+				Optional:    true,
+			},
 			"network_id": schema.StringAttribute{
 				Description: "The network this subnetwork is considered a part of.",
 				Required:    true,
@@ -89,6 +98,15 @@ func (r *subnetworkResource) Schema(_ context.Context, req resource.SchemaReques
 			"updated_at": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Description: "Key-value pairs used to organize resources, e.g. by team or environment. They can be used to filter resources when listing them.",
+				Optional:    true,
+			},
+			"resource_version": schema.Int64Attribute{
+				Description: "Increases with every change to the subnetwork. Updates and deletes fail if the subnetwork was changed outside of Terraform since it was last read.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -133,18 +151,23 @@ func (r *subnetworkResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	labels, diags := labelsFromValue(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	clientReq := &pb.SubnetworkCreationRequest{
+		Name:         plan.Name.ValueString(),
 		NetworkId:    uint32(networkId),
 		Address:      address,
 		PrefixLength: uint32(prefixLength),
+		Labels:       labels,
 	}
 
 	subnetwork, err := r.client.Create(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating subnetwork",
-			"Could not create subnetwork, unexpected error: "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error creating subnetwork", "Could not create subnetwork", err)
 		return
 	}
 
@@ -156,10 +179,17 @@ func (r *subnetworkResource) Create(ctx context.Context, req resource.CreateRequ
 		subnetwork.PrefixLength)
 
 	plan.Id = types.StringValue(strconv.FormatInt(int64(subnetwork.Id), 10))
+	plan.Name = nameValue(subnetwork.Name)
 	plan.NetworkId = types.StringValue(strconv.FormatInt(int64(subnetwork.NetworkId), 10))
 	plan.Cidr = types.StringValue(cidr)
 	plan.CreatedAt = types.StringValue(subnetwork.CreatedAt.AsTime().Format(time.RFC3339))
 	plan.UpdatedAt = types.StringValue(time.Now().Format(time.RFC3339))
+	plan.ResourceVersion = types.Int64Value(int64(subnetwork.ResourceVersion))
+	plan.Labels, diags = labelsValue(ctx, subnetwork.Labels, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -187,15 +217,17 @@ func (r *subnetworkResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	clientReq := &pb.SubnetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier: &pb.SubnetworkIdentificationRequest_Id{Id: uint32(id)},
 	}
 
 	subnetwork, err := r.client.Get(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		// Deleted outside of Terraform, so it has to be created again
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading subnetwork",
-			"Could not read subnetwork id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error reading subnetwork", "Could not read subnetwork id "+state.Id.ValueString(), err)
 		return
 	}
 
@@ -207,9 +239,16 @@ func (r *subnetworkResource) Read(ctx context.Context, req resource.ReadRequest,
 		subnetwork.PrefixLength)
 
 	state.Id = types.StringValue(strconv.FormatInt(int64(subnetwork.Id), 10))
+	state.Name = nameValue(subnetwork.Name)
 	state.NetworkId = types.StringValue(strconv.FormatInt(int64(subnetwork.NetworkId), 10))
 	state.Cidr = types.StringValue(cidr)
 	state.CreatedAt = types.StringValue(subnetwork.CreatedAt.AsTime().Format(time.RFC3339))
+	state.ResourceVersion = types.Int64Value(int64(subnetwork.ResourceVersion))
+	state.Labels, diags = labelsValue(ctx, subnetwork.Labels, state.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -226,6 +265,13 @@ func (r *subnetworkResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	var state subnetworkResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	_, ipNet, err := net.ParseCIDR(plan.Cidr.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
@@ -258,22 +304,28 @@ func (r *subnetworkResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	labels, diags := labelsFromValue(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	clientReq := &pb.SubnetworkUpdateRequest{
 		Identification: &pb.SubnetworkIdentificationRequest{
-			Id: uint32(id),
+			Identifier:      &pb.SubnetworkIdentificationRequest_Id{Id: uint32(id)},
+			ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 		},
 		Update: &pb.SubnetworkCreationRequest{
+			Name:         plan.Name.ValueString(),
 			Address:      address,
 			PrefixLength: uint32(prefixLength),
+			Labels:       labels,
 		},
 	}
 
 	subnetwork, err := r.client.Update(ctx, clientReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating subnetwork",
-			"Could not update subnetwork, unexpected error: "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error updating subnetwork", "Could not update subnetwork", err)
 		return
 	}
 
@@ -285,9 +337,16 @@ func (r *subnetworkResource) Update(ctx context.Context, req resource.UpdateRequ
 		subnetwork.PrefixLength)
 
 	plan.Id = types.StringValue(strconv.FormatInt(int64(subnetwork.Id), 10))
+	plan.Name = nameValue(subnetwork.Name)
 	plan.NetworkId = types.StringValue(strconv.FormatInt(int64(subnetwork.NetworkId), 10))
 	plan.Cidr = types.StringValue(cidr)
 	plan.UpdatedAt = types.StringValue(time.Now().Format(time.RFC3339))
+	plan.ResourceVersion = types.Int64Value(int64(subnetwork.ResourceVersion))
+	plan.Labels, diags = labelsValue(ctx, subnetwork.Labels, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -315,15 +374,16 @@ func (r *subnetworkResource) Delete(ctx context.Context, req resource.DeleteRequ
 	}
 
 	clientReq := &pb.SubnetworkIdentificationRequest{
-		Id: uint32(id),
+		Identifier:      &pb.SubnetworkIdentificationRequest_Id{Id: uint32(id)},
+		ResourceVersion: expectedResourceVersion(state.ResourceVersion),
 	}
 
 	_, err = r.client.Delete(ctx, clientReq)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting subnetwork",
-			"Could not delete subnetwork id "+state.Id.ValueString()+": "+err.Error(),
-		)
+		addApiError(&resp.Diagnostics, "Error deleting subnetwork", "Could not delete subnetwork id "+state.Id.ValueString(), err)
 		return
 	}
 }
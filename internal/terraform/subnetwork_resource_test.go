@@ -31,14 +31,14 @@ func TestAccSubnetworkResource(t *testing.T) {
 
 	t.Cleanup(func() {
 		networkOneDeleteReq := &pb.NetworkIdentificationRequest{
-			Id: networkOne.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: networkOne.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), networkOneDeleteReq)
 		if err != nil {
 			t.Fatalf("Failed to delete network '%d' after running the terraform test: %v", networkOne.Id, err)
 		}
 		networkTwoDeleteReq := &pb.NetworkIdentificationRequest{
-			Id: networkTwo.Id,
+			Identifier: &pb.NetworkIdentificationRequest_Id{Id: networkTwo.Id},
 		}
 		_, err = grpcClients.Network.Delete(context.Background(), networkTwoDeleteReq)
 		if err != nil {